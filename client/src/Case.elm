@@ -1,10 +1,13 @@
-module Case exposing (Art(..), Model, artToString, caseDecoder, caseEncoder, defaultArt, stringToArt)
+module Case exposing (Model, Rolle, caseDecoder, caseEncoder, rollenDecoder)
 
 import Json.Decode as JD
 import Json.Decode.Pipeline as JP
 import Json.Encode as JE
 
 
+{-| One case. Art is the main role of the lawyer in the case, rollen are
+further roles. Both contain names from the catalog of roles of the server.
+-}
 type alias Model =
     { rubrum : String
     , az : String
@@ -12,63 +15,21 @@ type alias Model =
     , beginn : String
     , ende : String
     , gegenstand : String
-    , art : Art
+    , art : String
+    , rollen : List String
     , beschreibung : String
     , stand : String
     }
 
 
-{-| One of the possible roles of the lawyer in a criminal law case.
--}
-type Art
-    = Verteidiger
-    | Nebenklaeger
-    | Zeugenbeistand
-    | Adhaesionsklaeger
-
-
-defaultArt : Art
-defaultArt =
-    Verteidiger
-
-
-{-| Converts the custom model type to human readable string.
--}
-artToString : Art -> String
-artToString a =
-    case a of
-        Verteidiger ->
-            "Verteidiger"
-
-        Nebenklaeger ->
-            "Nebenkläger"
-
-        Zeugenbeistand ->
-            "Zeugenbeistand"
-
-        Adhaesionsklaeger ->
-            "Adhäsionskläger"
-
-
-{-| Converts a string to the corresponding Art value. In case of missmatch it
-returns the default value.
+{-| One of the possible roles of the lawyer in a criminal law case. The
+catalog of roles is provided by the server so that it is defined in one place
+only.
 -}
-stringToArt : String -> Art
-stringToArt s =
-    if s == "Verteidiger" then
-        Verteidiger
-
-    else if s == "Nebenkläger" then
-        Nebenklaeger
-
-    else if s == "Zeugenbeistand" then
-        Zeugenbeistand
-
-    else if s == "Adhäsionskläger" then
-        Adhaesionsklaeger
-
-    else
-        defaultArt
+type alias Rolle =
+    { name : String
+    , beschreibung : String
+    }
 
 
 caseDecoder : JD.Decoder Model
@@ -80,17 +41,12 @@ caseDecoder =
         |> JP.required "Beginn" JD.string
         |> JP.required "Ende" JD.string
         |> JP.required "Gegenstand" JD.string
-        |> JP.required "Art" artDecoder
+        |> JP.required "Art" JD.string
+        |> JP.optional "Rollen" (JD.list JD.string) []
         |> JP.required "Beschreibung" JD.string
         |> JP.required "Stand" JD.string
 
 
-artDecoder : JD.Decoder Art
-artDecoder =
-    JD.string
-        |> JD.andThen (\s -> stringToArt s |> JD.succeed)
-
-
 caseEncoder : Model -> JE.Value
 caseEncoder m =
     JE.object
@@ -100,7 +56,19 @@ caseEncoder m =
         , ( "Beginn", JE.string m.beginn )
         , ( "Ende", JE.string m.ende )
         , ( "Gegenstand", JE.string m.gegenstand )
-        , ( "Art", JE.string <| artToString <| m.art )
+        , ( "Art", JE.string m.art )
+        , ( "Rollen", JE.list JE.string m.rollen )
         , ( "Beschreibung", JE.string m.beschreibung )
         , ( "Stand", JE.string m.stand )
         ]
+
+
+{-| Decodes the catalog of roles as returned by /api/case/roles.
+-}
+rollenDecoder : JD.Decoder (List Rolle)
+rollenDecoder =
+    JD.list
+        (JD.succeed Rolle
+            |> JP.required "Name" JD.string
+            |> JP.required "Beschreibung" JD.string
+        )
@@ -29,6 +29,7 @@ main =
 type alias Model =
     { newCaseForm : Maybe NewCaseForm.Model
     , caseTable : CaseTable.Model
+    , rollen : List Case.Rolle
     }
 
 
@@ -37,10 +38,17 @@ init _ =
     ( Model
         Nothing
         CaseTable.init
-    , Http.get
-        { url = "/api/case/retrieve"
-        , expect = Http.expectJson RetrieveCases (JD.dict Case.caseDecoder) -- TODO: Add decoder for validation that the key is transformable to int
-        }
+        []
+    , Cmd.batch
+        [ Http.get
+            { url = "/api/case/retrieve"
+            , expect = Http.expectJson RetrieveCases (JD.dict Case.caseDecoder) -- TODO: Add decoder for validation that the key is transformable to int
+            }
+        , Http.get
+            { url = "/api/case/roles"
+            , expect = Http.expectJson RetrieveRollen Case.rollenDecoder
+            }
+        ]
     )
 
 
@@ -50,6 +58,7 @@ init _ =
 
 type Msg
     = RetrieveCases RetrievedCases
+    | RetrieveRollen (Result Http.Error (List Case.Rolle))
     | OpenNewCaseForm
     | NewCaseFormMsg NewCaseForm.Msg
     | CaseTableMsg CaseTable.Msg
@@ -65,8 +74,17 @@ update msg model =
         RetrieveCases result ->
             ( { model | caseTable = loadInitialCaseTable result model.caseTable }, Cmd.none )
 
+        RetrieveRollen result ->
+            case result of
+                Ok rollen ->
+                    ( { model | rollen = rollen }, Cmd.none )
+
+                Err _ ->
+                    -- TODO: Handle the error and show error message or try again
+                    ( model, Cmd.none )
+
         OpenNewCaseForm ->
-            ( { model | newCaseForm = Just NewCaseForm.init }, Cmd.none )
+            ( { model | newCaseForm = Just <| NewCaseForm.init model.rollen }, Cmd.none )
 
         NewCaseFormMsg innerMsg ->
             handleNewCaseFormMsg innerMsg model
@@ -2,8 +2,8 @@ module NewCaseForm exposing (Action(..), Model, Msg, init, update, view)
 
 import Case
 import Html exposing (..)
-import Html.Attributes exposing (attribute, class, classList, disabled, for, id, placeholder, rows, selected, type_, value)
-import Html.Events exposing (onClick, onInput, onSubmit)
+import Html.Attributes exposing (attribute, checked, class, classList, disabled, for, id, placeholder, rows, selected, title, type_, value)
+import Html.Events exposing (onCheck, onClick, onInput, onSubmit)
 import Http
 import Json.Decode as JD
 import Shared exposing (classes)
@@ -20,12 +20,14 @@ import Shared exposing (classes)
                     invalid data (i. e. fields that must not be empty).
     caseOnSaving : Holds the case that was sent to server while we are waiting
                    for the response. The form is "locked" meanwhile.
+    rollen :        The catalog of roles from the server.
 
 -}
 type alias Model =
     { formData : FormData
     , invalidFields : InvalidFields
     , caseOnSaving : Maybe Case.Model
+    , rollen : List Case.Rolle
     }
 
 
@@ -36,7 +38,8 @@ type alias FormData =
     , beginn : String
     , ende : String
     , gegenstand : String
-    , art : Case.Art
+    , art : String
+    , rollen : List String
     , beschreibung : String
     , stand : String
     }
@@ -47,29 +50,33 @@ type alias FormData =
 type alias InvalidFields =
     { rubrum : Bool
     , beginn : Bool
+    , art : Bool
     , stand : Bool
     }
 
 
-{-| Initializes empty form.
+{-| Initializes empty form with the catalog of roles. The first role of the
+catalog is preselected.
 -}
-init : Model
-init =
+init : List Case.Rolle -> Model
+init rollen =
     Model
-        defaultFormData
+        (defaultFormData rollen)
         defaultInvalidFields
         Nothing
+        rollen
 
 
-defaultFormData : FormData
-defaultFormData =
+defaultFormData : List Case.Rolle -> FormData
+defaultFormData rollen =
     { rubrum = ""
     , az = ""
     , gericht = ""
     , beginn = ""
     , ende = ""
     , gegenstand = ""
-    , art = Case.defaultArt
+    , art = List.head rollen |> Maybe.map .name |> Maybe.withDefault ""
+    , rollen = []
     , beschreibung = ""
     , stand = "laufend"
     }
@@ -77,7 +84,7 @@ defaultFormData =
 
 defaultInvalidFields : InvalidFields
 defaultInvalidFields =
-    InvalidFields False False False
+    InvalidFields False False False False
 
 
 
@@ -98,7 +105,8 @@ type FormDataInput
     | Beginn String
     | Ende String
     | Gegenstand String
-    | ArtMsg Case.Art
+    | ArtMsg String
+    | RolleMsg String Bool
     | Beschreibung String
     | Stand String
 
@@ -165,7 +173,17 @@ updateFormData msg formData =
             { formData | gegenstand = v }
 
         ArtMsg v ->
-            { formData | art = v }
+            { formData | art = v, rollen = List.filter ((/=) v) formData.rollen }
+
+        RolleMsg r True ->
+            if r == formData.art || List.member r formData.rollen then
+                formData
+
+            else
+                { formData | rollen = formData.rollen ++ [ r ] }
+
+        RolleMsg r False ->
+            { formData | rollen = List.filter ((/=) r) formData.rollen }
 
         Beschreibung v ->
             { formData | beschreibung = v }
@@ -203,6 +221,7 @@ save model =
                     f.ende
                     f.gegenstand
                     f.art
+                    f.rollen
                     f.beschreibung
                     f.stand
 
@@ -222,12 +241,13 @@ formValidate f =
     InvalidFields
         (f.rubrum == "")
         (f.beginn == "")
+        (f.art == "")
         (f.stand == "")
 
 
 formIsInvalid : InvalidFields -> Bool
 formIsInvalid i =
-    i.rubrum || i.beginn || i.stand
+    i.rubrum || i.beginn || i.art || i.stand
 
 
 
@@ -241,15 +261,15 @@ view model =
     div []
         [ form
             [ onSubmit Save, class "mb-5" ]
-            [ formfields model.formData model.invalidFields |> map FormDataMsg
+            [ formfields model.rollen model.formData model.invalidFields |> map FormDataMsg
             , formButtons model.caseOnSaving
             ]
         , hr [ classes "col-4 mb-5" ] []
         ]
 
 
-formfields : FormData -> InvalidFields -> Html FormDataInput
-formfields formData invalidFields =
+formfields : List Case.Rolle -> FormData -> InvalidFields -> Html FormDataInput
+formfields rollen formData invalidFields =
     div []
         [ rubrum formData.rubrum invalidFields.rubrum
         , az formData.az
@@ -257,7 +277,8 @@ formfields formData invalidFields =
         , beginn formData.beginn invalidFields.beginn
         , ende formData.ende
         , gegenstand formData.gegenstand
-        , art formData.art
+        , art rollen formData.art invalidFields.art
+        , weitereRollen rollen formData
         , beschreibung formData.beschreibung
         , stand formData.stand invalidFields.stand
         ]
@@ -349,8 +370,8 @@ gegenstand a =
         a
 
 
-art : Case.Art -> Html FormDataInput
-art a =
+art : List Case.Rolle -> Value -> IsInvalid -> Html FormDataInput
+art rollen a i =
     let
         idPrefix : String
         idPrefix =
@@ -361,24 +382,54 @@ art a =
             [ text "Art der Tätigkeit" ]
         , select
             [ id (idPrefix ++ "Select")
-            , class "form-control"
+            , classList [ ( "form-control", True ), ( "is-invalid", i ) ]
             , attribute "aria-describedby" (idPrefix ++ "Help")
-            , onInput (\value -> Case.stringToArt value |> ArtMsg)
-            ]
-            [ artOption Case.Verteidiger a
-            , artOption Case.Nebenklaeger a
-            , artOption Case.Zeugenbeistand a
-            , artOption Case.Adhaesionsklaeger a
+            , onInput ArtMsg
             ]
+            (List.map (artOption a) rollen)
         , div [ id (idPrefix ++ "Help"), class "form-text" ]
             [ text "" ]
         ]
 
 
-artOption : Case.Art -> Case.Art -> Html FormDataInput
-artOption a b =
-    option [ value <| Case.artToString a, selected (a == b) ]
-        [ text <| Case.artToString a ]
+artOption : Value -> Case.Rolle -> Html FormDataInput
+artOption a r =
+    option [ value r.name, selected (r.name == a), title r.beschreibung ]
+        [ text r.name ]
+
+
+{-| Shows a checkbox for every role of the catalog besides the main role.
+-}
+weitereRollen : List Case.Rolle -> FormData -> Html FormDataInput
+weitereRollen rollen formData =
+    div [ class "mb-3" ]
+        (label [ class "form-label" ] [ text "Weitere Rollen" ]
+            :: (rollen
+                    |> List.filter (\r -> r.name /= formData.art)
+                    |> List.map (rolleCheckbox formData.rollen)
+               )
+        )
+
+
+rolleCheckbox : List String -> Case.Rolle -> Html FormDataInput
+rolleCheckbox selectedRollen r =
+    let
+        idName : String
+        idName =
+            "NewCaseForm" ++ "Rolle" ++ String.filter Char.isAlpha r.name
+    in
+    div [ class "form-check" ]
+        [ input
+            [ type_ "checkbox"
+            , class "form-check-input"
+            , id idName
+            , checked (List.member r.name selectedRollen)
+            , onCheck (RolleMsg r.name)
+            ]
+            []
+        , label [ for idName, class "form-check-label", title r.beschreibung ]
+            [ text r.name ]
+        ]
 
 
 beschreibung : Value -> Html FormDataInput
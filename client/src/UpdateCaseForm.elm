@@ -1,6 +1,5 @@
 module UpdateCaseForm exposing (Model, Msg, init, update, view)
 
-import Case
 import Html exposing (..)
 import Html.Attributes exposing (disabled, type_)
 import Html.Events exposing (onClick, onSubmit)
@@ -47,7 +46,8 @@ type FormDataInput
     | Beginn String
     | Ende String
     | Gegenstand String
-    | ArtMsg Case.Art
+    | ArtMsg String
+    | RolleMsg String Bool
     | Beschreibung String
     | Stand String
 
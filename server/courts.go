@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/court"
+)
+
+// runImportCourts imports a court list from a local JSON file containing an
+// array of courts. Courts that already exist (same name and place) are
+// skipped. Usage:
+//
+//	server import-courts FILE
+func runImportCourts(out io.Writer, m *model.Model, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly one JSON file, got %d arguments", len(args))
+	}
+
+	b, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("reading court list: %w", err)
+	}
+	var courts []court.Court
+	if err := json.Unmarshal(b, &courts); err != nil {
+		return fmt.Errorf("unmarshalling court list: %w", err)
+	}
+
+	v := validator.New()
+	for i, c := range courts {
+		if err := v.Struct(c); err != nil {
+			return fmt.Errorf("invalid court %d (%s), nothing was saved:\n%v", i+1, c.Name, err)
+		}
+	}
+
+	for _, c := range courts {
+		if id, ok := m.Court.Find(c.Name, c.Ort); ok {
+			fmt.Fprintf(out, "Skipped %s, it already exists as court %d\n", c.Name, id)
+			continue
+		}
+		id, err := m.Court.AddCourt(c, m.WriteEvent("Court"))
+		if err != nil {
+			return fmt.Errorf("adding court %s: %w", c.Name, err)
+		}
+		fmt.Fprintf(out, "Saved court %d (%s)\n", id, c.Name)
+	}
+	return nil
+}
+
+// runMigrateCourts prints suggested courts for all cases that do not
+// reference a court yet. With -apply, every case with exactly one best
+// suggestion of full score gets this court. Usage:
+//
+//	server migrate-courts [-apply]
+func runMigrateCourts(out io.Writer, m *model.Model, args []string) error {
+	fs := flag.NewFlagSet("migrate-courts", flag.ContinueOnError)
+	apply := fs.Bool("apply", false, "assign unambiguous matches to the cases")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	for _, s := range m.CourtSuggestions() {
+		fmt.Fprintf(out, "Case %d %q:", s.CaseID, s.Gericht)
+		if len(s.Suggestions) == 0 {
+			fmt.Fprintf(out, " no suggestion\n")
+			continue
+		}
+		for _, sug := range s.Suggestions {
+			fmt.Fprintf(out, " %d %s (%.1f)", sug.CourtID, m.Court[sug.CourtID].Name, sug.Score)
+		}
+		fmt.Fprintln(out)
+
+		unambiguous := s.Suggestions[0].Score == 1 &&
+			(len(s.Suggestions) == 1 || s.Suggestions[1].Score < 1)
+		if !*apply || !unambiguous {
+			continue
+		}
+		c := m.Case[s.CaseID]
+		c.GerichtID = s.Suggestions[0].CourtID
+		if err := m.UpdateCase(s.CaseID, c); err != nil {
+			return fmt.Errorf("updating case %d: %w", s.CaseID, err)
+		}
+		fmt.Fprintf(out, "  assigned court %d\n", c.GerichtID)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/csvimport"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+)
+
+// assignments is a flag value that can be given several times.
+type assignments []string
+
+func (a *assignments) String() string {
+	return strings.Join(*a, ",")
+}
+
+func (a *assignments) Set(v string) error {
+	*a = append(*a, v)
+	return nil
+}
+
+// runImport imports cases from a CSV file. Usage:
+//
+//	server import [-dryrun] [-map Field=Column ...] [-default Field=Value ...] FILE
+func runImport(out io.Writer, m *model.Model, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fs.Bool("dryrun", false, "only validate the rows, do not save anything")
+	var mapFlag, defaultFlag assignments
+	fs.Var(&mapFlag, "map", "map a case field to a CSV column, e.g. Rubrum=Mandant (repeatable)")
+	fs.Var(&defaultFlag, "default", "default value for an empty case field, e.g. Stand=abgeschlossen (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected exactly one CSV file, got %d arguments", fs.NArg())
+	}
+
+	mapping, err := csvimport.ParseAssignments(mapFlag)
+	if err != nil {
+		return fmt.Errorf("parsing mapping: %w", err)
+	}
+	defaults, err := csvimport.ParseAssignments(defaultFlag)
+	if err != nil {
+		return fmt.Errorf("parsing defaults: %w", err)
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("opening CSV file: %w", err)
+	}
+	defer f.Close()
+
+	rows, err := csvimport.Read(f, csvimport.Options{Mapping: mapping, Defaults: defaults})
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if len(row.Errors) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "Line %d (%s):\n", row.Line, row.Case.Rubrum)
+		for _, e := range row.Errors {
+			fmt.Fprintf(out, "  %s\n", e)
+		}
+	}
+	if csvimport.HasErrors(rows) {
+		return fmt.Errorf("CSV file contains invalid rows, nothing was saved")
+	}
+
+	if *dryRun {
+		fmt.Fprintf(out, "Dry run: %d valid rows, nothing was saved\n", len(rows))
+		return nil
+	}
+
+	for _, row := range rows {
+		id, err := m.AddCase(row.Case)
+		if err != nil {
+			return fmt.Errorf("adding case from line %d: %w", row.Line, err)
+		}
+		fmt.Fprintf(out, "Line %d: saved case %d (%s)\n", row.Line, id, row.Case.Rubrum)
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+	"io"
 	"log"
 	"os"
 
@@ -10,6 +12,20 @@ import (
 	"github.com/normanjaeckel/fao-strafrecht/server/pkg/srv"
 )
 
+// runCommand runs one of the subcommands instead of the server.
+func runCommand(out io.Writer, m *model.Model, cmd string, args []string) error {
+	switch cmd {
+	case "import":
+		return runImport(out, m, args)
+	case "import-courts":
+		return runImportCourts(out, m, args)
+	case "migrate-courts":
+		return runMigrateCourts(out, m, args)
+	default:
+		return fmt.Errorf("unknown command")
+	}
+}
+
 func main() {
 	// Logger
 	logger := log.Default()
@@ -32,6 +48,14 @@ func main() {
 		logger.Fatalf("Error: loading model: %v", err)
 	}
 
+	// Subcommands
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Stdout, model, os.Args[1], os.Args[2:]); err != nil {
+			logger.Fatalf("Error: %s: %v", os.Args[1], err)
+		}
+		return
+	}
+
 	// Start everything.
 	if err := srv.Run(logger, environment, model); err != nil {
 		logger.Fatalf("Error: %v", err)
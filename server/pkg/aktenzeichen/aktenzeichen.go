@@ -0,0 +1,170 @@
+/*
+Package aktenzeichen parses German case numbers of criminal proceedings like
+"123 Js 4567/25" (public prosecutor's office) or "5 Ls 12/26" (court).
+*/
+package aktenzeichen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Authorities a case number belongs to.
+const (
+	Staatsanwaltschaft = "Staatsanwaltschaft"
+	Gericht            = "Gericht"
+)
+
+// registerzeichen maps the register signs used in criminal proceedings to the
+// authority. The keys are in lower case without whitespace.
+var registerzeichen = map[string]string{
+	// Staatsanwaltschaft
+	"js":   Staatsanwaltschaft, // Ermittlungsverfahren gegen bekannte Beschuldigte
+	"ujs":  Staatsanwaltschaft, // Ermittlungsverfahren gegen Unbekannt
+	"bjs":  Staatsanwaltschaft, // Generalbundesanwalt
+	"ar":   Staatsanwaltschaft, // Allgemeines Register
+	"zs":   Staatsanwaltschaft, // Beschwerden an die Generalstaatsanwaltschaft
+	"vrs":  Staatsanwaltschaft, // Vollstreckung
+	"vrjs": Gericht,            // Jugendvollstreckung beim Jugendrichter
+
+	// Amtsgericht
+	"gs":  Gericht, // Ermittlungsrichter
+	"cs":  Gericht, // Strafbefehl
+	"ds":  Gericht, // Strafrichter
+	"ls":  Gericht, // Schöffengericht
+	"owi": Gericht, // Bußgeldsachen
+
+	// Landgericht
+	"kls":  Gericht, // Große Strafkammer, erste Instanz
+	"ks":   Gericht, // Schwurgericht
+	"ns":   Gericht, // Kleine Strafkammer, Berufung
+	"qs":   Gericht, // Beschwerden
+	"stvk": Gericht, // Strafvollstreckungskammer
+
+	// Oberlandesgericht und Bundesgerichtshof
+	"ss":    Gericht, // Revision
+	"ssrs":  Gericht, // Rechtsbeschwerde
+	"ssowi": Gericht, // Rechtsbeschwerde in Bußgeldsachen
+	"ws":    Gericht, // Beschwerde
+	"ojs":   Gericht, // Staatsschutzsachen, erste Instanz
+	"str":   Gericht, // Strafsenat des Bundesgerichtshofs
+	"ars":   Gericht, // Allgemeines Register des Bundesgerichtshofs
+}
+
+var pattern = regexp.MustCompile(`^(\d+)\s*([A-Za-zÄÖÜäöü]+(?:\s+[A-Za-zÄÖÜäöü]+)?)\s*(\d+)\s*/\s*(\d{2}|\d{4})$`)
+
+var findPattern = regexp.MustCompile(`\d+\s*[A-Za-zÄÖÜäöü]+(?:\s+[A-Za-zÄÖÜäöü]+)?\s*\d+\s*/\s*(?:\d{4}|\d{2})\b`)
+
+// Aktenzeichen is a parsed case number.
+type Aktenzeichen struct {
+	// Abteilung is the number of the department or chamber in front of the
+	// register sign.
+	Abteilung int `json:"Abteilung"`
+
+	// Registerzeichen is the register sign like "Js" or "KLs" as written in
+	// the input.
+	Registerzeichen string `json:"Registerzeichen"`
+
+	Nummer int `json:"Nummer"`
+
+	// Jahr is the year with four digits.
+	Jahr int `json:"Jahr"`
+
+	// Behoerde is Staatsanwaltschaft or Gericht.
+	Behoerde string `json:"Behoerde"`
+}
+
+// Parse parses a case number. Whitespace is optional and may be repeated. Two
+// digit years are expanded to 19xx or 20xx.
+func Parse(s string) (Aktenzeichen, error) {
+	m := pattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Aktenzeichen{}, fmt.Errorf("unrecognized case number %q", s)
+	}
+
+	sign := strings.Join(strings.Fields(m[2]), " ")
+	behoerde, ok := registerzeichen[strings.ToLower(strings.ReplaceAll(sign, " ", ""))]
+	if !ok {
+		return Aktenzeichen{}, fmt.Errorf("unknown register sign %q in case number %q", sign, s)
+	}
+
+	abteilung, _ := strconv.Atoi(m[1])
+	nummer, _ := strconv.Atoi(m[3])
+	jahr, _ := strconv.Atoi(m[4])
+	if len(m[4]) == 2 {
+		if jahr < 50 {
+			jahr += 2000
+		} else {
+			jahr += 1900
+		}
+	}
+
+	return Aktenzeichen{
+		Abteilung:       abteilung,
+		Registerzeichen: sign,
+		Nummer:          nummer,
+		Jahr:            jahr,
+		Behoerde:        behoerde,
+	}, nil
+}
+
+// Find returns all valid case numbers contained in a free text like "AG
+// Leipzig 123 Cs 456 Js 7890/22; LG Leipzig 5 Ns 12/23". Note that in the
+// first example the court number is incomplete, so only the case number of
+// the prosecutor's office is found.
+func Find(text string) []Aktenzeichen {
+	var result []Aktenzeichen
+	rest := text
+	for {
+		loc := findPattern.FindStringIndex(rest)
+		if loc == nil {
+			return result
+		}
+		if a, err := Parse(rest[loc[0]:loc[1]]); err == nil {
+			result = append(result, a)
+			rest = rest[loc[1]:]
+			continue
+		}
+		// Skip the first number and try again, e.g. "123 Cs 456 Js 7890/22"
+		// is not valid but "456 Js 7890/22" is.
+		next := strings.IndexFunc(rest[loc[0]:], func(r rune) bool { return r < '0' || r > '9' })
+		rest = rest[loc[0]+next:]
+	}
+}
+
+// String returns the normalized case number with single spaces and a two
+// digit year, e.g. "123 Js 4567/25".
+func (a Aktenzeichen) String() string {
+	return fmt.Sprintf("%d %s %d/%02d", a.Abteilung, a.Registerzeichen, a.Nummer, a.Jahr%100)
+}
+
+// Normalize returns the normalized form of a case number or the trimmed input
+// if it is not a valid case number.
+func Normalize(s string) string {
+	a, err := Parse(s)
+	if err != nil {
+		return strings.TrimSpace(s)
+	}
+	return a.String()
+}
+
+// Compare compares two case numbers by year, register sign, number and
+// department. It returns -1, 0 or 1.
+func Compare(a, b Aktenzeichen) int {
+	for _, c := range []int{
+		a.Jahr - b.Jahr,
+		strings.Compare(strings.ToLower(a.Registerzeichen), strings.ToLower(b.Registerzeichen)),
+		a.Nummer - b.Nummer,
+		a.Abteilung - b.Abteilung,
+	} {
+		if c < 0 {
+			return -1
+		}
+		if c > 0 {
+			return 1
+		}
+	}
+	return 0
+}
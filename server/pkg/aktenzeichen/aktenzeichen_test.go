@@ -0,0 +1,92 @@
+package aktenzeichen_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/aktenzeichen"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected aktenzeichen.Aktenzeichen
+	}{
+		{"123 Js 4567/25", aktenzeichen.Aktenzeichen{123, "Js", 4567, 2025, aktenzeichen.Staatsanwaltschaft}},
+		{"  123  Js 4567 / 2025 ", aktenzeichen.Aktenzeichen{123, "Js", 4567, 2025, aktenzeichen.Staatsanwaltschaft}},
+		{"123Js4567/25", aktenzeichen.Aktenzeichen{123, "Js", 4567, 2025, aktenzeichen.Staatsanwaltschaft}},
+		{"5 Ls 12/26", aktenzeichen.Aktenzeichen{5, "Ls", 12, 2026, aktenzeichen.Gericht}},
+		{"1 KLs 3/98", aktenzeichen.Aktenzeichen{1, "KLs", 3, 1998, aktenzeichen.Gericht}},
+		{"2 Ss  OWi 100/22", aktenzeichen.Aktenzeichen{2, "Ss OWi", 100, 2022, aktenzeichen.Gericht}},
+		{"1 StR 123/22", aktenzeichen.Aktenzeichen{1, "StR", 123, 2022, aktenzeichen.Gericht}},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := aktenzeichen.Parse(tc.input)
+			if err != nil {
+				t.Fatalf("parsing case number: %v", err)
+			}
+			if got != tc.expected {
+				t.Fatalf("wrong result: expected %#v, got %#v", tc.expected, got)
+			}
+		})
+	}
+
+	for input, expectedErrMsg := range map[string]string{
+		"000234/2022 M.M.": `unrecognized case number "000234/2022 M.M."`,
+		"12 Xy 34/22":      `unknown register sign "Xy" in case number "12 Xy 34/22"`,
+		"":                 `unrecognized case number ""`,
+	} {
+		t.Run(input, func(t *testing.T) {
+			_, err := aktenzeichen.Parse(input)
+			if err == nil || err.Error() != expectedErrMsg {
+				t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+			}
+		})
+	}
+}
+
+func TestString(t *testing.T) {
+	for input, expected := range map[string]string{
+		"123  Js 4567 / 2025": "123 Js 4567/25",
+		"5 Ls 12/06":          "5 Ls 12/06",
+		"foo  ":               "foo",
+	} {
+		got := aktenzeichen.Normalize(input)
+		if got != expected {
+			t.Fatalf("wrong normalized form of %q: expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	got := aktenzeichen.Find("AG Leipzig 123 Cs 456 Js 7890/2022; LG Leipzig 5 Ns 12/23, 7 Xy 1/22")
+	expected := "[456 Js 7890/22 5 Ns 12/23]"
+	if fmt.Sprint(got) != expected {
+		t.Fatalf("wrong case numbers: expected %s, got %s", expected, fmt.Sprint(got))
+	}
+}
+
+func TestCompare(t *testing.T) {
+	parse := func(s string) aktenzeichen.Aktenzeichen {
+		a, err := aktenzeichen.Parse(s)
+		if err != nil {
+			t.Fatalf("parsing case number: %v", err)
+		}
+		return a
+	}
+	for _, tc := range []struct {
+		a, b     string
+		expected int
+	}{
+		{"1 Js 5/22", "1 Js 5/22", 0},
+		{"1 Js 5/21", "1 Js 4/22", -1},
+		{"1 Js 5/22", "1 Js 40/22", -1},
+		{"2 Ls 5/22", "1 Js 5/22", 1},
+		{"2 Js 5/22", "1 Js 5/22", 1},
+	} {
+		got := aktenzeichen.Compare(parse(tc.a), parse(tc.b))
+		if got != tc.expected {
+			t.Fatalf("wrong result for %s and %s: expected %d, got %d", tc.a, tc.b, tc.expected, got)
+		}
+	}
+}
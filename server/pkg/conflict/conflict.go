@@ -0,0 +1,86 @@
+/*
+Package conflict checks for conflicts of interest (§ 43a Abs. 4 BRAO, § 356
+StGB Parteiverrat). A conflict is a person that we represent in one case while
+being on the opposite side of this person in another case.
+*/
+package conflict
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/person"
+)
+
+// Conflict is one conflict of interest between the checked case and an
+// existing case.
+type Conflict struct {
+	PersonID int    `json:"PersonID"`
+	CaseID   int    `json:"CaseID"`
+	Hinweis  string `json:"Hinweis"`
+}
+
+// Check returns all conflicts of the given case with the existing cases. The
+// case with the id self (the checked case itself if it is already saved) is
+// skipped.
+func Check(cs lawcase.Model, ps person.Model, c lawcase.Case, self int) []Conflict {
+	mandanten := set(c.Mandanten())
+	gegner := set(c.Gegner())
+
+	var result []Conflict
+	ids := make([]int, 0, len(cs))
+	for id := range cs {
+		if id != self {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		other := cs[id]
+		for _, pid := range other.Mandanten() {
+			if gegner[pid] {
+				result = append(result, Conflict{
+					PersonID: pid,
+					CaseID:   id,
+					Hinweis:  fmt.Sprintf("%s is our client in case %d (%s) but on the opposite side in this case", name(ps, pid), id, other.Rubrum),
+				})
+			}
+		}
+		for _, pid := range other.Gegner() {
+			if mandanten[pid] {
+				result = append(result, Conflict{
+					PersonID: pid,
+					CaseID:   id,
+					Hinweis:  fmt.Sprintf("%s is our client in this case but on the opposite side in case %d (%s)", name(ps, pid), id, other.Rubrum),
+				})
+			}
+		}
+	}
+	return result
+}
+
+// Warnings returns the conflicts as warnings like the warnings of a case.
+func Warnings(conflicts []Conflict) []string {
+	var warnings []string
+	for _, c := range conflicts {
+		warnings = append(warnings, "Beteiligte: possible conflict of interest: "+c.Hinweis)
+	}
+	return warnings
+}
+
+func name(ps person.Model, id int) string {
+	if p, ok := ps[id]; ok {
+		return p.FullName()
+	}
+	return fmt.Sprintf("person %d", id)
+}
+
+func set(ids []int) map[int]bool {
+	m := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		m[id] = true
+	}
+	return m
+}
@@ -0,0 +1,71 @@
+package conflict_test
+
+import (
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/conflict"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/person"
+)
+
+func TestCheck(t *testing.T) {
+	ps := person.Model{
+		1: {Name: "A"},
+		2: {Name: "V"},
+		3: {Name: "Z"},
+	}
+	cs := lawcase.Model{
+		1: {Rubrum: "A wegen Körperverletzung", Art: "Verteidiger", Beteiligte: []lawcase.Beteiligter{
+			{PersonID: 1, Rolle: "Mandant"},
+			{PersonID: 2, Rolle: "Verletzter"},
+			{PersonID: 3, Rolle: "Zeuge"},
+		}},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		c        lawcase.Case
+		expected []conflict.Conflict
+	}{
+		{
+			name: "Nebenklage for the victim against our client",
+			c: lawcase.Case{Art: "Nebenkläger", Beteiligte: []lawcase.Beteiligter{
+				{PersonID: 2, Rolle: "Mandant"},
+				{PersonID: 1, Rolle: "Beschuldigter"},
+			}},
+			expected: []conflict.Conflict{
+				{PersonID: 1, CaseID: 1, Hinweis: "A is our client in case 1 (A wegen Körperverletzung) but on the opposite side in this case"},
+				{PersonID: 2, CaseID: 1, Hinweis: "V is our client in this case but on the opposite side in case 1 (A wegen Körperverletzung)"},
+			},
+		},
+		{
+			name: "defence for the client again",
+			c: lawcase.Case{Art: "Verteidiger", Beteiligte: []lawcase.Beteiligter{
+				{PersonID: 1, Rolle: "Mandant"},
+			}},
+		},
+		{
+			name: "witness counsel for a witness",
+			c: lawcase.Case{Art: "Zeugenbeistand", Beteiligte: []lawcase.Beteiligter{
+				{PersonID: 3, Rolle: "Mandant"},
+				{PersonID: 1, Rolle: "Beschuldigter"},
+			}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := conflict.Check(cs, ps, tc.c, 0)
+			if len(got) != len(tc.expected) {
+				t.Fatalf("wrong conflicts: expected %v, got %v", tc.expected, got)
+			}
+			for i := range got {
+				if got[i] != tc.expected[i] {
+					t.Fatalf("wrong conflict: expected %v, got %v", tc.expected[i], got[i])
+				}
+			}
+		})
+	}
+
+	if got := conflict.Check(cs, ps, cs[1], 1); len(got) != 0 {
+		t.Fatalf("case conflicts with itself: %v", got)
+	}
+}
@@ -0,0 +1,62 @@
+package csvimport
+
+import (
+	"strings"
+)
+
+// artKeywords maps word stems found in free-text role descriptions to the
+// roles of the catalog lawcase.Rollen. The first matching stem wins, so more
+// specific stems have to come first.
+var artKeywords = []struct {
+	stem string
+	art  string
+}{
+	{"adhäsion", "Adhäsionskläger"},
+	{"adhaesion", "Adhäsionskläger"},
+	{"nebenkl", "Nebenkläger"},
+	{"nk-vertret", "Nebenkläger"},
+	{"vernehmungsbeist", "Vernehmungsbeistand"},
+	{"verletztenbeist", "Verletztenbeistand"},
+	{"zeugenbeist", "Zeugenbeistand"},
+	{"zeuge", "Zeugenbeistand"},
+	{"zeugin", "Zeugenbeistand"},
+	{"verteid", "Verteidiger"},
+	{"vert.", "Verteidiger"},
+	{"pflichtvert", "Verteidiger"},
+	{"wahlvert", "Verteidiger"},
+}
+
+// splitRoles splits a free-text field with several roles like "Nebenklage /
+// Adhäsion" into the single roles.
+func splitRoles(s string) []string {
+	s = strings.ReplaceAll(s, " und ", ",")
+	var result []string
+	for _, r := range strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == '/' || r == '+' || r == ';' || r == '&'
+	}) {
+		if r = strings.TrimSpace(r); r != "" {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// ParseArt maps a free-text role like "Pflichtverteidigung" or "NK-Vertreter"
+// onto one of the roles of the catalog lawcase.Rollen.
+func ParseArt(s string) (string, bool) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	switch v {
+	case "v", "pv", "wv":
+		return "Verteidiger", true
+	case "nk":
+		return "Nebenkläger", true
+	case "zb":
+		return "Zeugenbeistand", true
+	}
+	for _, k := range artKeywords {
+		if strings.Contains(v, k.stem) {
+			return k.art, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,261 @@
+/*
+Package csvimport reads a case list from a CSV file, e.g. exported from an old
+spreadsheet, and transforms every row into a case.
+*/
+package csvimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Options configure the import.
+type Options struct {
+	// Mapping maps a field of lawcase.Case to the header of a CSV column. All
+	// fields that are not in the mapping are taken from a column with the same
+	// name as the field (case insensitive) if such a column exists.
+	Mapping map[string]string
+
+	// Defaults contains values for fields that are empty after the mapping.
+	Defaults map[string]string
+}
+
+// Row is the result for one row of the CSV file.
+type Row struct {
+	Line   int          `json:"Line"`
+	Case   lawcase.Case `json:"Case"`
+	Errors []string     `json:"Errors"`
+}
+
+// Fields returns the names of all fields of lawcase.Case that can be mapped.
+func Fields() []string {
+	t := reflect.TypeOf(lawcase.Case{})
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type.Kind() == reflect.String {
+			fields = append(fields, t.Field(i).Name)
+		}
+	}
+	return fields
+}
+
+// ParseAssignments parses a list of assignments like "Rubrum=Mandant" into a
+// map. It is used for the mapping and the default values given by the user.
+func ParseAssignments(l []string) (map[string]string, error) {
+	result := make(map[string]string, len(l))
+	for _, a := range l {
+		k, v, ok := strings.Cut(a, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid assignment %q, expected Field=Value", a)
+		}
+		k = strings.TrimSpace(k)
+		if !isField(k) {
+			return nil, fmt.Errorf("unknown case field %q", k)
+		}
+		result[k] = strings.TrimSpace(v)
+	}
+	return result, nil
+}
+
+func isField(name string) bool {
+	for _, f := range Fields() {
+		if f == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Read reads the CSV data and returns one row for every record except the
+// header. The delimiter (comma or semicolon) is detected from the header line.
+// Data that is not valid UTF-8 is decoded as Windows-1252 as this is what
+// Excel usually writes. The cases are validated but nothing is saved.
+func Read(r io.Reader, opts Options) ([]Row, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV data: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		data, err = charmap.Windows1252.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("decoding Windows-1252 data: %w", err)
+		}
+	}
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.Comma = detectDelimiter(data)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("CSV data is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+
+	columns, err := columnIndices(header, opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	for k := range opts.Defaults {
+		if !isField(k) {
+			return nil, fmt.Errorf("unknown case field %q in defaults", k)
+		}
+	}
+
+	v := lawcase.NewValidator()
+	var rows []Row
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV record: %w", err)
+		}
+		line, _ := cr.FieldPos(0)
+		if isEmpty(record) {
+			continue
+		}
+		rows = append(rows, readRow(v, line, record, columns, opts.Defaults))
+	}
+	return rows, nil
+}
+
+func detectDelimiter(data []byte) rune {
+	first, _ := bufio.NewReader(bytes.NewReader(data)).ReadString('\n')
+	if strings.Count(first, ";") > strings.Count(first, ",") {
+		return ';'
+	}
+	return ','
+}
+
+// columnIndices returns the index of the CSV column for every mapped field.
+func columnIndices(header []string, mapping map[string]string) (map[string]int, error) {
+	find := func(name string) int {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(name)) {
+				return i
+			}
+		}
+		return -1
+	}
+
+	columns := make(map[string]int)
+	for _, f := range Fields() {
+		col, ok := mapping[f]
+		if !ok {
+			if i := find(f); i >= 0 {
+				columns[f] = i
+			}
+			continue
+		}
+		i := find(col)
+		if i < 0 {
+			return nil, fmt.Errorf("column %q for field %s not found in CSV header", col, f)
+		}
+		columns[f] = i
+	}
+	for f := range mapping {
+		if !isField(f) {
+			return nil, fmt.Errorf("unknown case field %q in mapping", f)
+		}
+	}
+	return columns, nil
+}
+
+func isEmpty(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func readRow(v *validator.Validate, line int, record []string, columns map[string]int, defaults map[string]string) Row {
+	row := Row{Line: line, Errors: []string{}}
+
+	values := make(map[string]string)
+	for f, i := range columns {
+		if i < len(record) {
+			values[f] = strings.TrimSpace(record[i])
+		}
+	}
+	for f, d := range defaults {
+		if values[f] == "" {
+			values[f] = d
+		}
+	}
+
+	if b := values["Beginn"]; b != "" {
+		t, err := lawcase.ParseDate(b)
+		if err != nil {
+			row.Errors = append(row.Errors, fmt.Sprintf("field Beginn: %v", err))
+		} else {
+			values["Beginn"] = t.Format(lawcase.DateLayout)
+		}
+	}
+	if e := values["Ende"]; e != "" {
+		// Ende may contain free text like "noch anhängig" so we only
+		// normalize real dates.
+		if t, err := lawcase.ParseDate(e); err == nil {
+			values["Ende"] = t.Format(lawcase.DateLayout)
+		}
+	}
+	var roles []string
+	for _, a := range splitRoles(values["Art"]) {
+		art, ok := ParseArt(a)
+		if !ok {
+			row.Errors = append(row.Errors, fmt.Sprintf("field Art: unknown role %q", a))
+			continue
+		}
+		roles = append(roles, art)
+	}
+	if len(roles) > 0 {
+		values["Art"] = roles[0]
+		row.Case.Rollen = roles[1:]
+		if len(row.Case.Rollen) == 0 {
+			row.Case.Rollen = nil
+		}
+	}
+
+	c := reflect.ValueOf(&row.Case).Elem()
+	for f, val := range values {
+		c.FieldByName(f).SetString(val)
+	}
+
+	if err := v.Struct(row.Case); err != nil {
+		if errs, ok := err.(validator.ValidationErrors); ok {
+			for _, fe := range errs {
+				row.Errors = append(row.Errors, fe.Error())
+			}
+		} else {
+			row.Errors = append(row.Errors, err.Error())
+		}
+	}
+	return row
+}
+
+// HasErrors reports whether at least one of the rows is invalid.
+func HasErrors(rows []Row) bool {
+	for _, r := range rows {
+		if len(r.Errors) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package csvimport_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/csvimport"
+)
+
+func TestRead(t *testing.T) {
+	t.Run("semicolon with mapping and German dates", func(t *testing.T) {
+		data := "\ufeffMandant;Datum;Rolle;Stand;Bemerkung\n" +
+			"Müller wg. Betrug;01.02.2019;Pflichtverteidigung;abgeschlossen;foo\n" +
+			";;;;\n" +
+			"Schulze;2. März 2020;NK-Vertreterin / Adhäsion;laufend;bar\n"
+		opts := csvimport.Options{
+			Mapping: map[string]string{
+				"Rubrum":       "Mandant",
+				"Beginn":       "Datum",
+				"Art":          "Rolle",
+				"Beschreibung": "Bemerkung",
+			},
+		}
+
+		rows, err := csvimport.Read(strings.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf("reading CSV: %v", err)
+		}
+		if len(rows) != 2 {
+			t.Fatalf("wrong number of rows: expected 2, got %d", len(rows))
+		}
+		if csvimport.HasErrors(rows) {
+			t.Fatalf("unexpected errors: %v", rows)
+		}
+		c := rows[0].Case
+		if c.Rubrum != "Müller wg. Betrug" || c.Beginn != "2019-02-01" || c.Art != "Verteidiger" || c.Stand != "abgeschlossen" || c.Beschreibung != "foo" {
+			t.Fatalf("wrong first case: %#v", c)
+		}
+		c = rows[1].Case
+		if rows[1].Line != 4 || c.Beginn != "2020-03-02" || c.Art != "Nebenkläger" || len(c.Rollen) != 1 || c.Rollen[0] != "Adhäsionskläger" {
+			t.Fatalf("wrong second row: %#v", rows[1])
+		}
+	})
+
+	t.Run("comma, defaults and Windows-1252", func(t *testing.T) {
+		data := "Rubrum,Beginn,Art\n" +
+			"M\xfcller,2021-05-06,Zeugenbeistand\n"
+		opts := csvimport.Options{
+			Defaults: map[string]string{"Stand": "abgeschlossen"},
+		}
+
+		rows, err := csvimport.Read(strings.NewReader(data), opts)
+		if err != nil {
+			t.Fatalf("reading CSV: %v", err)
+		}
+		if len(rows) != 1 {
+			t.Fatalf("wrong number of rows: expected 1, got %d", len(rows))
+		}
+		c := rows[0].Case
+		if c.Rubrum != "Müller" || c.Stand != "abgeschlossen" || c.Art != "Zeugenbeistand" {
+			t.Fatalf("wrong case: %#v", c)
+		}
+	})
+
+	t.Run("errors per row", func(t *testing.T) {
+		data := "Rubrum;Beginn;Art;Stand\n" +
+			"A;gestern;Verteidiger;laufend\n" +
+			"B;01.01.2020;Sachverständiger;laufend\n"
+
+		rows, err := csvimport.Read(strings.NewReader(data), csvimport.Options{})
+		if err != nil {
+			t.Fatalf("reading CSV: %v", err)
+		}
+		if !csvimport.HasErrors(rows) {
+			t.Fatalf("expected errors, got none")
+		}
+		expected := `field Beginn: invalid date "gestern"`
+		if len(rows[0].Errors) != 1 || rows[0].Errors[0] != expected {
+			t.Fatalf("wrong errors for first row: expected %q, got %v", expected, rows[0].Errors)
+		}
+		expected = `field Art: unknown role "Sachverständiger"`
+		if len(rows[1].Errors) != 2 || rows[1].Errors[0] != expected {
+			t.Fatalf("wrong errors for second row: expected %q first, got %v", expected, rows[1].Errors)
+		}
+	})
+
+	t.Run("unknown column in mapping", func(t *testing.T) {
+		opts := csvimport.Options{Mapping: map[string]string{"Rubrum": "Mandant"}}
+		_, err := csvimport.Read(strings.NewReader("Rubrum;Beginn\n"), opts)
+		expectedErrMsg := `column "Mandant" for field Rubrum not found in CSV header`
+		if err == nil || err.Error() != expectedErrMsg {
+			t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+		}
+	})
+}
+
+func TestParseAssignments(t *testing.T) {
+	got, err := csvimport.ParseAssignments([]string{"Rubrum=Mandant", " Beginn = Datum "})
+	if err != nil {
+		t.Fatalf("parsing assignments: %v", err)
+	}
+	if got["Rubrum"] != "Mandant" || got["Beginn"] != "Datum" {
+		t.Fatalf("wrong result: %v", got)
+	}
+
+	_, err = csvimport.ParseAssignments([]string{"Foo=Bar"})
+	expectedErrMsg := `unknown case field "Foo"`
+	if err == nil || err.Error() != expectedErrMsg {
+		t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+	}
+}
+
+func TestParseArt(t *testing.T) {
+	for input, expected := range map[string]string{
+		"Pflichtverteidiger":     "Verteidiger",
+		"Wahlverteidigung":       "Verteidiger",
+		"NK":                     "Nebenkläger",
+		"Nebenklagevertretung":   "Nebenkläger",
+		"Zeugenbeistand":         "Zeugenbeistand",
+		"Adhäsionsverfahren":     "Adhäsionskläger",
+		"Vertretung als Zeugin?": "Zeugenbeistand",
+		"Vernehmungsbeistand":    "Vernehmungsbeistand",
+		"Verletztenbeistand":     "Verletztenbeistand",
+	} {
+		got, ok := csvimport.ParseArt(input)
+		if !ok || got != expected {
+			t.Fatalf("wrong role for %q: expected %q, got %q", input, expected, got)
+		}
+	}
+	if _, ok := csvimport.ParseArt("Sachverständiger"); ok {
+		t.Fatalf("expected unknown role")
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Package enforcement computes the key dates of the enforcement of a prison
+sentence (Strafvollstreckung): the day when half and two thirds of the
+sentence are served, which allow the suspension of the remainder on probation
+(§ 57 Abs. 1 and 2 StGB), and the end of the sentence.
+
+Detention that is credited against the sentence (§ 51 StGB), usually
+pre-trial detention, counts day by day as served. The sentence is converted
+into days counted from its beginning, so a sentence of one month that begins
+on 15 March ends on 14 April (§ 37 Abs. 4 StVollstrO). If the credited
+detention already covers half or two thirds of the sentence, the respective
+date is the beginning of the enforcement. Interruptions of the enforcement are
+not taken into account.
+*/
+package enforcement
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout is the layout of all dates.
+const DateLayout = "2006-01-02"
+
+// Zeitraum is a period of detention that is credited against the sentence.
+// The first and the last day count.
+type Zeitraum struct {
+	Von string `json:"Von" validate:"required,datetime=2006-01-02"`
+	Bis string `json:"Bis" validate:"required,datetime=2006-01-02"`
+}
+
+// Strafe is a prison sentence to be enforced.
+type Strafe struct {
+	Monate int `json:"Monate" validate:"min=0"`
+	Tage   int `json:"Tage" validate:"min=0"`
+
+	// Beginn is the first day of the enforcement.
+	Beginn string `json:"Beginn" validate:"required,datetime=2006-01-02"`
+
+	Anrechnung []Zeitraum `json:"Anrechnung" validate:"dive"`
+}
+
+// Result contains the key dates of the enforcement. A date is empty if it
+// does not apply, see Begruendung.
+type Result struct {
+	// Tage is the sentence in days and Angerechnet the number of days of
+	// credited detention.
+	Tage        int `json:"Tage"`
+	Angerechnet int `json:"Angerechnet"`
+
+	Halbstrafe  string `json:"Halbstrafe,omitempty"`
+	ZweiDrittel string `json:"ZweiDrittel,omitempty"`
+	Strafende   string `json:"Strafende,omitempty"`
+
+	// Begruendung explains every step of the computation.
+	Begruendung []string `json:"Begruendung"`
+}
+
+// Date is a key date of the enforcement.
+type Date struct {
+	Art   string `json:"Art"`
+	Norm  string `json:"Norm"`
+	Datum string `json:"Datum"`
+}
+
+// Dates returns the key dates of the result in chronological order.
+func (r Result) Dates() []Date {
+	var result []Date
+	for _, d := range []Date{
+		{Art: "Halbstrafe", Norm: "§ 57 Abs. 2 StGB", Datum: r.Halbstrafe},
+		{Art: "Zwei-Drittel-Zeitpunkt", Norm: "§ 57 Abs. 1 StGB", Datum: r.ZweiDrittel},
+		{Art: "Strafende", Norm: "§ 37 StVollstrO", Datum: r.Strafende},
+	} {
+		if d.Datum != "" {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// Compute computes the key dates of the enforcement of the sentence.
+func Compute(s Strafe) (Result, error) {
+	if s.Monate == 0 && s.Tage == 0 {
+		return Result{}, fmt.Errorf("Monate or Tage is required")
+	}
+	begin, err := time.Parse(DateLayout, s.Beginn)
+	if err != nil {
+		return Result{}, fmt.Errorf("Beginn: %w", err)
+	}
+
+	r := Result{Tage: days(begin, begin.AddDate(0, s.Monate, s.Tage))}
+	r.Begruendung = append(r.Begruendung, fmt.Sprintf("%s are %d days from %s", sentence(s), r.Tage, s.Beginn))
+
+	for _, z := range s.Anrechnung {
+		von, err := time.Parse(DateLayout, z.Von)
+		if err != nil {
+			return Result{}, fmt.Errorf("Anrechnung: %w", err)
+		}
+		bis, err := time.Parse(DateLayout, z.Bis)
+		if err != nil {
+			return Result{}, fmt.Errorf("Anrechnung: %w", err)
+		}
+		if bis.Before(von) {
+			return Result{}, fmt.Errorf("Anrechnung: Bis %s is before Von %s", z.Bis, z.Von)
+		}
+		n := days(von, bis) + 1
+		r.Angerechnet += n
+		r.Begruendung = append(r.Begruendung, fmt.Sprintf("detention from %s to %s: %d days are credited (§ 51 Abs. 1 StGB)", z.Von, z.Bis, n))
+	}
+
+	if r.Angerechnet >= r.Tage {
+		r.Begruendung = append(r.Begruendung, "the sentence is served completely by the credited detention")
+		return r, nil
+	}
+
+	// served returns the day on which n days of the sentence are served. If
+	// the credited detention already covers them, it returns the beginning
+	// and true.
+	served := func(n int) (string, bool) {
+		if n <= r.Angerechnet {
+			return s.Beginn, true
+		}
+		return begin.AddDate(0, 0, n-r.Angerechnet-1).Format(DateLayout), false
+	}
+
+	twoThirds := ceilDiv(2*r.Tage, 3)
+	if minimum := days(begin, begin.AddDate(0, 2, 0)); twoThirds < minimum {
+		twoThirds = minimum
+		r.Begruendung = append(r.Begruendung, "at least two months must be served (§ 57 Abs. 1 Satz 1 Nr. 1 StGB)")
+	}
+	if twoThirds < r.Tage {
+		var reached bool
+		r.ZweiDrittel, reached = served(twoThirds)
+		if reached {
+			r.Begruendung = append(r.Begruendung, fmt.Sprintf("two thirds (%d days) are already reached by credited detention, so the date is the beginning %s (§ 57 Abs. 1 StGB)", twoThirds, r.ZweiDrittel))
+		} else {
+			r.Begruendung = append(r.Begruendung, fmt.Sprintf("two thirds are served after %d days on %s (§ 57 Abs. 1 StGB)", twoThirds, r.ZweiDrittel))
+		}
+	} else {
+		r.Begruendung = append(r.Begruendung, "no suspension after two thirds: the sentence ends before (§ 57 Abs. 1 StGB)")
+	}
+
+	half := ceilDiv(r.Tage, 2)
+	if minimum := days(begin, begin.AddDate(0, 6, 0)); half < minimum {
+		half = minimum
+		r.Begruendung = append(r.Begruendung, "at least six months must be served for the suspension after half of the sentence (§ 57 Abs. 2 StGB)")
+	}
+	if half < twoThirds && half < r.Tage {
+		var reached bool
+		r.Halbstrafe, reached = served(half)
+		nr := "Nr. 1: first prison sentence of at most two years"
+		if r.Tage > days(begin, begin.AddDate(2, 0, 0)) {
+			nr = "Nr. 2: special circumstances"
+		}
+		if reached {
+			r.Begruendung = append(r.Begruendung, fmt.Sprintf("half (%d days) is already reached by credited detention, so the date is the beginning %s (§ 57 Abs. 2 %s)", half, r.Halbstrafe, nr))
+		} else {
+			r.Begruendung = append(r.Begruendung, fmt.Sprintf("half is served after %d days on %s (§ 57 Abs. 2 %s)", half, r.Halbstrafe, nr))
+		}
+	} else {
+		r.Begruendung = append(r.Begruendung, "no suspension after half of the sentence: not before two thirds (§ 57 Abs. 2 StGB)")
+	}
+
+	r.Strafende, _ = served(r.Tage)
+	r.Begruendung = append(r.Begruendung, fmt.Sprintf("the sentence ends on %s", r.Strafende))
+	return r, nil
+}
+
+func days(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
+
+func sentence(s Strafe) string {
+	switch {
+	case s.Tage == 0:
+		return fmt.Sprintf("%d months", s.Monate)
+	case s.Monate == 0:
+		return fmt.Sprintf("%d days", s.Tage)
+	default:
+		return fmt.Sprintf("%d months and %d days", s.Monate, s.Tage)
+	}
+}
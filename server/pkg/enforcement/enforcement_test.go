@@ -0,0 +1,95 @@
+package enforcement_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/enforcement"
+)
+
+func TestCompute(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		s        enforcement.Strafe
+		expected string
+	}{
+		{
+			"two years with pre-trial detention",
+			enforcement.Strafe{Monate: 24, Beginn: "2025-03-15", Anrechnung: []enforcement.Zeitraum{{Von: "2024-11-01", Bis: "2025-01-31"}}},
+			"730 92 2025-12-12 2026-04-13 2026-12-12",
+		},
+		{
+			"no half-time because of six months minimum",
+			enforcement.Strafe{Monate: 4, Beginn: "2025-01-10"},
+			"120 0  2025-03-30 2025-05-09",
+		},
+		{
+			"no two-thirds because of two months minimum",
+			enforcement.Strafe{Monate: 1, Beginn: "2025-03-15"},
+			"31 0   2025-04-14",
+		},
+		{
+			"half reached by credited detention",
+			enforcement.Strafe{Monate: 24, Beginn: "2025-03-15", Anrechnung: []enforcement.Zeitraum{{Von: "2024-01-01", Bis: "2025-02-28"}}},
+			"730 425 2025-03-15 2025-05-15 2026-01-13",
+		},
+		{
+			"two thirds reached by credited detention",
+			enforcement.Strafe{Monate: 24, Beginn: "2025-03-15", Anrechnung: []enforcement.Zeitraum{{Von: "2023-09-01", Bis: "2025-02-28"}}},
+			"730 547 2025-03-15 2025-03-15 2025-09-13",
+		},
+		{
+			"served by credited detention",
+			enforcement.Strafe{Tage: 30, Beginn: "2025-03-15", Anrechnung: []enforcement.Zeitraum{{Von: "2025-01-01", Bis: "2025-02-09"}}},
+			"30 40   ",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := enforcement.Compute(tc.s)
+			if err != nil {
+				t.Fatalf("computing enforcement: %v", err)
+			}
+			got := fmt.Sprint(r.Tage, " ", r.Angerechnet, " ", r.Halbstrafe, " ", r.ZweiDrittel, " ", r.Strafende)
+			if got != tc.expected {
+				t.Fatalf("wrong result: expected %q, got %q\n%v", tc.expected, got, r.Begruendung)
+			}
+		})
+	}
+
+	t.Run("reasoning for dates reached by credited detention", func(t *testing.T) {
+		r, err := enforcement.Compute(enforcement.Strafe{Monate: 24, Beginn: "2025-03-15", Anrechnung: []enforcement.Zeitraum{{Von: "2023-09-01", Bis: "2025-02-28"}}})
+		if err != nil {
+			t.Fatalf("computing enforcement: %v", err)
+		}
+		expected := "two thirds (487 days) are already reached by credited detention, so the date is the beginning 2025-03-15 (§ 57 Abs. 1 StGB)"
+		if got := r.Begruendung[2]; got != expected {
+			t.Fatalf("wrong reasoning: expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("key dates", func(t *testing.T) {
+		r, err := enforcement.Compute(enforcement.Strafe{Monate: 4, Beginn: "2025-01-10"})
+		if err != nil {
+			t.Fatalf("computing enforcement: %v", err)
+		}
+		expected := "[{Zwei-Drittel-Zeitpunkt § 57 Abs. 1 StGB 2025-03-30} {Strafende § 37 StVollstrO 2025-05-09}]"
+		if got := fmt.Sprint(r.Dates()); got != expected {
+			t.Fatalf("wrong key dates: expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("invalid sentences", func(t *testing.T) {
+		for _, tc := range []struct {
+			s        enforcement.Strafe
+			expected string
+		}{
+			{enforcement.Strafe{Beginn: "2025-01-01"}, "Monate or Tage is required"},
+			{enforcement.Strafe{Monate: 6, Beginn: "2025-01-01", Anrechnung: []enforcement.Zeitraum{{Von: "2024-12-01", Bis: "2024-11-01"}}}, "Anrechnung: Bis 2024-11-01 is before Von 2024-12-01"},
+		} {
+			_, err := enforcement.Compute(tc.s)
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("expected error %q, got %v", tc.expected, err)
+			}
+		}
+	})
+}
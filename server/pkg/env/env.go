@@ -5,6 +5,7 @@ These are:
   FAO_STRAFRECHT_HOST
   FAO_STRAFRECHT_PORT
   FAO_STRAFRECHT_DSFILENAME
+  FAO_STRAFRECHT_MERGED_CASES
 */
 package env
 
@@ -17,6 +18,10 @@ const (
 	DefaultHost        = ""
 	DefaultPort        = "8000"
 	DefaultDSFilenname = "ds.jsonl"
+
+	// DefaultMergedCases counts joined cases as one case in the FAO report.
+	// Use "several" to count every joined case on its own.
+	DefaultMergedCases = "one"
 )
 
 // Environment provides all environment variables that are used in this module.
@@ -36,14 +41,19 @@ func (e Environment) DSFilename() string {
 	return e.vars["FAO_STRAFRECHT_DSFILENAME"]
 }
 
+func (e Environment) MergedCases() string {
+	return e.vars["FAO_STRAFRECHT_MERGED_CASES"]
+}
+
 // Parse creates the Environment struct with all environment variables retrieved
 // from the given function or with default value.
 func Parse(fn func(key string) string) (Environment, error) {
 	e := Environment{
 		vars: map[string]string{
-			"FAO_STRAFRECHT_HOST":       DefaultHost,
-			"FAO_STRAFRECHT_PORT":       DefaultPort,
-			"FAO_STRAFRECHT_DSFILENAME": DefaultDSFilenname,
+			"FAO_STRAFRECHT_HOST":         DefaultHost,
+			"FAO_STRAFRECHT_PORT":         DefaultPort,
+			"FAO_STRAFRECHT_DSFILENAME":   DefaultDSFilenname,
+			"FAO_STRAFRECHT_MERGED_CASES": DefaultMergedCases,
 		},
 	}
 
@@ -58,6 +68,10 @@ func Parse(fn func(key string) string) (Environment, error) {
 		return Environment{}, fmt.Errorf("invalid environment variable FAO_STRAFRECHT_PORT: %w", err)
 	}
 
+	if v := e.MergedCases(); v != "one" && v != "several" {
+		return Environment{}, fmt.Errorf("invalid environment variable FAO_STRAFRECHT_MERGED_CASES: expected one or several, got %q", v)
+	}
+
 	// TODO: Validate FAO_STRAFRECHT_DSFILENAME: https://stackoverflow.com/questions/35231846/golang-check-if-string-is-valid-path
 
 	return e, nil
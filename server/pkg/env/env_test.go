@@ -58,6 +58,20 @@ func TestEnvWithError(t *testing.T) {
 			t.Fatalf("setting environment: %v", err)
 		}
 
+		_, err := env.Parse(os.Getenv)
+		if err == nil {
+			t.Fatalf("expecting error, but got nil")
+		}
+	})
+	t.Run("bad merged cases value", func(t *testing.T) {
+		if err := os.Setenv("FAO_STRAFRECHT_PORT", "8000"); err != nil {
+			t.Fatalf("setting environment: %v", err)
+		}
+		if err := os.Setenv("FAO_STRAFRECHT_MERGED_CASES", "two"); err != nil {
+			t.Fatalf("setting environment: %v", err)
+		}
+		defer os.Unsetenv("FAO_STRAFRECHT_MERGED_CASES")
+
 		_, err := env.Parse(os.Getenv)
 		if err == nil {
 			t.Fatalf("expecting error, but got nil")
@@ -0,0 +1,107 @@
+/*
+Package export writes case lists as spreadsheets (CSV or XLSX) and invoices as
+PDF for people outside of this application, e.g. the office manager, the
+accountant or the client.
+*/
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+// Header contains the German column headers of all exports.
+var Header = []string{
+	"Nr.",
+	"Rubrum",
+	"Aktenzeichen",
+	"Gericht",
+	"Beginn",
+	"Ende",
+	"Gegenstand",
+	"Art der Tätigkeit",
+	"Beschreibung",
+	"Stand",
+}
+
+// cell is one value of the table. If date is not zero, the cell is a date and
+// text contains the German representation. If isNumber is true, the cell
+// contains the number instead of text.
+type cell struct {
+	text     string
+	number   int
+	isNumber bool
+	date     time.Time
+}
+
+// table returns all selected cases as rows sorted by id.
+func table(cs lawcase.Model, f lawcase.Filter) [][]cell {
+	var rows [][]cell
+	for _, id := range cs.IDs(f) {
+		c := cs[id]
+		rows = append(rows, []cell{
+			{number: id, isNumber: true},
+			{text: c.Rubrum},
+			{text: c.Az},
+			{text: c.Gericht},
+			dateCell(c.Beginn),
+			dateCell(c.Ende),
+			{text: c.Gegenstand},
+			{text: strings.Join(c.Roles(), ", ")},
+			{text: c.Beschreibung},
+			{text: c.Stand},
+		})
+	}
+	return rows
+}
+
+// dateCell returns a date cell if the value is a valid date and a text cell
+// otherwise.
+func dateCell(v string) cell {
+	t, err := lawcase.ParseDate(v)
+	if err != nil {
+		return cell{text: v}
+	}
+	return cell{text: t.Format("02.01.2006"), date: t}
+}
+
+// CSV writes all selected cases as CSV. It uses a semicolon as delimiter, CRLF
+// line endings and a UTF-8 byte order mark so that Excel with German locale
+// opens the file correctly.
+func CSV(w io.Writer, cs lawcase.Model, f lawcase.Filter) error {
+	if _, err := w.Write([]byte("\xef\xbb\xbf")); err != nil {
+		return fmt.Errorf("writing byte order mark: %w", err)
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = ';'
+	cw.UseCRLF = true
+
+	if err := cw.Write(Header); err != nil {
+		return fmt.Errorf("writing CSV header: %w", err)
+	}
+	for _, row := range table(cs, f) {
+		record := make([]string, len(row))
+		for i, c := range row {
+			if c.isNumber {
+				record[i] = strconv.Itoa(c.number)
+			} else {
+				record[i] = c.text
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("writing CSV record: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("flushing CSV writer: %w", err)
+	}
+	return nil
+}
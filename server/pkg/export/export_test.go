@@ -0,0 +1,137 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/export"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/invoice"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+var testCases = lawcase.Model{
+	2: {Rubrum: "Schulze; Kevin", Beginn: "2021-06-01", Ende: "noch anhängig", Art: "Nebenkläger", Stand: "laufend"},
+	1: {Rubrum: "Müller & Co.", Az: "000234/2022", Beginn: "2019-01-02", Ende: "2019-12-31", Art: "Verteidiger", Stand: "abgeschlossen"},
+}
+
+func TestCSV(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := export.CSV(buf, testCases, lawcase.Filter{}); err != nil {
+		t.Fatalf("writing CSV: %v", err)
+	}
+
+	expected := "\xef\xbb\xbf" +
+		"Nr.;Rubrum;Aktenzeichen;Gericht;Beginn;Ende;Gegenstand;Art der Tätigkeit;Beschreibung;Stand\r\n" +
+		"1;Müller & Co.;000234/2022;;02.01.2019;31.12.2019;;Verteidiger;;abgeschlossen\r\n" +
+		"2;\"Schulze; Kevin\";;;01.06.2021;noch anhängig;;Nebenkläger;;laufend\r\n"
+	if buf.String() != expected {
+		t.Fatalf("wrong CSV: expected %q, got %q", expected, buf.String())
+	}
+
+	t.Run("with filter", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		if err := export.CSV(buf, testCases, lawcase.Filter{Art: "Nebenkläger"}); err != nil {
+			t.Fatalf("writing CSV: %v", err)
+		}
+		if n := strings.Count(buf.String(), "\r\n"); n != 2 {
+			t.Fatalf("wrong number of lines: expected 2, got %d", n)
+		}
+	})
+}
+
+func TestXLSX(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := export.XLSX(buf, testCases, lawcase.Filter{}); err != nil {
+		t.Fatalf("writing XLSX: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("reading zip archive: %v", err)
+	}
+	var sheet string
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		if f.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("opening worksheet: %v", err)
+		}
+		b, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("reading worksheet: %v", err)
+		}
+		sheet = string(b)
+	}
+	if len(names) != 6 {
+		t.Fatalf("wrong files in archive: %v", names)
+	}
+
+	for _, expected := range []string{
+		`<c r="H1" s="1" t="inlineStr"><is><t>Art der Tätigkeit</t></is></c>`,
+		`<row r="2"><c r="A2"><v>1</v></c><c r="B2" t="inlineStr"><is><t xml:space="preserve">Müller &amp; Co.</t></is></c>`,
+		`<c r="E2" s="2"><v>43467</v></c>`,
+		`<c r="F3" t="inlineStr"><is><t xml:space="preserve">noch anhängig</t></is></c>`,
+	} {
+		if !strings.Contains(sheet, expected) {
+			t.Fatalf("worksheet does not contain %q: %s", expected, sheet)
+		}
+	}
+}
+
+func TestEuro(t *testing.T) {
+	for _, tc := range []struct {
+		cents    int
+		expected string
+	}{
+		{0, "0,00 €"},
+		{5, "0,05 €"},
+		{21450, "214,50 €"},
+		{123456789, "1.234.567,89 €"},
+		{-100000, "-1.000,00 €"},
+	} {
+		if got := export.Euro(tc.cents); got != tc.expected {
+			t.Fatalf("wrong amount for %d: expected %q, got %q", tc.cents, tc.expected, got)
+		}
+	}
+}
+
+func TestInvoicePDF(t *testing.T) {
+	inv := invoice.Invoice{
+		Nummer:     "2025-0002",
+		CaseID:     1,
+		Datum:      "2025-04-01",
+		Absender:   invoice.Absender{Name: "Kanzlei Muster", Anschrift: "Hauptstraße 1\n04109 Leipzig", Steuernummer: "231/123/45678"},
+		Empfaenger: invoice.Empfaenger{Name: "Max Müller", Anschrift: "Ring 2\n04109 Leipzig"},
+		Steuersatz: 19,
+		Netto:      -100000,
+		Storno:     1,
+	}
+	for i := 0; i < 40; i++ {
+		inv.Positionen = append(inv.Positionen, invoice.Position{Art: "Gebuehr", Nr: "4106", Bezeichnung: "Verfahrensgebühr", Betrag: -2500})
+	}
+
+	buf := new(bytes.Buffer)
+	if err := export.InvoicePDF(buf, inv, testCases[1], "2025-0001"); err != nil {
+		t.Fatalf("writing PDF: %v", err)
+	}
+	got := buf.String()
+	for _, expected := range []string{
+		"%PDF-1.4\n",
+		"(Stornorechnung)",
+		"Rechnung Nr. 2025-0001",
+		"(-1.000,00 \x80)",
+		"(Seite 2 von 2)",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Fatalf("PDF does not contain %q", expected)
+		}
+	}
+}
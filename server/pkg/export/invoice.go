@@ -0,0 +1,193 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/invoice"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/pdf"
+)
+
+const (
+	marginLeft   = 70
+	marginRight  = pdf.Width - 60
+	marginBottom = 110
+	lineHeight   = 15
+)
+
+// InvoicePDF writes the invoice of the case as PDF. For a cancellation invoice
+// cancelled is the number of the cancelled invoice.
+func InvoicePDF(w io.Writer, inv invoice.Invoice, c lawcase.Case, cancelled string) error {
+	doc := &pdf.Document{}
+	page := doc.AddPage()
+	pages := []*pdf.Page{page}
+
+	a := inv.Absender
+	page.Text(marginLeft, 790, 14, true, a.Name)
+	y := 775.0
+	for _, l := range lines(a.Anschrift) {
+		page.Text(marginLeft, y, 9, false, l)
+		y -= 11
+	}
+
+	page.Text(marginLeft, 700, 7, false, strings.Join(append([]string{a.Name}, lines(a.Anschrift)...), " · "))
+	page.Line(marginLeft, 696, marginLeft+240, 696)
+	y = 680
+	for _, l := range append([]string{inv.Empfaenger.Name}, lines(inv.Empfaenger.Anschrift)...) {
+		page.Text(marginLeft, y, 11, false, l)
+		y -= 14
+	}
+
+	y = 680
+	for _, f := range [][2]string{
+		{"Rechnungsnummer", inv.Nummer},
+		{"Rechnungsdatum", germanDate(inv.Datum)},
+		{"Mandat", c.Rubrum},
+		{"Aktenzeichen", c.Az},
+	} {
+		if f[1] == "" {
+			continue
+		}
+		page.Text(340, y, 9, false, f[0]+":")
+		page.Text(430, y, 9, false, f[1])
+		y -= 13
+	}
+
+	title := "Rechnung"
+	if inv.Storno != 0 {
+		title = "Stornorechnung"
+	}
+	page.Text(marginLeft, 570, 14, true, title)
+	y = 550
+	if inv.Storno != 0 {
+		page.Text(marginLeft, y, 10, false, fmt.Sprintf("Hiermit stornieren wir die Rechnung Nr. %s vollständig.", cancelled))
+		y -= 2 * lineHeight
+	}
+
+	header := func(p *pdf.Page, y float64) float64 {
+		p.Text(marginLeft, y, 10, true, "Nr.")
+		p.Text(marginLeft+50, y, 10, true, "Bezeichnung")
+		p.TextRight(marginRight, y, 10, true, "Betrag")
+		p.Line(marginLeft, y-5, marginRight, y-5)
+		return y - lineHeight - 5
+	}
+	y = header(page, y)
+
+	for _, pos := range inv.Positionen {
+		text := lines(wrap(pos.Bezeichnung, 60))
+		if len(text) == 0 {
+			text = []string{""}
+		}
+		if y-float64(len(text)-1)*lineHeight < marginBottom {
+			page = doc.AddPage()
+			pages = append(pages, page)
+			y = header(page, 780)
+		}
+		nr := pos.Nr
+		if nr != "" && pos.Art != "Verguetung" {
+			nr = "VV " + nr
+		}
+		page.Text(marginLeft, y, 10, false, nr)
+		page.TextRight(marginRight, y, 10, false, Euro(pos.Betrag))
+		for _, l := range text {
+			page.Text(marginLeft+50, y, 10, false, l)
+			y -= lineHeight
+		}
+	}
+
+	if y-4*lineHeight < marginBottom {
+		page = doc.AddPage()
+		pages = append(pages, page)
+		y = 780
+	}
+	page.Line(marginLeft, y+10, marginRight, y+10)
+	for _, t := range []struct {
+		label  string
+		amount int
+		bold   bool
+	}{
+		{"Nettobetrag", inv.Netto, false},
+		{fmt.Sprintf("Umsatzsteuer %d %%", inv.Steuersatz), inv.Umsatzsteuer, false},
+		{"Gesamtbetrag", inv.Brutto, true},
+	} {
+		page.Text(marginLeft+50, y, 10, t.bold, t.label)
+		page.TextRight(marginRight, y, 10, t.bold, Euro(t.amount))
+		y -= lineHeight
+	}
+
+	for i, p := range pages {
+		footer := []string{}
+		if a.Steuernummer != "" {
+			footer = append(footer, "Steuernummer: "+a.Steuernummer)
+		}
+		if a.Bankverbindung != "" {
+			footer = append(footer, a.Bankverbindung)
+		}
+		p.Line(marginLeft, 62, marginRight, 62)
+		p.Text(marginLeft, 50, 8, false, strings.Join(footer, " · "))
+		p.TextRight(marginRight, 50, 8, false, fmt.Sprintf("Seite %d von %d", i+1, len(pages)))
+	}
+
+	_, err := doc.WriteTo(w)
+	return err
+}
+
+// Euro formats the amount in cents in German notation like "-1.234,56 €".
+func Euro(cents int) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	euros := fmt.Sprint(cents / 100)
+	var groups []string
+	for len(euros) > 3 {
+		groups = append([]string{euros[len(euros)-3:]}, groups...)
+		euros = euros[:len(euros)-3]
+	}
+	groups = append([]string{euros}, groups...)
+	return fmt.Sprintf("%s%s,%02d €", sign, strings.Join(groups, "."), cents%100)
+}
+
+func germanDate(s string) string {
+	t, err := time.Parse(invoice.DateLayout, s)
+	if err != nil {
+		return s
+	}
+	return t.Format("02.01.2006")
+}
+
+// lines splits the text into its non-empty lines.
+func lines(text string) []string {
+	var result []string
+	for _, l := range strings.Split(text, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			result = append(result, l)
+		}
+	}
+	return result
+}
+
+// wrap breaks the text into lines of at most width characters between words.
+func wrap(text string, width int) string {
+	var result, line []string
+	length := 0
+	for _, word := range strings.Fields(text) {
+		if length > 0 && length+1+len([]rune(word)) > width {
+			result = append(result, strings.Join(line, " "))
+			line, length = nil, 0
+		}
+		if length > 0 {
+			length++
+		}
+		line = append(line, word)
+		length += len([]rune(word))
+	}
+	if len(line) > 0 {
+		result = append(result, strings.Join(line, " "))
+	}
+	return strings.Join(result, "\n")
+}
@@ -0,0 +1,156 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+// The static parts of an Office Open XML workbook with one worksheet. The
+// styles contain a bold font for the header (style 1) and the default date
+// format (style 2).
+var xlsxStaticFiles = []struct {
+	name    string
+	content string
+}{
+	{
+		"[Content_Types].xml",
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			`</Types>`,
+	},
+	{
+		"_rels/.rels",
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		"xl/workbook.xml",
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Fälle" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`,
+	},
+	{
+		"xl/_rels/workbook.xml.rels",
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+			`</Relationships>`,
+	},
+	{
+		"xl/styles.xml",
+		`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+			`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+			`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+			`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+			`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+			`<cellXfs count="3">` +
+			`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+			`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+			`<xf numFmtId="14" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+			`</cellXfs>` +
+			`</styleSheet>`,
+	},
+}
+
+// XLSX writes all selected cases as Excel workbook.
+func XLSX(w io.Writer, cs lawcase.Model, f lawcase.Filter) error {
+	zw := zip.NewWriter(w)
+
+	for _, file := range xlsxStaticFiles {
+		fw, err := zw.Create(file.name)
+		if err != nil {
+			return fmt.Errorf("creating %s in zip archive: %w", file.name, err)
+		}
+		if _, err := io.WriteString(fw, file.content); err != nil {
+			return fmt.Errorf("writing %s: %w", file.name, err)
+		}
+	}
+
+	fw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return fmt.Errorf("creating worksheet in zip archive: %w", err)
+	}
+	if _, err := io.WriteString(fw, worksheet(cs, f)); err != nil {
+		return fmt.Errorf("writing worksheet: %w", err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("closing zip archive: %w", err)
+	}
+	return nil
+}
+
+func worksheet(cs lawcase.Model, f lawcase.Filter) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<sheetData>`)
+
+	b.WriteString(`<row r="1">`)
+	for i, h := range Header {
+		fmt.Fprintf(&b, `<c r="%s1" s="1" t="inlineStr"><is><t>%s</t></is></c>`, columnName(i), escape(h))
+	}
+	b.WriteString(`</row>`)
+
+	for n, row := range table(cs, f) {
+		r := n + 2
+		fmt.Fprintf(&b, `<row r="%d">`, r)
+		for i, c := range row {
+			ref := columnName(i) + strconv.Itoa(r)
+			switch {
+			case c.isNumber:
+				fmt.Fprintf(&b, `<c r="%s"><v>%d</v></c>`, ref, c.number)
+			case !c.date.IsZero():
+				fmt.Fprintf(&b, `<c r="%s" s="2"><v>%d</v></c>`, ref, serialDate(c.date))
+			case c.text != "":
+				fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, escape(c.text))
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+
+	b.WriteString(`</sheetData>`)
+	b.WriteString(`</worksheet>`)
+	return b.String()
+}
+
+// columnName returns the spreadsheet column name (A, B, ..., Z, AA, ...) for
+// the zero based index.
+func columnName(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+	return name
+}
+
+// serialDate returns the number of days since 1899-12-30 which is how
+// spreadsheets store dates.
+func serialDate(t time.Time) int {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	return int(t.Sub(epoch).Hours() / 24)
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,187 @@
+/*
+Package fao creates the report of cases and hearing days needed for the
+application for the title Fachanwalt für Strafrecht (§ 5 Abs. 1 lit. f FAO:
+60 cases including at least 40 days of main hearing before the
+Schöffengericht or a higher court).
+*/
+package fao
+
+import (
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/court"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/proceeding"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/stats"
+)
+
+// Report is the FAO report. Faelle is the number of matters, i. e. all
+// instances of one proceeding count as one case. Hauptverhandlungstage is the
+// number of hearing days before the Schöffengericht or a higher court.
+type Report struct {
+	Faelle                int      `json:"Faelle"`
+	Hauptverhandlungstage int      `json:"Hauptverhandlungstage"`
+	Verfahren             []Matter `json:"Verfahren"`
+}
+
+// Matter is one matter of the report. ProceedingID is 0 for cases that do not
+// belong to a proceeding.
+type Matter struct {
+	ProceedingID int        `json:"ProceedingID,omitempty"`
+	Bezeichnung  string     `json:"Bezeichnung"`
+	Instanzen    []Instance `json:"Instanzen"`
+}
+
+// Instance is one case of a matter with its hearing days. Qualifiziert is true
+// if the hearing days count for the FAO.
+type Instance struct {
+	CaseID                int    `json:"CaseID"`
+	Stufe                 string `json:"Stufe,omitempty"`
+	Gericht               string `json:"Gericht"`
+	Ergebnis              string `json:"Ergebnis,omitempty"`
+	Hauptverhandlungstage int    `json:"Hauptverhandlungstage"`
+	Qualifiziert          bool   `json:"Qualifiziert"`
+}
+
+// Options configure the report.
+type Options struct {
+	// MergedAsOne counts a case joined to another case (see
+	// lawcase.Verbindung) as part of the matter of that case. Otherwise a
+	// joined case is a matter of its own.
+	MergedAsOne bool
+}
+
+// Compute returns the report for all cases selected by the filter. A
+// proceeding is in the report if at least one of its instances is selected;
+// then all its instances are listed. Hearing days are counted per instance and
+// only if they are within the date range of the filter.
+func Compute(cs lawcase.Model, courts court.Model, ps proceeding.Model, f lawcase.Filter, o Options) Report {
+	selected := make(map[int]bool)
+	for _, id := range cs.IDs(f) {
+		selected[id] = true
+	}
+
+	// Every case belongs to the matter of its root case, i. e. the case it
+	// was joined to if MergedAsOne is set or the case itself. The root case
+	// belongs to its proceeding if there is one.
+	joined := make(map[int][]int)
+	for _, id := range cs.IDs(lawcase.Filter{}) {
+		if root := rootCase(cs, id, o); root != id {
+			joined[root] = append(joined[root], id)
+		}
+	}
+
+	r := Report{Verfahren: []Matter{}}
+	add := func(m Matter, ids []int) {
+		var found bool
+		for _, id := range ids {
+			found = found || selected[id]
+		}
+		if !found {
+			return
+		}
+		r.Faelle++
+		for _, i := range m.Instanzen {
+			if i.Qualifiziert {
+				r.Hauptverhandlungstage += i.Hauptverhandlungstage
+			}
+		}
+		r.Verfahren = append(r.Verfahren, m)
+	}
+	withJoined := func(m *Matter, ids []int, root int) []int {
+		for _, id := range joined[root] {
+			m.Instanzen = append(m.Instanzen, instance(id, cs[id], courts, f))
+			ids = append(ids, id)
+		}
+		return ids
+	}
+
+	inProceeding := make(map[int]bool)
+	for _, pid := range ps.IDs() {
+		p := ps[pid]
+		m := Matter{ProceedingID: pid, Bezeichnung: p.Bezeichnung}
+		var ids []int
+		for _, i := range p.Instanzen {
+			c, ok := cs[i.CaseID]
+			if !ok || rootCase(cs, i.CaseID, o) != i.CaseID {
+				continue
+			}
+			inProceeding[i.CaseID] = true
+			inst := instance(i.CaseID, c, courts, f)
+			inst.Stufe = i.Stufe
+			inst.Ergebnis = i.Ergebnis
+			m.Instanzen = append(m.Instanzen, inst)
+			ids = append(ids, i.CaseID)
+		}
+		for _, i := range p.Instanzen {
+			if inProceeding[i.CaseID] {
+				ids = withJoined(&m, ids, i.CaseID)
+			}
+		}
+		add(m, ids)
+	}
+
+	for _, id := range cs.IDs(lawcase.Filter{}) {
+		if inProceeding[id] || rootCase(cs, id, o) != id {
+			continue
+		}
+		c := cs[id]
+		m := Matter{
+			Bezeichnung: c.Rubrum,
+			Instanzen:   []Instance{instance(id, c, courts, f)},
+		}
+		add(m, withJoined(&m, []int{id}, id))
+	}
+	return r
+}
+
+// rootCase returns the case the given case was joined to, following joinders
+// of several levels. Without MergedAsOne it is the case itself.
+func rootCase(cs lawcase.Model, id int, o Options) int {
+	if !o.MergedAsOne {
+		return id
+	}
+	seen := map[int]bool{id: true}
+	for {
+		v := cs[id].VerbundenIn
+		if v == nil || seen[v.CaseID] {
+			return id
+		}
+		if _, ok := cs[v.CaseID]; !ok {
+			return id
+		}
+		id = v.CaseID
+		seen[id] = true
+	}
+}
+
+func instance(id int, c lawcase.Case, courts court.Model, f lawcase.Filter) Instance {
+	i := Instance{
+		CaseID:                id,
+		Gericht:               stats.CourtName(c.Gericht),
+		Hauptverhandlungstage: HearingDays(c, f.From, f.To),
+		Qualifiziert:          stats.IsHearingIntensive(c),
+	}
+	if g, ok := courts[c.GerichtID]; ok {
+		i.Gericht = g.Name
+		i.Qualifiziert = i.Qualifiziert || g.Instanz != "AG"
+	}
+	return i
+}
+
+// HearingDays returns the number of different hearing days of the case within
+// the given range. A zero time means no limit. Invalid dates are ignored.
+func HearingDays(c lawcase.Case, from, to time.Time) int {
+	days := make(map[time.Time]bool)
+	for _, d := range c.Hauptverhandlungstage {
+		t, err := lawcase.ParseDate(d)
+		if err != nil {
+			continue
+		}
+		if !from.IsZero() && t.Before(from) || !to.IsZero() && t.After(to) {
+			continue
+		}
+		days[t] = true
+	}
+	return len(days)
+}
@@ -0,0 +1,84 @@
+package fao_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/fao"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/court"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/proceeding"
+)
+
+func TestCompute(t *testing.T) {
+	cs := lawcase.Model{
+		1: {Rubrum: "Müller", Beginn: "2021-01-10", Gericht: "AG Leipzig, Schöffengericht", Hauptverhandlungstage: []string{"2021-05-03", "2021-05-10"}},
+		2: {Rubrum: "Müller", Beginn: "2021-08-01", GerichtID: 1, Hauptverhandlungstage: []string{"2021-11-02", "2021-11-03", "2021-11-03"}},
+		3: {Rubrum: "Schulze", Beginn: "2022-02-01", Gericht: "AG Leipzig, Strafrichter", Hauptverhandlungstage: []string{"2022-03-01"}},
+		4: {Rubrum: "Meier", Beginn: "2015-02-01", Ende: "2015-06-01", Gericht: "LG Leipzig", Hauptverhandlungstage: []string{"2015-03-01"}},
+	}
+	courts := court.Model{1: {Name: "Landgericht Leipzig", Ort: "Leipzig", Instanz: "LG"}}
+	ps := proceeding.Model{
+		1: {Bezeichnung: "Müller wegen Betrugs", Instanzen: []proceeding.Instanz{
+			{CaseID: 1, Stufe: "Erstinstanz"},
+			{CaseID: 2, Stufe: "Berufung", Ergebnis: "verworfen"},
+		}},
+	}
+
+	t.Run("all cases", func(t *testing.T) {
+		r := fao.Compute(cs, courts, ps, lawcase.Filter{}, fao.Options{})
+		if r.Faelle != 3 {
+			t.Fatalf("wrong number of cases: expected 3, got %d", r.Faelle)
+		}
+		if r.Hauptverhandlungstage != 5 {
+			t.Fatalf("wrong number of hearing days: expected 5, got %d", r.Hauptverhandlungstage)
+		}
+		m := r.Verfahren[0]
+		if m.ProceedingID != 1 || len(m.Instanzen) != 2 {
+			t.Fatalf("wrong first matter: %v", m)
+		}
+		if i := m.Instanzen[1]; i.Gericht != "Landgericht Leipzig" || i.Hauptverhandlungstage != 2 || !i.Qualifiziert {
+			t.Fatalf("wrong second instance: %v", i)
+		}
+		if i := r.Verfahren[1].Instanzen[0]; i.CaseID != 3 || i.Qualifiziert {
+			t.Fatalf("wrong instance of case 3: %v", i)
+		}
+	})
+
+	t.Run("date range", func(t *testing.T) {
+		f := lawcase.Filter{
+			From: time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC),
+			To:   time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC),
+		}
+		r := fao.Compute(cs, courts, ps, f, fao.Options{})
+		if r.Faelle != 2 {
+			t.Fatalf("wrong number of cases: expected 2, got %d", r.Faelle)
+		}
+		if r.Hauptverhandlungstage != 2 {
+			t.Fatalf("wrong number of hearing days: expected 2, got %d", r.Hauptverhandlungstage)
+		}
+	})
+}
+
+func TestComputeMerged(t *testing.T) {
+	cs := lawcase.Model{
+		1: {Rubrum: "A", Beginn: "2021-01-10", Gericht: "LG Leipzig", Hauptverhandlungstage: []string{"2021-05-03"}},
+		2: {Rubrum: "B", Beginn: "2021-02-10", Gericht: "LG Leipzig", Hauptverhandlungstage: []string{"2021-04-01"}, VerbundenIn: &lawcase.Verbindung{CaseID: 1, Datum: "2021-04-15"}},
+	}
+
+	for _, tc := range []struct {
+		mergedAsOne bool
+		faelle      int
+	}{
+		{true, 1},
+		{false, 2},
+	} {
+		r := fao.Compute(cs, court.Model{}, proceeding.Model{}, lawcase.Filter{}, fao.Options{MergedAsOne: tc.mergedAsOne})
+		if r.Faelle != tc.faelle {
+			t.Fatalf("wrong number of cases with MergedAsOne %v: expected %d, got %d", tc.mergedAsOne, tc.faelle, r.Faelle)
+		}
+		if r.Hauptverhandlungstage != 2 {
+			t.Fatalf("wrong number of hearing days with MergedAsOne %v: expected 2, got %d", tc.mergedAsOne, r.Hauptverhandlungstage)
+		}
+	}
+}
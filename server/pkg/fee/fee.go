@@ -0,0 +1,344 @@
+/*
+Package fee proposes the statutory fees of defence counsel and other counsel
+in criminal matters under the RVG (Teil 4 Abschnitt 1 VV RVG).
+
+A case is one stage of the proceeding and one matter (Angelegenheit, § 17 Nr.
+10a RVG). Its fees are the Grundgebühr if it is the first stage of the
+proceeding, the Verfahrensgebühr of the stage and court, a Terminsgebühr for
+every hearing day and the additional fee of Nr. 4141 VV RVG if the proceeding
+ends without a hearing. The fees with surcharge (Zuschlag) are used if the
+client is in custody. Fees of retained counsel are proposed at the middle of
+the range (§ 14 RVG), court-appointed counsel gets the fixed amounts. The flat
+rate for post and telecommunication (Nr. 7002 VV RVG) and the value added tax
+(Nr. 7008 VV RVG) are added.
+
+All amounts are in cents.
+*/
+package fee
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/court"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/proceeding"
+)
+
+//go:embed table.csv
+var tableData []byte
+
+// TableValidFrom is the day the fee table came into force.
+const TableValidFrom = "2025-06-01"
+
+const (
+	// Pauschale is the flat rate of Nr. 7002 VV RVG in percent of the fees,
+	// at most Hoechstpauschale.
+	Pauschale        = 20
+	Hoechstpauschale = 2000
+
+	// Umsatzsteuer is the rate of the value added tax in percent.
+	Umsatzsteuer = 19
+)
+
+// Gebuehr is a fee of the VV RVG. Retained counsel gets an amount within the
+// range, court-appointed counsel the fixed amount Pflichtverteidiger.
+type Gebuehr struct {
+	Nr                 string `json:"Nr"`
+	Bezeichnung        string `json:"Bezeichnung"`
+	Mindestgebuehr     int    `json:"Mindestgebuehr"`
+	Hoechstgebuehr     int    `json:"Hoechstgebuehr"`
+	Pflichtverteidiger int    `json:"Pflichtverteidiger"`
+}
+
+// Mittelgebuehr returns the middle of the range.
+func (g Gebuehr) Mittelgebuehr() int {
+	return (g.Mindestgebuehr + g.Hoechstgebuehr) / 2
+}
+
+// Betrag returns the proposed amount for court-appointed or retained counsel.
+func (g Gebuehr) Betrag(pflichtverteidiger bool) int {
+	if pflichtverteidiger {
+		return g.Pflichtverteidiger
+	}
+	return g.Mittelgebuehr()
+}
+
+var table = mustParse(tableData)
+
+// Table returns all fees ordered by number.
+func Table() []Gebuehr {
+	return append([]Gebuehr{}, table...)
+}
+
+// Lookup returns the fee with the given number.
+func Lookup(nr string) (Gebuehr, bool) {
+	for _, g := range table {
+		if g.Nr == nr {
+			return g, true
+		}
+	}
+	return Gebuehr{}, false
+}
+
+func mustParse(data []byte) []Gebuehr {
+	l, err := parse(data)
+	if err != nil {
+		panic(fmt.Sprintf("parsing embedded fee table: %v", err))
+	}
+	return l
+}
+
+func parse(data []byte) ([]Gebuehr, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = ';'
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing header")
+	}
+
+	var result []Gebuehr
+	for i, rec := range records[1:] {
+		line := i + 2
+		g := Gebuehr{Nr: rec[0], Bezeichnung: rec[1]}
+		for j, f := range []*int{&g.Mindestgebuehr, &g.Hoechstgebuehr, &g.Pflichtverteidiger} {
+			euro, err := strconv.Atoi(rec[j+2])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid amount %q", line, rec[j+2])
+			}
+			*f = euro * 100
+		}
+		if g.Mindestgebuehr > g.Hoechstgebuehr {
+			return nil, fmt.Errorf("line %d: Mindestgebuehr is greater than Hoechstgebuehr", line)
+		}
+		result = append(result, g)
+	}
+	return result, nil
+}
+
+// Termin is a hearing day.
+type Termin struct {
+	Datum string `json:"Datum"`
+
+	// Haft is set if the client is in custody on this day.
+	Haft bool `json:"Haft"`
+}
+
+// Verfahren contains everything that determines the fees of a case.
+type Verfahren struct {
+	Stufe string `json:"Stufe" validate:"oneof=Ermittlungsverfahren Erstinstanz Berufung Revision"`
+
+	// Gericht is the court of the first instance.
+	Gericht string `json:"Gericht" validate:"omitempty,oneof=Amtsgericht Strafkammer Schwurgericht Oberlandesgericht"`
+
+	Pflichtverteidiger bool `json:"Pflichtverteidiger"`
+
+	// Grundgebuehr is set if the case is the first stage of the proceeding
+	// we work on.
+	Grundgebuehr bool `json:"Grundgebuehr"`
+
+	// Haft is set if the client is in custody during the stage.
+	Haft bool `json:"Haft"`
+
+	Termine []Termin `json:"Termine"`
+
+	// Einstellung is set if the proceeding ended by dismissal without a
+	// hearing (Nr. 4141 Anm. 1 VV RVG).
+	Einstellung bool `json:"Einstellung"`
+}
+
+// Position is one item of the proposal.
+type Position struct {
+	Nr          string `json:"Nr"`
+	Bezeichnung string `json:"Bezeichnung"`
+	Datum       string `json:"Datum,omitempty"`
+	Betrag      int    `json:"Betrag"`
+}
+
+// Vorschlag is the proposal of the fees of a case.
+type Vorschlag struct {
+	Verfahren  Verfahren  `json:"Verfahren"`
+	Positionen []Position `json:"Positionen"`
+
+	Gebuehren    int `json:"Gebuehren"`
+	Auslagen     int `json:"Auslagen"`
+	Netto        int `json:"Netto"`
+	Umsatzsteuer int `json:"Umsatzsteuer"`
+	Brutto       int `json:"Brutto"`
+
+	// Hinweise are notes on fees that are not taken into account.
+	Hinweise []string `json:"Hinweise"`
+}
+
+// Propose proposes the fees for the given stage.
+func Propose(v Verfahren) (Vorschlag, error) {
+	verfahren, termin, err := numbers(v.Stufe, v.Gericht)
+	if err != nil {
+		return Vorschlag{}, err
+	}
+
+	p := Vorschlag{Verfahren: v, Positionen: []Position{}, Hinweise: []string{}}
+	add := func(nr, datum string) error {
+		g, ok := Lookup(nr)
+		if !ok {
+			return fmt.Errorf("unknown fee Nr. %s VV RVG", nr)
+		}
+		p.Positionen = append(p.Positionen, Position{Nr: g.Nr, Bezeichnung: g.Bezeichnung, Datum: datum, Betrag: g.Betrag(v.Pflichtverteidiger)})
+		return nil
+	}
+
+	if v.Grundgebuehr {
+		if err := add(surcharge("4100", v.Haft), ""); err != nil {
+			return Vorschlag{}, err
+		}
+	}
+	if err := add(surcharge(verfahren, v.Haft), ""); err != nil {
+		return Vorschlag{}, err
+	}
+	if termin == "" && len(v.Termine) > 0 {
+		p.Hinweise = append(p.Hinweise, fmt.Sprintf("%d hearing day(s) in the %s are not taken into account", len(v.Termine), v.Stufe))
+	}
+	if termin != "" {
+		for _, t := range v.Termine {
+			if err := add(surcharge(termin, t.Haft), t.Datum); err != nil {
+				return Vorschlag{}, err
+			}
+		}
+	}
+	if v.Einstellung && len(v.Termine) == 0 {
+		g, _ := Lookup(verfahren)
+		p.Positionen = append(p.Positionen, Position{
+			Nr:          "4141",
+			Bezeichnung: "Zusätzliche Gebühr bei Einstellung",
+			Betrag:      g.Betrag(v.Pflichtverteidiger),
+		})
+	}
+
+	for _, pos := range p.Positionen {
+		p.Gebuehren += pos.Betrag
+	}
+	p.Auslagen = p.Gebuehren * Pauschale / 100
+	if p.Auslagen > Hoechstpauschale {
+		p.Auslagen = Hoechstpauschale
+	}
+	p.Positionen = append(p.Positionen, Position{Nr: "7002", Bezeichnung: "Pauschale für Post- und Telekommunikationsentgelte", Betrag: p.Auslagen})
+	p.Netto = p.Gebuehren + p.Auslagen
+	p.Umsatzsteuer = (p.Netto*Umsatzsteuer + 50) / 100
+	p.Positionen = append(p.Positionen, Position{Nr: "7008", Bezeichnung: fmt.Sprintf("Umsatzsteuer %d %%", Umsatzsteuer), Betrag: p.Umsatzsteuer})
+	p.Brutto = p.Netto + p.Umsatzsteuer
+
+	if v.Pflichtverteidiger {
+		p.Hinweise = append(p.Hinweise, "surcharges for hearing days of more than five hours (Nr. 4110, 4111 VV RVG and following) are not taken into account")
+	} else {
+		p.Hinweise = append(p.Hinweise, "fees of retained counsel are proposed at the middle of the range, see § 14 RVG")
+	}
+	return p, nil
+}
+
+// numbers returns the numbers of the Verfahrensgebühr and the Terminsgebühr
+// of the stage without surcharge. The latter is empty for the preliminary
+// proceeding.
+func numbers(stufe, gericht string) (string, string, error) {
+	switch stufe {
+	case "Ermittlungsverfahren":
+		return "4104", "", nil
+	case "Erstinstanz":
+		switch gericht {
+		case "Amtsgericht", "":
+			return "4106", "4108", nil
+		case "Strafkammer":
+			return "4112", "4114", nil
+		case "Schwurgericht", "Oberlandesgericht":
+			return "4118", "4120", nil
+		default:
+			return "", "", fmt.Errorf("unknown Gericht %q", gericht)
+		}
+	case "Berufung":
+		return "4124", "4126", nil
+	case "Revision":
+		return "4130", "4132", nil
+	default:
+		return "", "", fmt.Errorf("no fees for Stufe %q", stufe)
+	}
+}
+
+// surcharge returns the number of the fee with surcharge which is the next
+// number (Vorbem. 4 Abs. 4 VV RVG).
+func surcharge(nr string, haft bool) string {
+	if !haft {
+		return nr
+	}
+	n, _ := strconv.Atoi(nr)
+	return strconv.Itoa(n + 1)
+}
+
+// FromCase returns the parameters of the fees of the case. The stage is taken
+// from the proceeding of the case. Cases that are not part of a proceeding are
+// taken as the first instance if they have a court and as the preliminary
+// proceeding otherwise.
+func FromCase(id int, c lawcase.Case, courts court.Model, ps proceeding.Model) Verfahren {
+	v := Verfahren{
+		Stufe:        "Ermittlungsverfahren",
+		Gericht:      Gericht(c, courts),
+		Grundgebuehr: true,
+		Haft:         c.Haft != nil,
+	}
+	if c.Gericht != "" || c.GerichtID != 0 || len(c.Hauptverhandlungstage) > 0 {
+		v.Stufe = "Erstinstanz"
+	}
+	if pid, ok := ps.OfCase(id); ok {
+		for i, inst := range ps[pid].Instanzen {
+			if inst.CaseID == id {
+				v.Stufe = inst.Stufe
+				v.Grundgebuehr = i == 0
+			}
+		}
+	}
+	for _, d := range c.Hauptverhandlungstage {
+		v.Termine = append(v.Termine, Termin{Datum: d, Haft: inCustody(c.Haft, d)})
+	}
+	if a := c.Abschluss; a != nil && len(v.Termine) == 0 {
+		switch a.Ergebnis {
+		case "Einstellung153", "Einstellung153a", "Einstellung170":
+			v.Einstellung = true
+		}
+	}
+	return v
+}
+
+// Gericht returns the kind of court of the first instance that determines the
+// fees: Amtsgericht, Strafkammer, Schwurgericht or Oberlandesgericht. The
+// court directory is used if the case references a court, the free-text
+// field Gericht otherwise.
+func Gericht(c lawcase.Case, courts court.Model) string {
+	text := strings.ToLower(c.Gericht)
+	var instanz string
+	if g, ok := courts[c.GerichtID]; ok {
+		instanz = g.Instanz
+		text += " " + strings.ToLower(g.Name)
+	}
+	switch {
+	case strings.Contains(text, "schwurgericht"):
+		return "Schwurgericht"
+	case instanz == "OLG" || strings.HasPrefix(text, "olg") || strings.Contains(text, "oberlandesgericht"):
+		return "Oberlandesgericht"
+	case instanz == "LG" || strings.HasPrefix(text, "lg") || strings.Contains(text, "landgericht") || strings.Contains(text, "strafkammer"):
+		return "Strafkammer"
+	default:
+		return "Amtsgericht"
+	}
+}
+
+func inCustody(h *lawcase.Haft, day string) bool {
+	if h == nil || h.Festnahme == "" || day < h.Festnahme {
+		return false
+	}
+	return h.Entlassung == "" || day <= h.Entlassung
+}
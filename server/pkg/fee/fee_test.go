@@ -0,0 +1,113 @@
+package fee_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/fee"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/court"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/proceeding"
+)
+
+func TestTable(t *testing.T) {
+	for _, g := range fee.Table() {
+		if g.Pflichtverteidiger < g.Mindestgebuehr || g.Pflichtverteidiger > g.Hoechstgebuehr {
+			t.Fatalf("wrong fee Nr. %s: fixed amount %d is not within the range", g.Nr, g.Pflichtverteidiger)
+		}
+	}
+	g, ok := fee.Lookup("4100")
+	if !ok || g.Mittelgebuehr() != 24000 || g.Betrag(true) != 19200 {
+		t.Fatalf("wrong fee Nr. 4100: %+v", g)
+	}
+}
+
+func TestPropose(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		v        fee.Verfahren
+		expected string
+	}{
+		{
+			"retained counsel at the Amtsgericht",
+			fee.Verfahren{Stufe: "Erstinstanz", Gericht: "Amtsgericht", Grundgebuehr: true, Termine: []fee.Termin{{Datum: "2025-03-01"}, {Datum: "2025-03-08"}}},
+			"[4100:24000 4106:19800 4108:33000 4108:33000 7002:2000 7008:21242] 109800 111800 133042",
+		},
+		{
+			"court-appointed counsel with client in custody",
+			fee.Verfahren{Stufe: "Erstinstanz", Gericht: "Strafkammer", Pflichtverteidiger: true, Grundgebuehr: true, Haft: true, Termine: []fee.Termin{{Datum: "2025-03-01", Haft: true}, {Datum: "2025-03-08"}}},
+			"[4101:23500 4113:21600 4115:37400 4114:30700 7002:2000 7008:21888] 113200 115200 137088",
+		},
+		{
+			"dismissal in the preliminary proceeding",
+			fee.Verfahren{Stufe: "Ermittlungsverfahren", Grundgebuehr: true, Einstellung: true},
+			"[4100:24000 4104:19800 4141:19800 7002:2000 7008:12464] 63600 65600 78064",
+		},
+		{
+			"appeal",
+			fee.Verfahren{Stufe: "Revision", Pflichtverteidiger: true},
+			"[4130:59000 7002:2000 7008:11590] 59000 61000 72590",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := fee.Propose(tc.v)
+			if err != nil {
+				t.Fatalf("proposing fees: %v", err)
+			}
+			var positions []string
+			for _, pos := range p.Positionen {
+				positions = append(positions, fmt.Sprintf("%s:%d", pos.Nr, pos.Betrag))
+			}
+			got := fmt.Sprint(positions, " ", p.Gebuehren, " ", p.Netto, " ", p.Brutto)
+			if got != tc.expected {
+				t.Fatalf("wrong proposal: expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+
+	t.Run("unsupported stage", func(t *testing.T) {
+		_, err := fee.Propose(fee.Verfahren{Stufe: "Beschwerde"})
+		expected := `no fees for Stufe "Beschwerde"`
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected error %q, got %v", expected, err)
+		}
+	})
+}
+
+func TestFromCase(t *testing.T) {
+	courts := court.Model{1: {Name: "Landgericht Leipzig", Ort: "Leipzig", Instanz: "LG"}}
+	cs := lawcase.Model{
+		1: {Gericht: "AG Leipzig", Hauptverhandlungstage: []string{"2025-01-10"}},
+		2: {
+			GerichtID:             1,
+			Hauptverhandlungstage: []string{"2025-05-02", "2025-05-09"},
+			Haft:                  &lawcase.Haft{Festnahme: "2025-04-01", Entlassung: "2025-05-02"},
+		},
+		3: {Abschluss: &lawcase.Abschluss{Ergebnis: "Einstellung170"}},
+	}
+	ps := proceeding.Model{1: {Bezeichnung: "A", Instanzen: []proceeding.Instanz{{CaseID: 1, Stufe: "Erstinstanz"}, {CaseID: 2, Stufe: "Berufung"}}}}
+
+	for id, expected := range map[int]string{
+		1: "{Erstinstanz Amtsgericht false true false [{2025-01-10 false}] false}",
+		2: "{Berufung Strafkammer false false true [{2025-05-02 true} {2025-05-09 false}] false}",
+		3: "{Ermittlungsverfahren Amtsgericht false true false [] true}",
+	} {
+		if got := fmt.Sprint(fee.FromCase(id, cs[id], courts, ps)); got != expected {
+			t.Fatalf("wrong parameters of case %d: expected %q, got %q", id, expected, got)
+		}
+	}
+}
+
+func TestGericht(t *testing.T) {
+	for gericht, expected := range map[string]string{
+		"":                          "Amtsgericht",
+		"AG Leipzig 5 Ls 12/26":     "Amtsgericht",
+		"LG Dresden":                "Strafkammer",
+		"LG Dresden, Schwurgericht": "Schwurgericht",
+		"Oberlandesgericht Dresden": "Oberlandesgericht",
+	} {
+		if got := fee.Gericht(lawcase.Case{Gericht: gericht}, court.Model{}); got != expected {
+			t.Fatalf("wrong court for %q: expected %q, got %q", gericht, expected, got)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Package holiday computes the statutory public holidays (gesetzliche
+Feiertage) in the German Bundesländer for any year since 1954, including the
+changes of the last decades like the Reformationstag in the northern
+Bundesländer since 2018. Bundesländer are given by the codes used in the court
+directory, e.g. SN for Sachsen.
+
+Only holidays of a whole Bundesland are included. Holidays of some
+municipalities like Fronleichnam in parts of Sachsen and Thüringen, Mariä
+Himmelfahrt in parts of Bayern and the Augsburger Friedensfest are not.
+*/
+package holiday
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Holiday is one public holiday.
+type Holiday struct {
+	Date time.Time
+	Name string
+}
+
+// MarshalJSON encodes the holiday with the date in ISO format.
+func (h Holiday) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Datum string `json:"Datum"`
+		Name  string `json:"Name"`
+	}{h.Date.Format("2006-01-02"), h.Name})
+}
+
+// Laender contains the codes of all Bundesländer.
+var Laender = []string{"BW", "BY", "BE", "BB", "HB", "HH", "HE", "MV", "NI", "NW", "RP", "SL", "SN", "ST", "SH", "TH"}
+
+// Easter returns Easter Sunday of the given year (Gregorian calendar).
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return date(year, time.Month(month), day)
+}
+
+// rule is a holiday that exists in the given Bundesländer (all if empty)
+// from the year from to the year to (no limit if 0).
+type rule struct {
+	name    string
+	day     func(year int, easter time.Time) time.Time
+	laender []string
+	from    int
+	to      int
+}
+
+func fixed(month time.Month, day int) func(int, time.Time) time.Time {
+	return func(year int, _ time.Time) time.Time {
+		return date(year, month, day)
+	}
+}
+
+func afterEaster(days int) func(int, time.Time) time.Time {
+	return func(_ int, easter time.Time) time.Time {
+		return easter.AddDate(0, 0, days)
+	}
+}
+
+var rules = []rule{
+	{name: "Neujahr", day: fixed(time.January, 1)},
+	{name: "Heilige Drei Könige", day: fixed(time.January, 6), laender: []string{"BW", "BY", "ST"}},
+	{name: "Internationaler Frauentag", day: fixed(time.March, 8), laender: []string{"BE"}, from: 2019},
+	{name: "Internationaler Frauentag", day: fixed(time.March, 8), laender: []string{"MV"}, from: 2023},
+	{name: "Karfreitag", day: afterEaster(-2)},
+	{name: "Ostersonntag", day: afterEaster(0), laender: []string{"BB", "HE"}},
+	{name: "Ostermontag", day: afterEaster(1)},
+	{name: "Tag der Arbeit", day: fixed(time.May, 1)},
+	{name: "Tag der Befreiung", day: fixed(time.May, 8), laender: []string{"BE"}, from: 2020, to: 2020},
+	{name: "Tag der Befreiung", day: fixed(time.May, 8), laender: []string{"BE"}, from: 2025, to: 2025},
+	{name: "Christi Himmelfahrt", day: afterEaster(39)},
+	{name: "Pfingstsonntag", day: afterEaster(49), laender: []string{"BB", "HE"}},
+	{name: "Pfingstmontag", day: afterEaster(50)},
+	{name: "Fronleichnam", day: afterEaster(60), laender: []string{"BW", "BY", "HE", "NW", "RP", "SL"}},
+	{name: "Tag der deutschen Einheit", day: fixed(time.June, 17), to: 1990},
+	{name: "Mariä Himmelfahrt", day: fixed(time.August, 15), laender: []string{"SL"}},
+	{name: "Weltkindertag", day: fixed(time.September, 20), laender: []string{"TH"}, from: 2019},
+	{name: "Tag der Deutschen Einheit", day: fixed(time.October, 3), from: 1990},
+	{name: "Reformationstag", day: fixed(time.October, 31), laender: []string{"BB", "MV", "SN", "ST", "TH"}, from: 1990},
+	{name: "Reformationstag", day: fixed(time.October, 31), laender: []string{"BW", "BY", "BE", "HB", "HH", "HE", "NI", "NW", "RP", "SL", "SH"}, from: 2017, to: 2017},
+	{name: "Reformationstag", day: fixed(time.October, 31), laender: []string{"HB", "HH", "NI", "SH"}, from: 2018},
+	{name: "Allerheiligen", day: fixed(time.November, 1), laender: []string{"BW", "BY", "NW", "RP", "SL"}},
+	{name: "Buß- und Bettag", day: bussUndBettag, to: 1994},
+	{name: "Buß- und Bettag", day: bussUndBettag, laender: []string{"SN"}, from: 1995},
+	{name: "1. Weihnachtstag", day: fixed(time.December, 25)},
+	{name: "2. Weihnachtstag", day: fixed(time.December, 26)},
+}
+
+// Holidays returns all public holidays of the year in the Bundesland sorted
+// by date. With an empty or unknown Bundesland only the holidays of all
+// Bundesländer are returned.
+func Holidays(year int, land string) []Holiday {
+	easter := Easter(year)
+	var h []Holiday
+	for _, r := range rules {
+		if r.from != 0 && year < r.from || r.to != 0 && year > r.to {
+			continue
+		}
+		if len(r.laender) > 0 && !contains(r.laender, land) {
+			continue
+		}
+		h = append(h, Holiday{Date: r.day(year, easter), Name: r.name})
+	}
+	sort.SliceStable(h, func(i, j int) bool {
+		return h[i].Date.Before(h[j].Date)
+	})
+	return h
+}
+
+// IsLand reports whether the code is the code of a Bundesland.
+func IsLand(land string) bool {
+	return contains(Laender, land)
+}
+
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHoliday reports whether the day is a public holiday in the Bundesland.
+func IsHoliday(t time.Time, land string) bool {
+	_, ok := Name(t, land)
+	return ok
+}
+
+// Name returns the name of the public holiday on the day.
+func Name(t time.Time, land string) (string, bool) {
+	d := date(t.Year(), t.Month(), t.Day())
+	for _, h := range Holidays(t.Year(), land) {
+		if h.Date.Equal(d) {
+			return h.Name, true
+		}
+	}
+	return "", false
+}
+
+// IsWorkday reports whether the day is neither Saturday, Sunday nor a public
+// holiday in the Bundesland.
+func IsWorkday(t time.Time, land string) bool {
+	if wd := t.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return false
+	}
+	return !IsHoliday(t, land)
+}
+
+// bussUndBettag is the Wednesday before November 23.
+func bussUndBettag(year int, _ time.Time) time.Time {
+	d := date(year, time.November, 22)
+	for d.Weekday() != time.Wednesday {
+		d = d.AddDate(0, 0, -1)
+	}
+	return d
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
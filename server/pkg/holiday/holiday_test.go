@@ -0,0 +1,99 @@
+package holiday_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/holiday"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestEaster(t *testing.T) {
+	for year, expected := range map[int]time.Time{
+		2019: date(2019, time.April, 21),
+		2024: date(2024, time.March, 31),
+		2025: date(2025, time.April, 20),
+		2026: date(2026, time.April, 5),
+	} {
+		if got := holiday.Easter(year); !got.Equal(expected) {
+			t.Fatalf("wrong Easter Sunday of %d: expected %s, got %s", year, expected, got)
+		}
+	}
+}
+
+func TestHolidays(t *testing.T) {
+	for _, tc := range []struct {
+		day      time.Time
+		land     string
+		expected string
+	}{
+		{date(2025, time.April, 18), "", "Karfreitag"},
+		{date(2025, time.June, 9), "HH", "Pfingstmontag"},
+		{date(2025, time.June, 19), "BY", "Fronleichnam"},
+		{date(2025, time.June, 19), "SN", ""},
+		{date(2025, time.October, 31), "SN", "Reformationstag"},
+		{date(2025, time.October, 31), "BY", ""},
+		{date(2025, time.November, 1), "NW", "Allerheiligen"},
+		{date(2025, time.November, 19), "SN", "Buß- und Bettag"},
+		{date(2025, time.November, 19), "BE", ""},
+		{date(2025, time.January, 6), "ST", "Heilige Drei Könige"},
+		{date(2025, time.March, 8), "BE", "Internationaler Frauentag"},
+		{date(2025, time.September, 20), "TH", "Weltkindertag"},
+	} {
+		got, _ := holiday.Name(tc.day, tc.land)
+		if got != tc.expected {
+			t.Fatalf("wrong holiday on %s in %q: expected %q, got %q", tc.day.Format("2006-01-02"), tc.land, tc.expected, got)
+		}
+	}
+
+	if n := len(holiday.Holidays(2025, "")); n != 9 {
+		t.Fatalf("wrong number of nationwide holidays: expected 9, got %d", n)
+	}
+	if holiday.IsWorkday(date(2025, time.October, 4), "BY") {
+		t.Fatalf("Saturday must not be a workday")
+	}
+}
+
+func TestHistoricalRules(t *testing.T) {
+	for _, tc := range []struct {
+		day      time.Time
+		land     string
+		expected string
+	}{
+		{date(2016, time.October, 31), "HH", ""},
+		{date(2017, time.October, 31), "BY", "Reformationstag"},
+		{date(2018, time.October, 31), "BY", ""},
+		{date(2018, time.October, 31), "HH", "Reformationstag"},
+		{date(1994, time.November, 16), "BY", "Buß- und Bettag"},
+		{date(1995, time.November, 22), "BY", ""},
+		{date(1995, time.November, 22), "SN", "Buß- und Bettag"},
+		{date(2018, time.March, 8), "BE", ""},
+		{date(2022, time.March, 8), "MV", ""},
+		{date(2023, time.March, 8), "MV", "Internationaler Frauentag"},
+		{date(2020, time.May, 8), "BE", "Tag der Befreiung"},
+		{date(2021, time.May, 8), "BE", ""},
+		{date(1985, time.June, 17), "NW", "Tag der deutschen Einheit"},
+		{date(1991, time.June, 17), "NW", ""},
+		{date(1989, time.October, 3), "NW", ""},
+	} {
+		got, _ := holiday.Name(tc.day, tc.land)
+		if got != tc.expected {
+			t.Fatalf("wrong holiday on %s in %q: expected %q, got %q", tc.day.Format("2006-01-02"), tc.land, tc.expected, got)
+		}
+	}
+}
+
+func TestAllLaender(t *testing.T) {
+	expected := map[string]int{
+		"BW": 12, "BY": 12, "BE": 11, "BB": 12, "HB": 10, "HH": 10, "HE": 12, "MV": 11,
+		"NI": 10, "NW": 11, "RP": 11, "SL": 12, "SN": 11, "ST": 11, "SH": 10, "TH": 11,
+	}
+	for _, land := range holiday.Laender {
+		if got := len(holiday.Holidays(2025, land)); got != expected[land] {
+			t.Fatalf("wrong number of holidays in %s: expected %d, got %d", land, expected[land], got)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Package ical reads and writes calendars in the iCalendar format (RFC 5545).
+*/
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ProdID is the product identifier of all calendars written by this package.
+const ProdID = "-//fao-strafrecht//Kalender//DE"
+
+const utcLayout = "20060102T150405Z"
+
+// Event is one VEVENT.
+type Event struct {
+	UID         string
+	Sequence    int
+	Stamp       time.Time
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Location    string
+	Description string
+	Cancelled   bool
+}
+
+// Write writes a calendar with the given name and events. All times are
+// written in UTC.
+func Write(w io.Writer, name string, events []Event) error {
+	bw := bufio.NewWriter(w)
+	l := lineWriter{w: bw}
+
+	l.line("BEGIN:VCALENDAR")
+	l.line("VERSION:2.0")
+	l.line("PRODID:" + ProdID)
+	l.line("CALSCALE:GREGORIAN")
+	l.line("METHOD:PUBLISH")
+	l.line("X-WR-CALNAME:" + Escape(name))
+	for _, e := range events {
+		l.line("BEGIN:VEVENT")
+		l.line("UID:" + Escape(e.UID))
+		l.line(fmt.Sprintf("SEQUENCE:%d", e.Sequence))
+		l.line("DTSTAMP:" + e.Stamp.UTC().Format(utcLayout))
+		l.line("DTSTART:" + e.Start.UTC().Format(utcLayout))
+		l.line("DTEND:" + e.End.UTC().Format(utcLayout))
+		l.line("SUMMARY:" + Escape(e.Summary))
+		if e.Location != "" {
+			l.line("LOCATION:" + Escape(e.Location))
+		}
+		if e.Description != "" {
+			l.line("DESCRIPTION:" + Escape(e.Description))
+		}
+		if e.Cancelled {
+			l.line("STATUS:CANCELLED")
+		} else {
+			l.line("STATUS:CONFIRMED")
+		}
+		l.line("END:VEVENT")
+	}
+	l.line("END:VCALENDAR")
+
+	if l.err != nil {
+		return fmt.Errorf("writing calendar: %w", l.err)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("writing calendar: %w", err)
+	}
+	return nil
+}
+
+// Escape escapes a text value (RFC 5545, section 3.3.11).
+func Escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// lineWriter writes content lines with CRLF and folds them after 75 octets
+// without splitting UTF-8 characters (RFC 5545, section 3.1).
+type lineWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (l *lineWriter) line(s string) {
+	if l.err != nil {
+		return
+	}
+	var b strings.Builder
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// The leading space of a continuation line counts.
+		limit = 74
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+	_, l.err = io.WriteString(l.w, b.String())
+}
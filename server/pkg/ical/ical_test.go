@@ -0,0 +1,141 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/ical"
+)
+
+func TestWrite(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("loading time zone: %v", err)
+	}
+	buf := new(bytes.Buffer)
+	err = ical.Write(buf, "Termine", []ical.Event{
+		{
+			UID:       "1@fao-strafrecht",
+			Sequence:  2,
+			Stamp:     time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC),
+			Start:     time.Date(2025, 11, 4, 9, 0, 0, 0, berlin),
+			End:       time.Date(2025, 11, 4, 10, 0, 0, 0, berlin),
+			Summary:   "Hauptverhandlung: Müller, Schulze, Schmidt wegen Diebstahls; Betrugs und Körperverletzung",
+			Cancelled: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("writing calendar: %v", err)
+	}
+	s := buf.String()
+
+	t.Run("CRLF", func(t *testing.T) {
+		if !strings.HasPrefix(s, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(s, "END:VCALENDAR\r\n") {
+			t.Fatalf("wrong calendar: %q", s)
+		}
+	})
+
+	t.Run("times in UTC", func(t *testing.T) {
+		for _, expected := range []string{"DTSTART:20251104T080000Z\r\n", "SEQUENCE:2\r\n", "STATUS:CANCELLED\r\n"} {
+			if !strings.Contains(s, expected) {
+				t.Fatalf("wrong calendar: expected %q in %q", expected, s)
+			}
+		}
+	})
+
+	t.Run("folding and escaping", func(t *testing.T) {
+		for _, line := range strings.Split(s, "\r\n") {
+			if len(line) > 75 {
+				t.Fatalf("line longer than 75 octets: %q", line)
+			}
+		}
+		unfolded := strings.ReplaceAll(s, "\r\n ", "")
+		expected := `SUMMARY:Hauptverhandlung: Müller\, Schulze\, Schmidt wegen Diebstahls\; Betrugs und Körperverletzung`
+		if !strings.Contains(unfolded, expected) {
+			t.Fatalf("wrong calendar: expected %q in %q", expected, unfolded)
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("loading time zone: %v", err)
+	}
+	data := "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:ladung-1@ag-leipzig.example\r\n" +
+		"DTSTART;TZID=W. Europe Standard Time:20251104T090000\r\n" +
+		"DTEND;TZID=\"Europe/Berlin\":20251104T103000\r\n" +
+		"SUMMARY:Hauptverhandlung in der Strafsache gegen Müller\\, Az. 5 Ls 100 Js 1\r\n" +
+		" /25\r\n" +
+		"LOCATION:Saal 1\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"DESCRIPTION:Erinnerung\r\n" +
+		"END:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\n" +
+		"UID:2@example\n" +
+		"SEQUENCE:3\n" +
+		"DTSTART;VALUE=DATE:20251105\n" +
+		"DTEND:20251105T130000Z\n" +
+		"DESCRIPTION:Zeile 1\\nZeile 2\n" +
+		"STATUS:CANCELLED\n" +
+		"END:VEVENT\n" +
+		"END:VCALENDAR\r\n"
+
+	events, err := ical.Parse(strings.NewReader(data), berlin)
+	if err != nil {
+		t.Fatalf("parsing calendar: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("wrong number of events: expected %d, got %d", 2, len(events))
+	}
+
+	t.Run("folded and escaped values", func(t *testing.T) {
+		e := events[0]
+		expected := "Hauptverhandlung in der Strafsache gegen Müller, Az. 5 Ls 100 Js 1/25"
+		if e.Summary != expected {
+			t.Fatalf("wrong summary: expected %q, got %q", expected, e.Summary)
+		}
+		if e.Description != "" {
+			t.Fatalf("wrong description: expected the one of VALARM to be skipped, got %q", e.Description)
+		}
+		if expected := "Zeile 1\nZeile 2"; events[1].Description != expected {
+			t.Fatalf("wrong description: expected %q, got %q", expected, events[1].Description)
+		}
+	})
+
+	t.Run("times", func(t *testing.T) {
+		for _, tc := range []struct {
+			got      time.Time
+			expected string
+		}{
+			{events[0].Start, "2025-11-04T08:00:00Z"},
+			{events[0].End, "2025-11-04T09:30:00Z"},
+			{events[1].Start, "2025-11-04T23:00:00Z"},
+			{events[1].End, "2025-11-05T13:00:00Z"},
+		} {
+			if got := tc.got.UTC().Format(time.RFC3339); got != tc.expected {
+				t.Fatalf("wrong time: expected %q, got %q", tc.expected, got)
+			}
+		}
+	})
+
+	t.Run("sequence and status", func(t *testing.T) {
+		if e := events[1]; e.Sequence != 3 || !e.Cancelled {
+			t.Fatalf("wrong event: %+v", e)
+		}
+	})
+
+	t.Run("event without UID", func(t *testing.T) {
+		_, err := ical.Parse(strings.NewReader("BEGIN:VEVENT\nDTSTART:20251105T130000Z\nEND:VEVENT\n"), berlin)
+		expectedErrMsg := "line 3: event without UID"
+		if err == nil || err.Error() != expectedErrMsg {
+			t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+		}
+	})
+}
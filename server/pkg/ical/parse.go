@@ -0,0 +1,186 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	localLayout = "20060102T150405"
+	dateLayout  = "20060102"
+)
+
+// Parse reads all VEVENTs of a calendar. Floating times, dates and times with
+// an unknown TZID (e.g. the Windows time zone names of Outlook) are read in
+// the given location. Components nested in events like VALARM are skipped.
+func Parse(r io.Reader, loc *time.Location) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading calendar: %w", err)
+	}
+
+	var events []Event
+	var e *Event
+	depth := 0
+	for n, l := range lines {
+		name, params, value, ok := splitLine(l)
+		if !ok {
+			return nil, fmt.Errorf("line %d: invalid content line %q", n+1, l)
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT") && e == nil:
+			e = &Event{}
+			depth = 0
+			continue
+		case name == "BEGIN" && e != nil:
+			depth++
+			continue
+		case name == "END" && e != nil && depth > 0:
+			depth--
+			continue
+		case name == "END" && strings.EqualFold(value, "VEVENT") && e != nil:
+			if e.UID == "" {
+				return nil, fmt.Errorf("line %d: event without UID", n+1)
+			}
+			if e.Start.IsZero() {
+				return nil, fmt.Errorf("line %d: event %s without DTSTART", n+1, e.UID)
+			}
+			events = append(events, *e)
+			e = nil
+			continue
+		}
+		if e == nil || depth > 0 {
+			continue
+		}
+
+		switch name {
+		case "UID":
+			e.UID = value
+		case "SEQUENCE":
+			s, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid SEQUENCE %q", n+1, value)
+			}
+			e.Sequence = s
+		case "DTSTAMP", "DTSTART", "DTEND":
+			t, err := parseTime(value, params, loc)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s: %w", n+1, name, err)
+			}
+			switch name {
+			case "DTSTAMP":
+				e.Stamp = t
+			case "DTSTART":
+				e.Start = t
+			case "DTEND":
+				e.End = t
+			}
+		case "SUMMARY":
+			e.Summary = Unescape(value)
+		case "LOCATION":
+			e.Location = Unescape(value)
+		case "DESCRIPTION":
+			e.Description = Unescape(value)
+		case "STATUS":
+			e.Cancelled = strings.EqualFold(value, "CANCELLED")
+		}
+	}
+	if e != nil {
+		return nil, fmt.Errorf("event %s without END:VEVENT", e.UID)
+	}
+	return events, nil
+}
+
+// Unescape reverses Escape.
+func Unescape(s string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if escaped {
+			if r == 'n' || r == 'N' {
+				r = '\n'
+			}
+			b.WriteRune(r)
+			escaped = false
+			continue
+		}
+		if r == '\\' {
+			escaped = true
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// unfold returns the content lines with folded lines joined. Lines may end
+// with CRLF or LF.
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for s.Scan() {
+		l := strings.TrimSuffix(s.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		if l == "" {
+			continue
+		}
+		lines = append(lines, l)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// splitLine splits a content line like "DTSTART;TZID=Europe/Berlin:20251104T090000"
+// into the upper case name, the parameters and the value. Parameter values
+// may be quoted and contain colons.
+func splitLine(l string) (string, map[string]string, string, bool) {
+	quoted := false
+	colon := -1
+	for i, r := range l {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 1 {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(l[:colon], ";")
+	params := map[string]string{}
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, l[colon+1:], true
+}
+
+// parseTime parses a DATE or DATE-TIME value (RFC 5545, sections 3.3.4 and
+// 3.3.5).
+func parseTime(value string, params map[string]string, loc *time.Location) (time.Time, error) {
+	if params["VALUE"] == "DATE" || len(value) == len(dateLayout) {
+		return time.ParseInLocation(dateLayout, value, loc)
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(utcLayout, value)
+	}
+	if tzid := params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	return time.ParseInLocation(localLayout, value, loc)
+}
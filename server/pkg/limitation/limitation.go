@@ -0,0 +1,143 @@
+/*
+Package limitation computes the limitation of prosecution (Verfolgungsverjährung)
+of a case according to §§ 78 to 78c StGB.
+
+The period begins when the offence is completed (§ 78a StGB) and starts anew
+with every interruption (§ 78c Abs. 3 Satz 1 StGB), but prosecution is barred
+at the latest when twice the period has elapsed since the beginning (§ 78c
+Abs. 3 Satz 2 StGB). Suspension (Ruhen, § 78b StGB) is not taken into account.
+Special limitation periods of other laws like § 376 Abs. 1 AO replace the
+period of § 78 Abs. 3 StGB.
+*/
+package limitation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+// DateLayout is the layout of all dates.
+const DateLayout = "2006-01-02"
+
+// Result is the computed limitation of a case.
+type Result struct {
+	// Jahre is the limitation period in years. It is 0 if the offence is
+	// not subject to limitation.
+	Jahre int `json:"Jahre"`
+
+	Beginn  string `json:"Beginn"`
+	Ablauf  string `json:"Ablauf,omitempty"`
+	Absolut string `json:"Absolut,omitempty"`
+
+	// Verjaehrt reports whether the period expired before the given day.
+	// Only the calendar date of the day counts.
+	Verjaehrt bool `json:"Verjaehrt"`
+
+	// Begruendung explains every step of the computation.
+	Begruendung []string `json:"Begruendung"`
+}
+
+// Period returns the limitation period in years and the number of § 78 Abs. 3
+// StGB. A special limitation period like the one of § 376 Abs. 1 AO replaces
+// the period of § 78 Abs. 3 StGB; the number is 0 then. It returns 0 years for
+// offences that are not subject to limitation.
+func Period(s lawcase.Strafdrohung) (years int, nr int) {
+	return s.Frist()
+}
+
+// End returns the last day of a period of the given years that begins on the
+// given day. The day of the beginning counts, so the period ends the day
+// before the day with the same date.
+func End(begin time.Time, years int) time.Time {
+	d := time.Date(begin.Year(), begin.Month(), begin.Day(), 0, 0, 0, 0, time.UTC)
+	return d.AddDate(years, 0, -1)
+}
+
+// Compute computes the limitation of the case at the given day. The period
+// depends on the explicit Strafdrohung of the case or the most serious
+// offence charged, see lawcase.Case.Hoechststrafe.
+func Compute(c lawcase.Case, today time.Time) (Result, error) {
+	if c.Tatende == "" {
+		return Result{}, fmt.Errorf("Tatende is missing")
+	}
+	s, source, ok := c.Hoechststrafe()
+	if !ok {
+		return Result{}, fmt.Errorf("Strafdrohung and Tatvorwuerfe are missing")
+	}
+	begin, err := time.Parse(DateLayout, c.Tatende)
+	if err != nil {
+		return Result{}, fmt.Errorf("Tatende: %w", err)
+	}
+
+	r := Result{Beginn: c.Tatende}
+	if source != "" {
+		r.Begruendung = append(r.Begruendung, fmt.Sprintf("most serious offence charged: %s", source))
+	}
+	years, nr := Period(s)
+	if years == 0 {
+		r.Begruendung = append(r.Begruendung, "offence is not subject to limitation (§ 78 Abs. 2 StGB)")
+		return r, nil
+	}
+	r.Jahre = years
+	if nr == 0 {
+		r.Begruendung = append(r.Begruendung, strings.TrimSuffix(fmt.Sprintf("special period of %d years (%s)", years, s.Verjaehrungsnorm), " ()"))
+	} else {
+		r.Begruendung = append(r.Begruendung, fmt.Sprintf("%s: period of %d years (§ 78 Abs. 3 Nr. %d StGB)", penalty(s), years, nr))
+	}
+	r.Begruendung = append(r.Begruendung, fmt.Sprintf("offence completed on %s: period begins (§ 78a StGB)", c.Tatende))
+
+	end := End(begin, years)
+	absolute := End(begin, 2*years)
+	r.Begruendung = append(r.Begruendung, fmt.Sprintf("period ends on %s", end.Format(DateLayout)))
+
+	interruptions := append([]lawcase.Unterbrechung{}, c.Unterbrechungen...)
+	sort.SliceStable(interruptions, func(i, j int) bool {
+		return interruptions[i].Datum < interruptions[j].Datum
+	})
+	for _, u := range interruptions {
+		d, err := time.Parse(DateLayout, u.Datum)
+		if err != nil {
+			return Result{}, fmt.Errorf("Unterbrechungen: %w", err)
+		}
+		switch {
+		case d.Before(begin):
+			r.Begruendung = append(r.Begruendung, fmt.Sprintf("%s (§ 78c Abs. 1 Satz 1 Nr. %d StGB) on %s is ignored: before the offence was completed", lawcase.Unterbrechungen[u.Nr], u.Nr, u.Datum))
+		case d.After(end):
+			r.Begruendung = append(r.Begruendung, fmt.Sprintf("%s (§ 78c Abs. 1 Satz 1 Nr. %d StGB) on %s is ignored: period had already ended", lawcase.Unterbrechungen[u.Nr], u.Nr, u.Datum))
+		default:
+			end = End(d, years)
+			r.Begruendung = append(r.Begruendung, fmt.Sprintf("%s (§ 78c Abs. 1 Satz 1 Nr. %d StGB) on %s: period begins anew and ends on %s (§ 78c Abs. 3 Satz 1 StGB)", lawcase.Unterbrechungen[u.Nr], u.Nr, u.Datum, end.Format(DateLayout)))
+		}
+	}
+
+	if len(interruptions) > 0 {
+		r.Absolut = absolute.Format(DateLayout)
+		if end.After(absolute) {
+			end = absolute
+			r.Begruendung = append(r.Begruendung, fmt.Sprintf("period ends on %s at the latest, twice the period after the beginning (§ 78c Abs. 3 Satz 2 StGB)", r.Absolut))
+		}
+	}
+
+	r.Ablauf = end.Format(DateLayout)
+	// Only the calendar date counts, the whole last day belongs to the period.
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	r.Verjaehrt = day.After(end)
+	return r, nil
+}
+
+func penalty(s lawcase.Strafdrohung) string {
+	switch {
+	case s.Lebenslang:
+		return "maximum penalty life imprisonment"
+	case s.Monate == 0:
+		return "fine only"
+	case s.Monate%12 == 0:
+		return fmt.Sprintf("maximum penalty %d years", s.Monate/12)
+	default:
+		return fmt.Sprintf("maximum penalty %d months", s.Monate)
+	}
+}
@@ -0,0 +1,153 @@
+package limitation_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/limitation"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(limitation.DateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestPeriod(t *testing.T) {
+	for _, tc := range []struct {
+		s        lawcase.Strafdrohung
+		expected int
+	}{
+		{lawcase.Strafdrohung{Unverjaehrbar: true, Lebenslang: true}, 0},
+		{lawcase.Strafdrohung{Lebenslang: true}, 30},
+		{lawcase.Strafdrohung{Monate: 15 * 12}, 20},
+		{lawcase.Strafdrohung{Monate: 10 * 12}, 10},
+		{lawcase.Strafdrohung{Monate: 5 * 12}, 5},
+		{lawcase.Strafdrohung{Monate: 2 * 12}, 5},
+		{lawcase.Strafdrohung{Monate: 12}, 3},
+		{lawcase.Strafdrohung{}, 3},
+		{lawcase.Strafdrohung{Monate: 10 * 12, Verjaehrungsfrist: 15}, 15},
+	} {
+		if got, _ := limitation.Period(tc.s); got != tc.expected {
+			t.Fatalf("wrong period for %+v: expected %d, got %d", tc.s, tc.expected, got)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	c := lawcase.Case{
+		Tatende:      "2019-03-15",
+		Strafdrohung: &lawcase.Strafdrohung{Monate: 60},
+	}
+
+	t.Run("without interruptions", func(t *testing.T) {
+		r, err := limitation.Compute(c, date("2024-03-15"))
+		if err != nil {
+			t.Fatalf("computing limitation: %v", err)
+		}
+		if r.Ablauf != "2024-03-14" || !r.Verjaehrt || r.Absolut != "" {
+			t.Fatalf("wrong result: %+v", r)
+		}
+	})
+
+	t.Run("last day of the period", func(t *testing.T) {
+		last := time.Date(2024, 3, 14, 23, 30, 0, 0, time.Local)
+		r, err := limitation.Compute(c, last)
+		if err != nil {
+			t.Fatalf("computing limitation: %v", err)
+		}
+		if r.Ablauf != "2024-03-14" || r.Verjaehrt {
+			t.Fatalf("wrong result on the last day: %+v", r)
+		}
+	})
+
+	t.Run("with interruptions", func(t *testing.T) {
+		c.Unterbrechungen = []lawcase.Unterbrechung{
+			{Datum: "2023-01-10", Nr: 1},
+		}
+		r, err := limitation.Compute(c, date("2024-03-15"))
+		if err != nil {
+			t.Fatalf("computing limitation: %v", err)
+		}
+		if r.Ablauf != "2028-01-09" || r.Verjaehrt || r.Absolut != "2029-03-14" {
+			t.Fatalf("wrong result: %+v", r)
+		}
+
+		c.Unterbrechungen = append(c.Unterbrechungen, lawcase.Unterbrechung{Datum: "2027-12-01", Nr: 6})
+		r, err = limitation.Compute(c, date("2024-03-15"))
+		if err != nil {
+			t.Fatalf("computing limitation: %v", err)
+		}
+		if r.Ablauf != "2029-03-14" {
+			t.Fatalf("wrong end: expected the absolute limit %q, got %q", "2029-03-14", r.Ablauf)
+		}
+		expected := "period ends on 2029-03-14 at the latest, twice the period after the beginning (§ 78c Abs. 3 Satz 2 StGB)"
+		if last := r.Begruendung[len(r.Begruendung)-1]; last != expected {
+			t.Fatalf("wrong reasoning: expected %q, got %q", expected, last)
+		}
+	})
+
+	t.Run("interruption after the end", func(t *testing.T) {
+		c.Unterbrechungen = []lawcase.Unterbrechung{{Datum: "2024-04-01", Nr: 6}}
+		r, err := limitation.Compute(c, date("2024-05-01"))
+		if err != nil {
+			t.Fatalf("computing limitation: %v", err)
+		}
+		if r.Ablauf != "2024-03-14" || !r.Verjaehrt {
+			t.Fatalf("wrong result: %+v", r)
+		}
+		if !strings.Contains(strings.Join(r.Begruendung, "\n"), "is ignored: period had already ended") {
+			t.Fatalf("wrong reasoning: %v", r.Begruendung)
+		}
+	})
+
+	t.Run("not subject to limitation", func(t *testing.T) {
+		c := lawcase.Case{Tatende: "1990-01-01", Strafdrohung: &lawcase.Strafdrohung{Lebenslang: true, Unverjaehrbar: true}}
+		r, err := limitation.Compute(c, date("2024-05-01"))
+		if err != nil {
+			t.Fatalf("computing limitation: %v", err)
+		}
+		if r.Jahre != 0 || r.Ablauf != "" || r.Verjaehrt {
+			t.Fatalf("wrong result: %+v", r)
+		}
+	})
+
+	t.Run("offences charged", func(t *testing.T) {
+		c := lawcase.Case{Tatende: "2019-03-15", Tatvorwuerfe: []lawcase.Tatvorwurf{{Norm: "§ 263 Abs. 3 StGB"}}}
+		r, err := limitation.Compute(c, date("2024-05-01"))
+		if err != nil {
+			t.Fatalf("computing limitation: %v", err)
+		}
+		expected := "most serious offence charged: Betrug (§ 263 Abs. 1 StGB)"
+		if r.Jahre != 5 || r.Begruendung[0] != expected {
+			t.Fatalf("wrong result: expected 5 years and reasoning %q, got %+v", expected, r)
+		}
+	})
+
+	t.Run("especially serious tax evasion", func(t *testing.T) {
+		c := lawcase.Case{Tatende: "2016-05-31", Tatvorwuerfe: []lawcase.Tatvorwurf{{Norm: "§ 370 Abs. 3 AO"}}}
+		r, err := limitation.Compute(c, date("2024-05-01"))
+		if err != nil {
+			t.Fatalf("computing limitation: %v", err)
+		}
+		if r.Jahre != 15 || r.Ablauf != "2031-05-30" || r.Verjaehrt {
+			t.Fatalf("wrong result: %+v", r)
+		}
+		expected := "special period of 15 years (§ 376 Abs. 1 AO)"
+		if r.Begruendung[1] != expected {
+			t.Fatalf("wrong reasoning: expected %q, got %q", expected, r.Begruendung[1])
+		}
+	})
+
+	t.Run("missing data", func(t *testing.T) {
+		_, err := limitation.Compute(lawcase.Case{Tatende: "2019-03-15"}, date("2024-05-01"))
+		expectedErrMsg := "Strafdrohung and Tatvorwuerfe are missing"
+		if err == nil || err.Error() != expectedErrMsg {
+			t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+		}
+	})
+}
@@ -0,0 +1,272 @@
+/*
+Package appointment is about appointments of cases like hearings, visits to
+clients in custody and file inspections.
+*/
+package appointment
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	// The time zone database is embedded so that Location works on every
+	// system.
+	_ "time/tzdata"
+)
+
+// TimeLayout is the layout of the begin and end of appointments. Times are
+// local times in Location.
+const TimeLayout = "2006-01-02T15:04"
+
+// Location is the time zone of all appointments.
+var Location = mustLoadLocation("Europe/Berlin")
+
+type Model map[int]Appointment
+
+type Appointment struct {
+	// CaseID is 0 for imported appointments that could not be assigned to a
+	// case yet.
+	CaseID int    `json:"CaseID"`
+	Art    string `json:"Art" validate:"oneof=Hauptverhandlung Haftbesuch Akteneinsicht Sonstiges"`
+	Titel  string `json:"Titel"`
+	Beginn string `json:"Beginn" validate:"required,datetime=2006-01-02T15:04"`
+	Ende   string `json:"Ende" validate:"omitempty,datetime=2006-01-02T15:04"`
+	Ort    string `json:"Ort"`
+	Notiz  string `json:"Notiz"`
+
+	// GerichtID references the court of the court directory where the
+	// appointment takes place.
+	GerichtID int `json:"GerichtID,omitempty"`
+
+	// UID, Sequence and Geaendert are set by the model. UID is stable for
+	// the whole life of the appointment, Sequence is increased with every
+	// change and is used for the calendar feeds only.
+	UID       string `json:"UID"`
+	Sequence  int    `json:"Sequence"`
+	Geaendert string `json:"Geaendert"`
+	Abgesagt  bool   `json:"Abgesagt,omitempty"`
+
+	// ImportSequence is the SEQUENCE of the imported event given by its
+	// organizer, e.g. a court. It is independent of the own Sequence.
+	ImportSequence int `json:"ImportSequence,omitempty"`
+}
+
+// Start returns the begin of the appointment.
+func (a Appointment) Start() (time.Time, error) {
+	return time.ParseInLocation(TimeLayout, a.Beginn, Location)
+}
+
+// Stop returns the end of the appointment. If Ende is empty, the appointment
+// lasts DefaultDuration.
+func (a Appointment) Stop() (time.Time, error) {
+	if a.Ende == "" {
+		s, err := a.Start()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return s.Add(DefaultDuration), nil
+	}
+	return time.ParseInLocation(TimeLayout, a.Ende, Location)
+}
+
+// DefaultDuration is the duration of appointments without end.
+const DefaultDuration = time.Hour
+
+type decodedMsg struct {
+	ID     int         `json:"ID"`
+	Fields Appointment `json:"Fields"`
+}
+
+func (as *Model) Load(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var d decodedMsg
+	if err := json.Unmarshal(msg, &d); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	if d.ID < 1 {
+		return fmt.Errorf("message contains invalid id %d", d.ID)
+	}
+	(*as)[d.ID] = d.Fields
+	return nil
+}
+
+// AddAppointment adds a new appointment. If it has no UID yet, a new one is
+// generated.
+func (as *Model) AddAppointment(a Appointment, now time.Time, w io.Writer) (int, error) {
+	newID := as.maxAppointmentID() + 1
+	if a.UID == "" {
+		uid, err := NewUID()
+		if err != nil {
+			return 0, err
+		}
+		a.UID = uid
+	}
+	a.Sequence = 0
+	a.Geaendert = now.UTC().Format(time.RFC3339)
+	if err := as.write(newID, a, w); err != nil {
+		return 0, err
+	}
+	return newID, nil
+}
+
+// UpdateAppointment replaces the appointment. UID and ImportSequence are kept
+// and Sequence is increased.
+func (as *Model) UpdateAppointment(id int, a Appointment, now time.Time, w io.Writer) error {
+	old, ok := (*as)[id]
+	if !ok {
+		return fmt.Errorf("appointment %d does not exist", id)
+	}
+	a.UID = old.UID
+	a.ImportSequence = old.ImportSequence
+	a.Sequence = old.Sequence + 1
+	a.Geaendert = now.UTC().Format(time.RFC3339)
+	return as.write(id, a, w)
+}
+
+// Cancel marks the appointment as cancelled. It stays in the model so that
+// calendars can remove it.
+func (as *Model) Cancel(id int, now time.Time, w io.Writer) error {
+	a, ok := (*as)[id]
+	if !ok {
+		return fmt.Errorf("appointment %d does not exist", id)
+	}
+	if a.Abgesagt {
+		return fmt.Errorf("appointment %d is already cancelled", id)
+	}
+	a.Abgesagt = true
+	return as.UpdateAppointment(id, a, now, w)
+}
+
+// Import adds an appointment imported from another calendar, e.g. a summons
+// of a court. Unlike AddAppointment it keeps the UID and the ImportSequence of
+// the event so that later versions of it can be recognized.
+func (as *Model) Import(a Appointment, now time.Time, w io.Writer) (int, error) {
+	if a.UID == "" {
+		return 0, fmt.Errorf("imported appointment must have a UID")
+	}
+	newID := as.maxAppointmentID() + 1
+	a.Sequence = 0
+	a.Geaendert = now.UTC().Format(time.RFC3339)
+	if err := as.write(newID, a, w); err != nil {
+		return 0, err
+	}
+	return newID, nil
+}
+
+// UpdateImported applies a new version of an imported appointment. It
+// returns false and changes nothing if the ImportSequence of the version is
+// not higher than the stored one. A cancelled version only marks the
+// appointment as cancelled like Cancel. Otherwise time, title, place and note
+// are replaced, while the case, the kind and the court stay as assigned. The
+// ImportSequence of the version is kept and Sequence is increased like for
+// every other change.
+func (as *Model) UpdateImported(id int, a Appointment, now time.Time, w io.Writer) (bool, error) {
+	old, ok := (*as)[id]
+	if !ok {
+		return false, fmt.Errorf("appointment %d does not exist", id)
+	}
+	if a.ImportSequence <= old.ImportSequence {
+		return false, nil
+	}
+
+	updated := old
+	updated.Abgesagt = a.Abgesagt
+	if !a.Abgesagt {
+		updated.Titel = a.Titel
+		updated.Beginn = a.Beginn
+		updated.Ende = a.Ende
+		updated.Ort = a.Ort
+		updated.Notiz = a.Notiz
+	}
+	updated.ImportSequence = a.ImportSequence
+	updated.Sequence = old.Sequence + 1
+	updated.Geaendert = now.UTC().Format(time.RFC3339)
+	if err := as.write(id, updated, w); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (as *Model) write(id int, a Appointment, w io.Writer) error {
+	d := decodedMsg{
+		ID:     id,
+		Fields: a,
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("writing event data: %w", err)
+	}
+	(*as)[id] = a
+	return nil
+}
+
+func (as Model) maxAppointmentID() int {
+	var result int
+	for n := range as {
+		if n > result {
+			result = n
+		}
+	}
+	return result
+}
+
+func (as Model) Retrieve(id int) (Appointment, error) {
+	a, ok := as[id]
+	if !ok {
+		return Appointment{}, fmt.Errorf("appointment %d does not exist", id)
+	}
+	return a, nil
+}
+
+// ByUID returns the id of the appointment with the given UID.
+func (as Model) ByUID(uid string) (int, bool) {
+	for id, a := range as {
+		if a.UID == uid {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// Unassigned returns the ids of all appointments without case sorted by
+// begin.
+func (as Model) Unassigned() []int {
+	ids := []int{}
+	for _, id := range as.IDs() {
+		if as[id].CaseID == 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// IDs returns the ids of all appointments sorted by begin.
+func (as Model) IDs() []int {
+	ids := make([]int, 0, len(as))
+	for id := range as {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := as[ids[i]], as[ids[j]]
+		if a.Beginn != b.Beginn {
+			return a.Beginn < b.Beginn
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+func mustLoadLocation(name string) *time.Location {
+	l, err := time.LoadLocation(name)
+	if err != nil {
+		panic(fmt.Sprintf("loading time zone %s: %v", name, err))
+	}
+	return l
+}
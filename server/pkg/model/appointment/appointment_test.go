@@ -0,0 +1,175 @@
+package appointment_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/appointment"
+)
+
+func TestModel(t *testing.T) {
+	m := appointment.Model{}
+	buf := bytes.NewBuffer(nil)
+	now := time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)
+
+	t.Run("add appointments", func(t *testing.T) {
+		id, err := m.AddAppointment(appointment.Appointment{CaseID: 1, Art: "Hauptverhandlung", Beginn: "2025-11-04T09:00"}, now, buf)
+		if err != nil {
+			t.Fatalf("adding appointment: %v", err)
+		}
+		if id != 1 {
+			t.Fatalf("wrong id: expected %d, got %d", 1, id)
+		}
+		a := m[1]
+		if !strings.HasSuffix(a.UID, "@fao-strafrecht") {
+			t.Fatalf("wrong UID: expected suffix %q, got %q", "@fao-strafrecht", a.UID)
+		}
+		if a.Geaendert != "2025-10-01T08:00:00Z" {
+			t.Fatalf("wrong change time: expected %q, got %q", "2025-10-01T08:00:00Z", a.Geaendert)
+		}
+
+		if _, err := m.AddAppointment(appointment.Appointment{CaseID: 1, Art: "Haftbesuch", Beginn: "2025-10-20T14:00", Ende: "2025-10-20T15:30"}, now, buf); err != nil {
+			t.Fatalf("adding appointment: %v", err)
+		}
+		if ids := m.IDs(); len(ids) != 2 || ids[0] != 2 {
+			t.Fatalf("wrong order of appointments: expected [2 1], got %v", ids)
+		}
+	})
+
+	t.Run("update keeps UID and increases sequence", func(t *testing.T) {
+		uid := m[1].UID
+		if err := m.UpdateAppointment(1, appointment.Appointment{CaseID: 1, Art: "Hauptverhandlung", Beginn: "2025-11-05T09:00"}, now.Add(time.Hour), buf); err != nil {
+			t.Fatalf("updating appointment: %v", err)
+		}
+		a := m[1]
+		if a.UID != uid {
+			t.Fatalf("wrong UID: expected %q, got %q", uid, a.UID)
+		}
+		if a.Sequence != 1 {
+			t.Fatalf("wrong sequence: expected %d, got %d", 1, a.Sequence)
+		}
+
+		err := m.UpdateAppointment(3, appointment.Appointment{}, now, buf)
+		expectedErrMsg := "appointment 3 does not exist"
+		if err == nil || err.Error() != expectedErrMsg {
+			t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		if err := m.Cancel(1, now, buf); err != nil {
+			t.Fatalf("cancelling appointment: %v", err)
+		}
+		if a := m[1]; !a.Abgesagt || a.Sequence != 2 {
+			t.Fatalf("wrong cancelled appointment: %+v", a)
+		}
+
+		err := m.Cancel(1, now, buf)
+		expectedErrMsg := "appointment 1 is already cancelled"
+		if err == nil || err.Error() != expectedErrMsg {
+			t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+		}
+	})
+
+	t.Run("import keeps sequence and applies newer versions", func(t *testing.T) {
+		id, err := m.Import(appointment.Appointment{CaseID: 1, Art: "Hauptverhandlung", Titel: "HV", Beginn: "2025-12-01T09:00", UID: "x@court", ImportSequence: 2}, now, buf)
+		if err != nil {
+			t.Fatalf("importing appointment: %v", err)
+		}
+		if a := m[id]; a.UID != "x@court" || a.ImportSequence != 2 || a.Sequence != 0 {
+			t.Fatalf("wrong imported appointment: %+v", a)
+		}
+
+		updated, err := m.UpdateImported(id, appointment.Appointment{Art: "Sonstiges", Beginn: "2025-12-02T09:00", ImportSequence: 2}, now, buf)
+		if err != nil || updated {
+			t.Fatalf("expected version with same sequence to be ignored, got %t, %v", updated, err)
+		}
+
+		updated, err = m.UpdateImported(id, appointment.Appointment{Art: "Sonstiges", Titel: "HV neu", Beginn: "2025-12-03T09:00", ImportSequence: 4}, now, buf)
+		if err != nil || !updated {
+			t.Fatalf("expected update, got %t, %v", updated, err)
+		}
+		if a := m[id]; a.Beginn != "2025-12-03T09:00" || a.Titel != "HV neu" || a.Art != "Hauptverhandlung" || a.CaseID != 1 || a.ImportSequence != 4 || a.Sequence != 1 {
+			t.Fatalf("wrong updated appointment: %+v", a)
+		}
+
+		// Assigning the appointment locally increases only the own sequence.
+		assigned := m[id]
+		assigned.CaseID = 2
+		if err := m.UpdateAppointment(id, assigned, now, buf); err != nil {
+			t.Fatalf("updating appointment: %v", err)
+		}
+		if a := m[id]; a.ImportSequence != 4 || a.Sequence != 2 {
+			t.Fatalf("wrong sequences after local update: %+v", a)
+		}
+
+		updated, err = m.UpdateImported(id, appointment.Appointment{Beginn: "2025-12-01T09:00", ImportSequence: 5, Abgesagt: true}, now, buf)
+		if err != nil || !updated {
+			t.Fatalf("expected cancellation, got %t, %v", updated, err)
+		}
+		if a := m[id]; !a.Abgesagt || a.Beginn != "2025-12-03T09:00" || a.CaseID != 2 || a.ImportSequence != 5 || a.Sequence != 3 {
+			t.Fatalf("wrong cancelled appointment: %+v", a)
+		}
+	})
+
+	t.Run("load events", func(t *testing.T) {
+		loaded := appointment.Model{}
+		dec := json.NewDecoder(buf)
+		for dec.More() {
+			var msg json.RawMessage
+			if err := dec.Decode(&msg); err != nil {
+				t.Fatalf("decoding event: %v", err)
+			}
+			if err := loaded.Load(msg); err != nil {
+				t.Fatalf("loading event: %v", err)
+			}
+		}
+		if loaded[1] != m[1] || loaded[2] != m[2] || loaded[3] != m[3] {
+			t.Fatalf("wrong loaded model: expected %v, got %v", m, loaded)
+		}
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		stop, err := m[1].Stop()
+		if err != nil {
+			t.Fatalf("parsing end: %v", err)
+		}
+		expected := "2025-11-05T10:00"
+		if got := stop.Format(appointment.TimeLayout); got != expected {
+			t.Fatalf("wrong end: expected %q, got %q", expected, got)
+		}
+	})
+}
+
+func TestFeeds(t *testing.T) {
+	fs := appointment.Feeds{}
+	buf := bytes.NewBuffer(nil)
+
+	id, f, err := fs.AddFeed(appointment.Feed{Name: "Kanzlei"}, buf)
+	if err != nil {
+		t.Fatalf("adding feed: %v", err)
+	}
+	if id != 1 || len(f.Token) != 64 {
+		t.Fatalf("wrong feed %d: %+v", id, f)
+	}
+	if got, ok := fs.ByToken(f.Token); !ok || got.Name != "Kanzlei" {
+		t.Fatalf("feed not found by token %q", f.Token)
+	}
+	if _, ok := fs.ByToken(""); ok {
+		t.Fatalf("found feed with empty token")
+	}
+	if _, ok := fs.ByToken(f.Token[:63]); ok {
+		t.Fatalf("found feed with a prefix of the token")
+	}
+
+	_, other, err := fs.AddFeed(appointment.Feed{Name: "Handy"}, buf)
+	if err != nil {
+		t.Fatalf("adding feed: %v", err)
+	}
+	if got, ok := fs.ByToken(other.Token); !ok || got.Name != "Handy" {
+		t.Fatalf("feed not found by token %q", other.Token)
+	}
+}
@@ -0,0 +1,199 @@
+package appointment
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// DefaultBuffer is the time needed between two appointments at different
+// courts if there is no Buffer for the two courts.
+const DefaultBuffer = time.Hour
+
+// Buffers contains the time needed between appointments at two courts, e.g.
+// for travelling from one town to the other.
+type Buffers map[int]Buffer
+
+type Buffer struct {
+	GerichtA int `json:"GerichtA" validate:"required"`
+	GerichtB int `json:"GerichtB" validate:"required,nefield=GerichtA"`
+	Minuten  int `json:"Minuten" validate:"min=0"`
+}
+
+type decodedBufferMsg struct {
+	ID     int    `json:"ID"`
+	Fields Buffer `json:"Fields"`
+}
+
+func (bs *Buffers) Load(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var d decodedBufferMsg
+	if err := json.Unmarshal(msg, &d); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	if d.ID < 1 {
+		return fmt.Errorf("message contains invalid id %d", d.ID)
+	}
+	(*bs)[d.ID] = d.Fields
+	return nil
+}
+
+// SetBuffer sets the buffer between two courts. An existing buffer of the
+// same two courts in any order is replaced.
+func (bs *Buffers) SetBuffer(b Buffer, w io.Writer) (int, error) {
+	id := 0
+	for n, old := range *bs {
+		if old.matches(b.GerichtA, b.GerichtB) {
+			id = n
+			break
+		}
+	}
+	if id == 0 {
+		for n := range *bs {
+			if n > id {
+				id = n
+			}
+		}
+		id++
+	}
+
+	d := decodedBufferMsg{
+		ID:     id,
+		Fields: b,
+	}
+	e, err := json.Marshal(d)
+	if err != nil {
+		return 0, fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(e); err != nil {
+		return 0, fmt.Errorf("writing event data: %w", err)
+	}
+	(*bs)[id] = b
+	return id, nil
+}
+
+func (b Buffer) matches(a, c int) bool {
+	return (b.GerichtA == a && b.GerichtB == c) || (b.GerichtA == c && b.GerichtB == a)
+}
+
+// Between returns the time needed between appointments at the two courts.
+// Appointments at the same court or without court need no buffer.
+func (bs Buffers) Between(a, b int) time.Duration {
+	if a == 0 || b == 0 || a == b {
+		return 0
+	}
+	for _, buf := range bs {
+		if buf.matches(a, b) {
+			return time.Duration(buf.Minuten) * time.Minute
+		}
+	}
+	return DefaultBuffer
+}
+
+// Conflict is an overlap or a too short gap between two appointments. First
+// begins before Second.
+type Conflict struct {
+	First  int `json:"First"`
+	Second int `json:"Second"`
+
+	// Abstand is the gap between the end of the first and the begin of the
+	// second appointment in minutes. It is negative for overlaps.
+	Abstand int `json:"Abstand"`
+
+	// Puffer is the needed gap in minutes.
+	Puffer int `json:"Puffer"`
+}
+
+// Overlap reports whether the appointments overlap.
+func (c Conflict) Overlap() bool {
+	return c.Abstand < 0
+}
+
+// Warning returns a hint about the conflict from the point of view of the
+// appointment with the given id.
+func (c Conflict) Warning(id int) string {
+	other := c.Second
+	if id == c.Second {
+		other = c.First
+	}
+	if c.Overlap() {
+		return fmt.Sprintf("Beginn: overlaps with appointment %d", other)
+	}
+	return fmt.Sprintf("Beginn: only %d minutes between this appointment and appointment %d at another court, %d minutes needed", c.Abstand, other, c.Puffer)
+}
+
+// ConflictsOf returns the conflicts of the appointment with the given id with
+// all other appointments. The appointment does not have to be in the model
+// yet. Cancelled appointments never conflict.
+func (as Model) ConflictsOf(id int, a Appointment, bs Buffers) []Conflict {
+	var result []Conflict
+	for _, other := range as.IDs() {
+		if other == id {
+			continue
+		}
+		if c, ok := conflict(id, a, other, as[other], bs); ok {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// Conflicts returns all conflicts of appointments that end after the given
+// time sorted by the begin of the first appointment.
+func (as Model) Conflicts(after time.Time, bs Buffers) []Conflict {
+	var ids []int
+	for _, id := range as.IDs() {
+		if stop, err := as[id].Stop(); err == nil && stop.After(after) {
+			ids = append(ids, id)
+		}
+	}
+
+	result := []Conflict{}
+	for i, a := range ids {
+		for _, b := range ids[i+1:] {
+			if c, ok := conflict(a, as[a], b, as[b], bs); ok {
+				result = append(result, c)
+			}
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return as[result[i].First].Beginn < as[result[j].First].Beginn
+	})
+	return result
+}
+
+func conflict(idA int, a Appointment, idB int, b Appointment, bs Buffers) (Conflict, bool) {
+	if a.Abgesagt || b.Abgesagt {
+		return Conflict{}, false
+	}
+	startA, errA := a.Start()
+	startB, errB := b.Start()
+	if errA != nil || errB != nil {
+		return Conflict{}, false
+	}
+	if startB.Before(startA) {
+		idA, idB = idB, idA
+		a, b = b, a
+		startA, startB = startB, startA
+	}
+	stopA, err := a.Stop()
+	if err != nil {
+		return Conflict{}, false
+	}
+
+	gap := startB.Sub(stopA)
+	buffer := bs.Between(a.GerichtID, b.GerichtID)
+	if gap >= buffer {
+		return Conflict{}, false
+	}
+	return Conflict{
+		First:   idA,
+		Second:  idB,
+		Abstand: int(gap / time.Minute),
+		Puffer:  int(buffer / time.Minute),
+	}, true
+}
@@ -0,0 +1,87 @@
+package appointment_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/appointment"
+)
+
+func TestBuffers(t *testing.T) {
+	bs := appointment.Buffers{}
+	buf := bytes.NewBuffer(nil)
+
+	if _, err := bs.SetBuffer(appointment.Buffer{GerichtA: 1, GerichtB: 2, Minuten: 90}, buf); err != nil {
+		t.Fatalf("setting buffer: %v", err)
+	}
+	id, err := bs.SetBuffer(appointment.Buffer{GerichtA: 2, GerichtB: 1, Minuten: 120}, buf)
+	if err != nil {
+		t.Fatalf("setting buffer: %v", err)
+	}
+	if id != 1 || len(bs) != 1 {
+		t.Fatalf("wrong buffers: expected the buffer of the same courts to be replaced, got %v", bs)
+	}
+
+	for _, tc := range []struct {
+		a, b     int
+		expected time.Duration
+	}{
+		{1, 2, 2 * time.Hour},
+		{2, 1, 2 * time.Hour},
+		{1, 3, appointment.DefaultBuffer},
+		{1, 1, 0},
+		{0, 1, 0},
+	} {
+		if got := bs.Between(tc.a, tc.b); got != tc.expected {
+			t.Fatalf("wrong buffer between %d and %d: expected %s, got %s", tc.a, tc.b, tc.expected, got)
+		}
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	bs := appointment.Buffers{1: {GerichtA: 1, GerichtB: 2, Minuten: 90}}
+	m := appointment.Model{
+		1: {CaseID: 1, Art: "Hauptverhandlung", Beginn: "2025-11-04T09:00", Ende: "2025-11-04T11:00", GerichtID: 1},
+		2: {CaseID: 2, Art: "Hauptverhandlung", Beginn: "2025-11-04T12:00", GerichtID: 2},
+		3: {CaseID: 3, Art: "Hauptverhandlung", Beginn: "2025-11-04T13:00", GerichtID: 2},
+		4: {CaseID: 4, Art: "Haftbesuch", Beginn: "2025-11-04T10:30", Abgesagt: true},
+		5: {CaseID: 5, Art: "Akteneinsicht", Beginn: "2025-11-03T10:00"},
+		6: {CaseID: 6, Art: "Sonstiges", Beginn: "2025-11-03T10:30"},
+	}
+
+	t.Run("all conflicts", func(t *testing.T) {
+		got := fmt.Sprint(m.Conflicts(time.Date(2025, 11, 4, 0, 0, 0, 0, appointment.Location), bs))
+		expected := "[{1 2 60 90}]"
+		if got != expected {
+			t.Fatalf("wrong conflicts: expected %s, got %s", expected, got)
+		}
+
+		got = fmt.Sprint(m.Conflicts(time.Date(2025, 11, 3, 0, 0, 0, 0, appointment.Location), bs))
+		expected = "[{5 6 -30 0} {1 2 60 90}]"
+		if got != expected {
+			t.Fatalf("wrong conflicts: expected %s, got %s", expected, got)
+		}
+	})
+
+	t.Run("conflicts of a new appointment", func(t *testing.T) {
+		a := appointment.Appointment{CaseID: 7, Art: "Hauptverhandlung", Beginn: "2025-11-04T08:30", GerichtID: 3}
+		conflicts := m.ConflictsOf(0, a, bs)
+		if len(conflicts) != 1 {
+			t.Fatalf("wrong conflicts: expected one, got %v", conflicts)
+		}
+		expected := "Beginn: overlaps with appointment 1"
+		if got := conflicts[0].Warning(0); got != expected {
+			t.Fatalf("wrong warning: expected %q, got %q", expected, got)
+		}
+	})
+
+	t.Run("warning about short gap", func(t *testing.T) {
+		c := m.ConflictsOf(2, m[2], bs)[0]
+		expected := "Beginn: only 60 minutes between this appointment and appointment 1 at another court, 90 minutes needed"
+		if got := c.Warning(2); got != expected {
+			t.Fatalf("wrong warning: expected %q, got %q", expected, got)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package appointment
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Feeds contains the calendar feeds. There are no user accounts, so every
+// feed contains all appointments of the office. A feed is a subscription,
+// e.g. of one phone, with a secret token that is part of the URL of the feed.
+type Feeds map[int]Feed
+
+type Feed struct {
+	// Name describes the subscriber, e.g. the device. It is part of the
+	// name of the calendar.
+	Name  string `json:"Name" validate:"required"`
+	Token string `json:"Token"`
+}
+
+type decodedFeedMsg struct {
+	ID     int  `json:"ID"`
+	Fields Feed `json:"Fields"`
+}
+
+func (fs *Feeds) Load(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var d decodedFeedMsg
+	if err := json.Unmarshal(msg, &d); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	if d.ID < 1 {
+		return fmt.Errorf("message contains invalid id %d", d.ID)
+	}
+	(*fs)[d.ID] = d.Fields
+	return nil
+}
+
+// AddFeed adds a new feed with a new random token.
+func (fs *Feeds) AddFeed(f Feed, w io.Writer) (int, Feed, error) {
+	token, err := randomHex(32)
+	if err != nil {
+		return 0, Feed{}, err
+	}
+	f.Token = token
+
+	var newID int
+	for n := range *fs {
+		if n > newID {
+			newID = n
+		}
+	}
+	newID++
+
+	d := decodedFeedMsg{
+		ID:     newID,
+		Fields: f,
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		return 0, Feed{}, fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return 0, Feed{}, fmt.Errorf("writing event data: %w", err)
+	}
+	(*fs)[newID] = f
+	return newID, f, nil
+}
+
+// ByToken returns the feed with the given token. The tokens are compared in
+// constant time so that they can not be guessed by timing.
+func (fs Feeds) ByToken(token string) (Feed, bool) {
+	if token == "" {
+		return Feed{}, false
+	}
+	var result Feed
+	found := false
+	for _, f := range fs {
+		if subtle.ConstantTimeCompare([]byte(f.Token), []byte(token)) == 1 {
+			result, found = f, true
+		}
+	}
+	return result, found
+}
+
+// NewUID returns a new unique id for an appointment.
+func NewUID() (string, error) {
+	r, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	return r + "@fao-strafrecht", nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("reading random bytes: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
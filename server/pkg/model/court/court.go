@@ -0,0 +1,97 @@
+/*
+Package court is about the directory of criminal courts.
+*/
+package court
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+type Model map[int]Court
+
+type Court struct {
+	Name          string   `json:"Name" validate:"required"`
+	Ort           string   `json:"Ort" validate:"required"`
+	Instanz       string   `json:"Instanz" validate:"oneof=AG LG OLG BGH"`
+	Spruchkoerper []string `json:"Spruchkoerper" validate:"dive,oneof=Strafrichter Schöffengericht Strafkammer Schwurgericht Strafsenat"`
+	Bundesland    string   `json:"Bundesland" validate:"oneof=BW BY BE BB HB HH HE MV NI NW RP SL SN ST SH TH"`
+}
+
+type decodedMsg struct {
+	ID     int   `json:"ID"`
+	Fields Court `json:"Fields"`
+}
+
+func (cs *Model) Load(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var d decodedMsg
+	if err := json.Unmarshal(msg, &d); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	if d.ID < 1 {
+		return fmt.Errorf("message contains invalid id %d", d.ID)
+	}
+	(*cs)[d.ID] = d.Fields
+	return nil
+}
+
+func (cs *Model) AddCourt(c Court, w io.Writer) (int, error) {
+	newID := cs.maxCourtID() + 1
+	d := decodedMsg{
+		ID:     newID,
+		Fields: c,
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		return 0, fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return 0, fmt.Errorf("writing event data: %w", err)
+	}
+	(*cs)[newID] = c
+	return newID, nil
+}
+
+func (cs Model) maxCourtID() int {
+	var result int
+	for n := range cs {
+		if n > result {
+			result = n
+		}
+	}
+	return result
+}
+
+func (cs Model) Retrieve(id int) (Court, error) {
+	c, ok := cs[id]
+	if !ok {
+		return Court{}, fmt.Errorf("court %d does not exist", id)
+	}
+	return c, nil
+}
+
+// Find returns the id of the court with the given name and place.
+func (cs Model) Find(name, ort string) (int, bool) {
+	for _, id := range cs.IDs() {
+		c := cs[id]
+		if normalize(c.Name) == normalize(name) && normalize(c.Ort) == normalize(ort) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// IDs returns the ids of all courts in ascending order.
+func (cs Model) IDs() []int {
+	ids := make([]int, 0, len(cs))
+	for id := range cs {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,97 @@
+package court_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/court"
+)
+
+func TestLoad(t *testing.T) {
+	m := court.Model{}
+	msg := json.RawMessage(`{"ID": 3, "Fields": {"Name": "Amtsgericht Leipzig", "Ort": "Leipzig"}}`)
+	if err := m.Load(msg); err != nil {
+		t.Fatalf("loading message: %v", err)
+	}
+	if m[3].Name != "Amtsgericht Leipzig" {
+		t.Fatalf("wrong name: expected %q, got %q", "Amtsgericht Leipzig", m[3].Name)
+	}
+
+	err := m.Load(json.RawMessage(`{"Fields": {}}`))
+	expectedErrMsg := "message contains invalid id 0"
+	if err == nil || err.Error() != expectedErrMsg {
+		t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+	}
+}
+
+func TestAddCourt(t *testing.T) {
+	m := court.Model{}
+	buf := bytes.NewBuffer(nil)
+	c := court.Court{
+		Name:          "Landgericht Leipzig",
+		Ort:           "Leipzig",
+		Instanz:       "LG",
+		Spruchkoerper: []string{"Strafkammer", "Schwurgericht"},
+		Bundesland:    "SN",
+	}
+
+	id, err := m.AddCourt(c, buf)
+	if err != nil {
+		t.Fatalf("adding court: %v", err)
+	}
+	if id != 1 {
+		t.Fatalf("wrong id: expected 1, got %d", id)
+	}
+	expectedMsg := `{"ID":1,"Fields":{"Name":"Landgericht Leipzig","Ort":"Leipzig","Instanz":"LG","Spruchkoerper":["Strafkammer","Schwurgericht"],"Bundesland":"SN"}}`
+	if buf.String() != expectedMsg {
+		t.Fatalf("wrong message, expected %q, got %q", expectedMsg, buf.String())
+	}
+
+	if _, err := m.Retrieve(1); err != nil {
+		t.Fatalf("retrieving court: %v", err)
+	}
+	if id, ok := m.Find("landgericht  leipzig", "LEIPZIG"); !ok || id != 1 {
+		t.Fatalf("finding court: expected 1, got %d (%v)", id, ok)
+	}
+	_, err = m.Retrieve(42)
+	expectedErrMsg := "court 42 does not exist"
+	if err == nil || err.Error() != expectedErrMsg {
+		t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	m := court.Model{
+		1: {Name: "Amtsgericht Leipzig", Ort: "Leipzig", Instanz: "AG"},
+		2: {Name: "Landgericht Leipzig", Ort: "Leipzig", Instanz: "LG"},
+		3: {Name: "Amtsgericht Lübeck", Ort: "Lübeck", Instanz: "AG"},
+		4: {Name: "Amtsgericht Frankfurt am Main", Ort: "Frankfurt am Main", Instanz: "AG"},
+		5: {Name: "Bundesgerichtshof", Ort: "Karlsruhe", Instanz: "BGH"},
+	}
+
+	for _, tc := range []struct {
+		gericht  string
+		expected string
+	}{
+		{"AG Leipzig", "[{1 1}]"},
+		{"Amtsgericht Leipzig", "[{1 1}]"},
+		{"AG Leipzig, Schöffengericht 5 Ls 12/22", "[{1 1}]"},
+		{"AG Leipzig 123 Cs 456 Js 7890/2022; LG Leipzig", "[{1 1}]"},
+		{"LG Leipzig Strafkammer", "[{2 1}]"},
+		{"AG L.", "[{1 0.5} {3 0.5}]"},
+		{"Amtsgericht Frankfurt am Main", "[{4 1}]"},
+		{"BGH", "[{5 1}]"},
+		{"Leipzig", "[{1 0.5} {2 0.5}]"},
+		{"AG Dresden", "[]"},
+		{"", "[]"},
+	} {
+		t.Run(tc.gericht, func(t *testing.T) {
+			got := fmt.Sprint(court.Suggest(m, tc.gericht))
+			if got != tc.expected {
+				t.Fatalf("wrong suggestions: expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package court
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// instanzWords maps abbreviations and names of courts to the instance level.
+var instanzWords = map[string]string{
+	"ag":                "AG",
+	"amtsgericht":       "AG",
+	"lg":                "LG",
+	"landgericht":       "LG",
+	"olg":               "OLG",
+	"oberlandesgericht": "OLG",
+	"kg":                "OLG",
+	"kammergericht":     "OLG",
+	"bgh":               "BGH",
+	"bundesgerichtshof": "BGH",
+}
+
+// Suggestion is a court that might be meant by a free-text value.
+type Suggestion struct {
+	CourtID int     `json:"CourtID"`
+	Score   float64 `json:"Score"`
+}
+
+// Suggest returns the courts that might be meant by the free-text value of
+// the field Gericht of a case, e.g. "AG Leipzig 123 Cs 456 Js 7890/22",
+// "Amtsgericht Leipzig" or "AG L.". Only the first court of the value is
+// considered. A full place name scores 1, an abbreviated one 0.5. The best
+// suggestions come first.
+func Suggest(cs Model, gericht string) []Suggestion {
+	instanz, place, abbreviated := parseGericht(gericht)
+	if place == "" && instanz != "BGH" {
+		return []Suggestion{}
+	}
+
+	suggestions := []Suggestion{}
+	for _, id := range cs.IDs() {
+		c := cs[id]
+		if instanz != "" && c.Instanz != instanz {
+			continue
+		}
+		ort := normalize(c.Ort)
+		var score float64
+		switch {
+		case instanz == "BGH" && place == "":
+			score = 1
+		case ort == place && !abbreviated:
+			score = 1
+		case strings.HasPrefix(ort, place):
+			score = 0.5
+		default:
+			continue
+		}
+		if instanz == "" {
+			score /= 2
+		}
+		suggestions = append(suggestions, Suggestion{CourtID: id, Score: score})
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return suggestions[i].Score > suggestions[j].Score
+	})
+	return suggestions
+}
+
+// parseGericht returns the instance level and the normalized place of the
+// first court in the free-text value. The place is abbreviated if it ends with
+// a dot.
+func parseGericht(gericht string) (instanz string, place string, abbreviated bool) {
+	first := gericht
+	if i := strings.IndexAny(first, ",;"); i >= 0 {
+		first = first[:i]
+	}
+	var placeWords []string
+	for _, w := range strings.Fields(first) {
+		if strings.IndexFunc(w, unicode.IsDigit) >= 0 {
+			break
+		}
+		word := strings.Trim(w, ":-()")
+		if word == "" {
+			continue
+		}
+		if i, ok := instanzWords[normalize(word)]; ok && instanz == "" {
+			instanz = i
+			continue
+		}
+		if isFormation(word) || (len(placeWords) == 0 && !unicode.IsUpper([]rune(word)[0])) {
+			// Formations like "Schöffengericht" and leading lower case
+			// words like "in" are not part of the place.
+			continue
+		}
+		placeWords = append(placeWords, word)
+	}
+	if n := len(placeWords); n > 0 && strings.HasSuffix(placeWords[n-1], ".") {
+		abbreviated = true
+		placeWords[n-1] = strings.TrimSuffix(placeWords[n-1], ".")
+	}
+	return instanz, normalize(strings.Join(placeWords, " ")), abbreviated
+}
+
+func isFormation(word string) bool {
+	w := normalize(word)
+	for _, f := range []string{"strafrichter", "schöffengericht", "strafkammer", "schwurgericht", "strafsenat", "jugend"} {
+		if strings.Contains(w, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
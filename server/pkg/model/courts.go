@@ -0,0 +1,57 @@
+package model
+
+import (
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/court"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+// CaseCourtSuggestions contains the suggested courts for one case.
+type CaseCourtSuggestions struct {
+	CaseID      int                `json:"CaseID"`
+	Gericht     string             `json:"Gericht"`
+	Suggestions []court.Suggestion `json:"Suggestions"`
+}
+
+// CourtSuggestions returns suggested courts for all cases with a free-text
+// value in the field Gericht but without reference to a court. It is used to
+// migrate old cases to the court directory.
+func (m *Model) CourtSuggestions() []CaseCourtSuggestions {
+	result := []CaseCourtSuggestions{}
+	for _, id := range m.Case.IDs(lawcase.Filter{}) {
+		c := m.Case[id]
+		if c.GerichtID != 0 || c.Gericht == "" {
+			continue
+		}
+		result = append(result, CaseCourtSuggestions{
+			CaseID:      id,
+			Gericht:     c.Gericht,
+			Suggestions: court.Suggest(m.Court, c.Gericht),
+		})
+	}
+	return result
+}
+
+// CaseLand returns the Bundesland of the court of the case. If the case does
+// not reference a court of the court directory yet, the free-text field
+// Gericht is used if it matches exactly one court. Otherwise the result is
+// empty.
+func (m *Model) CaseLand(caseID int) string {
+	c, ok := m.Case[caseID]
+	if !ok {
+		return ""
+	}
+	if g, ok := m.Court[c.GerichtID]; ok {
+		return g.Bundesland
+	}
+	var land string
+	for _, s := range court.Suggest(m.Court, c.Gericht) {
+		if s.Score < 1 {
+			continue
+		}
+		if land != "" {
+			return ""
+		}
+		land = m.Court[s.CourtID].Bundesland
+	}
+	return land
+}
@@ -0,0 +1,66 @@
+package deadline
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/holiday"
+)
+
+// Rule describes a statutory deadline that starts with an event like the
+// pronouncement or the service of a judgment.
+type Rule struct {
+	Art      string `json:"Art"`
+	Norm     string `json:"Norm"`
+	Ereignis string `json:"Ereignis"`
+	Wochen   int    `json:"Wochen"`
+	Monate   int    `json:"Monate"`
+}
+
+// Rules contains the deadlines that can be calculated.
+var Rules = []Rule{
+	{Art: "Berufung", Norm: "§ 314 StPO", Ereignis: "Verkündung des Urteils", Wochen: 1},
+	{Art: "Revision", Norm: "§ 341 StPO", Ereignis: "Verkündung des Urteils", Wochen: 1},
+	{Art: "Revisionsbegründung", Norm: "§ 345 StPO", Ereignis: "Zustellung des Urteils", Monate: 1},
+	{Art: "Einspruch", Norm: "§ 410 StPO", Ereignis: "Zustellung des Strafbefehls", Wochen: 2},
+}
+
+// Calculate returns the last day of the deadline of the given kind that
+// starts with an event on the given day. The Bundesland is used for public
+// holidays.
+func Calculate(art string, event time.Time, land string) (time.Time, error) {
+	for _, r := range Rules {
+		if r.Art == art {
+			return NextWorkday(EndOfPeriod(event, r.Wochen, r.Monate), land), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown deadline %q", art)
+}
+
+// EndOfPeriod returns the end of a period of weeks and months according to
+// § 43 Abs. 1 StPO: The period ends with the day of the last week or month
+// that has the same name or number as the day of the event. If this day does
+// not exist in the last month, the period ends with the last day of the month.
+func EndOfPeriod(event time.Time, weeks, months int) time.Time {
+	d := time.Date(event.Year(), event.Month(), event.Day(), 0, 0, 0, 0, time.UTC)
+	d = d.AddDate(0, 0, 7*weeks)
+	if months == 0 {
+		return d
+	}
+	firstOfMonth := time.Date(d.Year(), d.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	day := d.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
+
+// NextWorkday returns the day itself or, if it is a Saturday, Sunday or
+// public holiday in the Bundesland, the next workday (§ 43 Abs. 2 StPO).
+func NextWorkday(d time.Time, land string) time.Time {
+	for !holiday.IsWorkday(d, land) {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
@@ -0,0 +1,204 @@
+/*
+Package deadline is about deadlines (Fristen) of cases. See calc.go for the
+calculation of the usual deadlines of the StPO.
+*/
+package deadline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// DateLayout is the layout of all dates of a deadline.
+const DateLayout = "2006-01-02"
+
+type Model map[int]Deadline
+
+type Deadline struct {
+	CaseID      int    `json:"CaseID" validate:"required"`
+	Art         string `json:"Art" validate:"required"`
+	Bezeichnung string `json:"Bezeichnung"`
+
+	// Ereignis is the day of the event that starts the deadline. It may be
+	// empty for deadlines that are not calculated.
+	Ereignis string `json:"Ereignis" validate:"omitempty,datetime=2006-01-02"`
+
+	// Ende is the last day of the deadline.
+	Ende       string `json:"Ende" validate:"required,datetime=2006-01-02"`
+	Bundesland string `json:"Bundesland" validate:"omitempty,oneof=BW BY BE BB HB HH HE MV NI NW RP SL SN ST SH TH"`
+
+	// Vorfrist is the day from which on the deadline is listed as upcoming
+	// regardless of its end. It is used for deadlines that require
+	// preparation well in advance.
+	Vorfrist string `json:"Vorfrist,omitempty" validate:"omitempty,datetime=2006-01-02"`
+
+	// Erledigt is the day the deadline was marked as done. It is empty for
+	// open deadlines.
+	Erledigt string `json:"Erledigt,omitempty"`
+}
+
+// Entry is a deadline with its id, used for lists.
+type Entry struct {
+	ID int `json:"ID"`
+	Deadline
+}
+
+// Done is the event data for marking a deadline as done.
+type Done struct {
+	ID    int    `json:"ID"`
+	Datum string `json:"Datum" validate:"required,datetime=2006-01-02"`
+}
+
+type decodedMsg struct {
+	ID     int      `json:"ID"`
+	Fields Deadline `json:"Fields"`
+}
+
+func (ds *Model) Load(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var d decodedMsg
+	if err := json.Unmarshal(msg, &d); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	if d.ID < 1 {
+		return fmt.Errorf("message contains invalid id %d", d.ID)
+	}
+	(*ds)[d.ID] = d.Fields
+	return nil
+}
+
+func (ds *Model) AddDeadline(dl Deadline, w io.Writer) (int, error) {
+	newID := ds.maxDeadlineID() + 1
+	d := decodedMsg{
+		ID:     newID,
+		Fields: dl,
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		return 0, fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return 0, fmt.Errorf("writing event data: %w", err)
+	}
+	(*ds)[newID] = dl
+	return newID, nil
+}
+
+// LoadDone applies a Done event.
+func (ds *Model) LoadDone(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var d Done
+	if err := json.Unmarshal(msg, &d); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	return ds.applyDone(d)
+}
+
+// MarkDone marks the deadline as done.
+func (ds *Model) MarkDone(d Done, w io.Writer) error {
+	dl, ok := (*ds)[d.ID]
+	if !ok {
+		return fmt.Errorf("deadline %d does not exist", d.ID)
+	}
+	if dl.Erledigt != "" {
+		return fmt.Errorf("deadline %d is already done", d.ID)
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("writing event data: %w", err)
+	}
+	return ds.applyDone(d)
+}
+
+func (ds *Model) applyDone(d Done) error {
+	dl, ok := (*ds)[d.ID]
+	if !ok {
+		return fmt.Errorf("deadline %d does not exist", d.ID)
+	}
+	dl.Erledigt = d.Datum
+	(*ds)[d.ID] = dl
+	return nil
+}
+
+func (ds Model) maxDeadlineID() int {
+	var result int
+	for n := range ds {
+		if n > result {
+			result = n
+		}
+	}
+	return result
+}
+
+func (ds Model) Retrieve(id int) (Deadline, error) {
+	dl, ok := ds[id]
+	if !ok {
+		return Deadline{}, fmt.Errorf("deadline %d does not exist", id)
+	}
+	return dl, nil
+}
+
+// Upcoming returns all open deadlines that end on the given day or within the
+// given number of days after it, sorted by end. Deadlines with a Vorfrist are
+// included from this day on.
+func (ds Model) Upcoming(today time.Time, days int) []Entry {
+	from := today.Format(DateLayout)
+	to := today.AddDate(0, 0, days).Format(DateLayout)
+	return ds.list(func(dl Deadline) bool {
+		if dl.Ende < from {
+			return false
+		}
+		return dl.Ende <= to || (dl.Vorfrist != "" && dl.Vorfrist <= from)
+	})
+}
+
+// Overdue returns all open deadlines that ended before the given day, sorted
+// by end.
+func (ds Model) Overdue(today time.Time) []Entry {
+	t := today.Format(DateLayout)
+	return ds.list(func(dl Deadline) bool {
+		return dl.Ende < t
+	})
+}
+
+// OfCase returns all deadlines of the case, sorted by end.
+func (ds Model) OfCase(caseID int) []Entry {
+	var result []Entry
+	for id, dl := range ds {
+		if dl.CaseID == caseID {
+			result = append(result, Entry{ID: id, Deadline: dl})
+		}
+	}
+	sortEntries(result)
+	return result
+}
+
+func (ds Model) list(match func(Deadline) bool) []Entry {
+	result := []Entry{}
+	for id, dl := range ds {
+		if dl.Erledigt == "" && match(dl) {
+			result = append(result, Entry{ID: id, Deadline: dl})
+		}
+	}
+	sortEntries(result)
+	return result
+}
+
+func sortEntries(l []Entry) {
+	sort.Slice(l, func(i, j int) bool {
+		if l[i].Ende != l[j].Ende {
+			return l[i].Ende < l[j].Ende
+		}
+		return l[i].ID < l[j].ID
+	})
+}
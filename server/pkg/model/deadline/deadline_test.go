@@ -0,0 +1,179 @@
+package deadline_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/deadline"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse(deadline.DateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestEndOfPeriod(t *testing.T) {
+	for _, tc := range []struct {
+		event         string
+		weeks, months int
+		expected      string
+	}{
+		{"2025-04-11", 1, 0, "2025-04-18"},
+		{"2025-10-15", 2, 0, "2025-10-29"},
+		{"2025-01-31", 0, 1, "2025-02-28"},
+		{"2024-01-31", 0, 1, "2024-02-29"},
+		{"2025-03-15", 0, 1, "2025-04-15"},
+		{"2025-12-20", 0, 1, "2026-01-20"},
+	} {
+		got := deadline.EndOfPeriod(date(tc.event), tc.weeks, tc.months).Format(deadline.DateLayout)
+		if got != tc.expected {
+			t.Fatalf("wrong end for %s + %d weeks + %d months: expected %s, got %s", tc.event, tc.weeks, tc.months, tc.expected, got)
+		}
+	}
+}
+
+func TestCalculate(t *testing.T) {
+	for _, tc := range []struct {
+		art      string
+		event    string
+		land     string
+		expected string
+	}{
+		{"Berufung", "2025-04-11", "SN", "2025-04-22"},
+		{"Revision", "2025-05-02", "BY", "2025-05-09"},
+		{"Revisionsbegründung", "2025-05-03", "BY", "2025-06-03"},
+		{"Revisionsbegründung", "2025-05-19", "BY", "2025-06-20"},
+		{"Revisionsbegründung", "2025-05-19", "BE", "2025-06-19"},
+		{"Revisionsbegründung", "2025-05-22", "BY", "2025-06-23"},
+		{"Einspruch", "2025-10-17", "SN", "2025-11-03"},
+		{"Einspruch", "2025-10-17", "BY", "2025-10-31"},
+	} {
+		got, err := deadline.Calculate(tc.art, date(tc.event), tc.land)
+		if err != nil {
+			t.Fatalf("calculating %s: %v", tc.art, err)
+		}
+		if got.Format(deadline.DateLayout) != tc.expected {
+			t.Fatalf("wrong end of %s after %s in %s: expected %s, got %s", tc.art, tc.event, tc.land, tc.expected, got.Format(deadline.DateLayout))
+		}
+	}
+
+	_, err := deadline.Calculate("Klage", date("2025-01-01"), "")
+	expectedErrMsg := `unknown deadline "Klage"`
+	if err == nil || err.Error() != expectedErrMsg {
+		t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+	}
+}
+
+func TestModel(t *testing.T) {
+	m := deadline.Model{}
+	buf := bytes.NewBuffer(nil)
+
+	for _, dl := range []deadline.Deadline{
+		{CaseID: 1, Art: "Berufung", Ende: "2025-04-22"},
+		{CaseID: 1, Art: "Revisionsbegründung", Ende: "2025-06-03"},
+		{CaseID: 2, Art: "Einspruch", Ende: "2025-05-02"},
+		{CaseID: 3, Art: "Halbstrafe", Ende: "2025-07-01", Vorfrist: "2025-04-02"},
+		{CaseID: 3, Art: "Strafende", Ende: "2025-12-01", Vorfrist: "2025-09-02"},
+	} {
+		if _, err := m.AddDeadline(dl, buf); err != nil {
+			t.Fatalf("adding deadline: %v", err)
+		}
+	}
+
+	ids := func(l []deadline.Entry) string {
+		var result []int
+		for _, e := range l {
+			result = append(result, e.ID)
+		}
+		return fmt.Sprint(result)
+	}
+
+	today := date("2025-04-25")
+	if got := ids(m.Overdue(today)); got != "[1]" {
+		t.Fatalf("wrong overdue deadlines: expected [1], got %s", got)
+	}
+	if got := ids(m.Upcoming(today, 14)); got != "[3 4]" {
+		t.Fatalf("wrong upcoming deadlines: expected [3 4], got %s", got)
+	}
+
+	buf.Reset()
+	if err := m.MarkDone(deadline.Done{ID: 1, Datum: "2025-04-20"}, buf); err != nil {
+		t.Fatalf("marking deadline as done: %v", err)
+	}
+	expectedMsg := `{"ID":1,"Datum":"2025-04-20"}`
+	if buf.String() != expectedMsg {
+		t.Fatalf("wrong message, expected %q, got %q", expectedMsg, buf.String())
+	}
+	if got := ids(m.Overdue(today)); got != "[]" {
+		t.Fatalf("wrong overdue deadlines: expected [], got %s", got)
+	}
+
+	err := m.MarkDone(deadline.Done{ID: 1, Datum: "2025-04-21"}, buf)
+	expectedErrMsg := "deadline 1 is already done"
+	if err == nil || err.Error() != expectedErrMsg {
+		t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+	}
+
+	t.Run("load events", func(t *testing.T) {
+		loaded := deadline.Model{}
+		if err := loaded.Load(json.RawMessage(`{"ID":2,"Fields":{"CaseID":1,"Art":"Revision","Ende":"2025-05-09"}}`)); err != nil {
+			t.Fatalf("loading deadline: %v", err)
+		}
+		if err := loaded.LoadDone(json.RawMessage(`{"ID":2,"Datum":"2025-05-08"}`)); err != nil {
+			t.Fatalf("loading done event: %v", err)
+		}
+		if loaded[2].Erledigt != "2025-05-08" {
+			t.Fatalf("wrong field Erledigt: expected %q, got %q", "2025-05-08", loaded[2].Erledigt)
+		}
+	})
+}
+
+func TestHaftpruefung(t *testing.T) {
+	for _, tc := range []struct {
+		festnahme string
+		n         int
+		expected  string
+	}{
+		{"2025-01-10", 0, "2025-07-09"},
+		{"2025-01-10", 1, "2025-10-09"},
+		{"2025-08-31", 0, "2026-02-28"},
+		{"2025-09-01", 0, "2026-02-28"},
+		{"2025-03-01", 0, "2025-08-31"},
+	} {
+		got := deadline.Haftpruefung(date(tc.festnahme), tc.n).Format(deadline.DateLayout)
+		if got != tc.expected {
+			t.Fatalf("wrong review %d after arrest on %s: expected %s, got %s", tc.n, tc.festnahme, tc.expected, got)
+		}
+	}
+}
+
+func TestCustody(t *testing.T) {
+	cs := lawcase.Model{
+		1: {Rubrum: "A", Haft: &lawcase.Haft{Festnahme: "2025-01-10", Haftbefehl: "Untersuchungshaft"}},
+		2: {Rubrum: "B", Haft: &lawcase.Haft{Festnahme: "2025-05-02", Haftbefehl: "Untersuchungshaft"}},
+		3: {Rubrum: "C", Haft: &lawcase.Haft{Festnahme: "2025-02-01", Haftbefehl: "Sicherungshaft"}},
+		4: {Rubrum: "D", Haft: &lawcase.Haft{Festnahme: "2025-03-01", Haftbefehl: "Untersuchungshaft", Entlassung: "2025-04-01"}},
+		5: {Rubrum: "E", Haft: &lawcase.Haft{Festnahme: "2025-01-02", Haftbefehl: "Untersuchungshaft", Urteil: "2025-06-01"}},
+		6: {Rubrum: "F"},
+	}
+
+	l := deadline.Custody(cs, date("2025-08-01"))
+	var got []string
+	for _, c := range l {
+		got = append(got, fmt.Sprintf("%d:%s", c.ID, c.Haftpruefung))
+	}
+	expected := "[1:2025-10-09 2:2025-11-01 5: 3:]"
+	if fmt.Sprint(got) != expected {
+		t.Fatalf("wrong custody cases: expected %s, got %v", expected, got)
+	}
+	if l[0].Tage != 204 || *l[0].Verbleibend != 69 {
+		t.Fatalf("wrong days of case 1: expected 204 in custody and 69 remaining, got %d and %d", l[0].Tage, *l[0].Verbleibend)
+	}
+}
@@ -0,0 +1,103 @@
+package deadline
+
+import (
+	"sort"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+// ReviewMonths is the interval of the further reviews by the
+// Oberlandesgericht after the first one (§ 122 Abs. 4 Satz 2 StPO).
+const ReviewMonths = 3
+
+// Haftpruefung returns the end of the n-th review period of pre-trial
+// detention. The first period (n = 0) is six months from the arrest
+// (§ 121 Abs. 1 StPO), every further one ReviewMonths later. The day of the
+// arrest counts, so the period ends the day before the day with the same
+// number or, if this day does not exist, on the last day of the month.
+func Haftpruefung(festnahme time.Time, n int) time.Time {
+	months := 6 + n*ReviewMonths
+	d := time.Date(festnahme.Year(), festnahme.Month(), festnahme.Day(), 0, 0, 0, 0, time.UTC)
+	end := EndOfPeriod(d, 0, months)
+	if end.Day() != d.Day() {
+		return end
+	}
+	return end.AddDate(0, 0, -1)
+}
+
+// NextHaftpruefung returns the end of the first review period that does not
+// end before today. It returns false if there are no reviews because the
+// custody is no pre-trial detention, ended or there is a judgment.
+func NextHaftpruefung(h lawcase.Haft, today time.Time) (time.Time, bool) {
+	if h.Haftbefehl != "Untersuchungshaft" || h.Entlassung != "" || h.Urteil != "" {
+		return time.Time{}, false
+	}
+	festnahme, err := time.Parse(DateLayout, h.Festnahme)
+	if err != nil {
+		return time.Time{}, false
+	}
+	for n := 0; ; n++ {
+		if d := Haftpruefung(festnahme, n); !d.Before(today) {
+			return d, true
+		}
+	}
+}
+
+// CustodyCase is a case where the client is in custody.
+type CustodyCase struct {
+	ID     int          `json:"ID"`
+	Rubrum string       `json:"Rubrum"`
+	Az     string       `json:"Az"`
+	Haft   lawcase.Haft `json:"Haft"`
+
+	// Tage is the number of days in custody including the day of the arrest.
+	Tage int `json:"Tage"`
+
+	// Haftpruefung is the end of the next review period under § 121 StPO
+	// and Verbleibend the number of days until then.
+	Haftpruefung string `json:"Haftpruefung,omitempty"`
+	Verbleibend  *int   `json:"Verbleibend,omitempty"`
+}
+
+// Custody returns all cases where the client is in custody, the most urgent
+// first: Cases with a review date sorted by this date, then the others sorted
+// by the date of the arrest.
+func Custody(cs lawcase.Model, today time.Time) []CustodyCase {
+	result := []CustodyCase{}
+	for id, c := range cs {
+		if !c.InHaft() {
+			continue
+		}
+		cc := CustodyCase{
+			ID:     id,
+			Rubrum: c.Rubrum,
+			Az:     c.Az,
+			Haft:   *c.Haft,
+		}
+		if festnahme, err := time.Parse(DateLayout, c.Haft.Festnahme); err == nil {
+			cc.Tage = int(today.Sub(festnahme).Hours()/24) + 1
+		}
+		if d, ok := NextHaftpruefung(*c.Haft, today); ok {
+			cc.Haftpruefung = d.Format(DateLayout)
+			days := int(d.Sub(today).Hours() / 24)
+			cc.Verbleibend = &days
+		}
+		result = append(result, cc)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if (a.Haftpruefung == "") != (b.Haftpruefung == "") {
+			return a.Haftpruefung != ""
+		}
+		if a.Haftpruefung != b.Haftpruefung {
+			return a.Haftpruefung < b.Haftpruefung
+		}
+		if a.Haft.Festnahme != b.Haft.Festnahme {
+			return a.Haft.Festnahme < b.Haft.Festnahme
+		}
+		return a.ID < b.ID
+	})
+	return result
+}
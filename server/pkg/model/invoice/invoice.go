@@ -0,0 +1,273 @@
+/*
+Package invoice is about invoices of cases. Invoices are immutable once they
+are issued. An invoice can only be cancelled by a cancellation invoice
+(Stornorechnung) with the negated items.
+
+Every invoice gets a number of the form 2025-0001. The numbers of a year have
+no gaps and follow the dates of the invoices.
+*/
+package invoice
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// DateLayout is the layout of all dates of an invoice.
+const DateLayout = "2006-01-02"
+
+type Model map[int]Invoice
+
+type Invoice struct {
+	// Nummer is assigned when the invoice is issued.
+	Nummer string `json:"Nummer"`
+
+	CaseID     int        `json:"CaseID" validate:"required"`
+	Datum      string     `json:"Datum" validate:"required,datetime=2006-01-02"`
+	Absender   Absender   `json:"Absender"`
+	Empfaenger Empfaenger `json:"Empfaenger"`
+	Positionen []Position `json:"Positionen" validate:"required,min=1,dive"`
+
+	// Steuersatz is the rate of the value added tax in percent.
+	Steuersatz int `json:"Steuersatz" validate:"min=0,max=100"`
+
+	// Netto, Umsatzsteuer and Brutto are the totals in cents. They are
+	// computed when the invoice is issued.
+	Netto        int `json:"Netto"`
+	Umsatzsteuer int `json:"Umsatzsteuer"`
+	Brutto       int `json:"Brutto"`
+
+	// Storno is the id of the invoice cancelled by this one. StorniertDurch
+	// is the id of the cancellation invoice of this one.
+	Storno         int `json:"Storno,omitempty"`
+	StorniertDurch int `json:"StorniertDurch,omitempty"`
+}
+
+// Absender is the law firm that issues the invoice.
+type Absender struct {
+	Name      string `json:"Name" validate:"required"`
+	Anschrift string `json:"Anschrift" validate:"required"`
+
+	// Steuernummer is the tax number or the VAT identification number
+	// (§ 14 Abs. 4 Satz 1 Nr. 2 UStG).
+	Steuernummer   string `json:"Steuernummer" validate:"required"`
+	Bankverbindung string `json:"Bankverbindung"`
+}
+
+// Empfaenger is the recipient of the invoice (Rechnungsempfänger).
+type Empfaenger struct {
+	Name      string `json:"Name" validate:"required"`
+	Anschrift string `json:"Anschrift"`
+}
+
+// Position is one item of an invoice. The amount is the net amount in cents.
+type Position struct {
+	Art         string `json:"Art" validate:"oneof=Gebuehr Verguetung Auslage"`
+	Nr          string `json:"Nr"`
+	Bezeichnung string `json:"Bezeichnung" validate:"required"`
+	Betrag      int    `json:"Betrag"`
+}
+
+// Entry is an invoice with its id, used for lists.
+type Entry struct {
+	ID int `json:"ID"`
+	Invoice
+}
+
+type decodedMsg struct {
+	ID     int     `json:"ID"`
+	Fields Invoice `json:"Fields"`
+}
+
+func (is *Model) Load(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var d decodedMsg
+	if err := json.Unmarshal(msg, &d); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	if d.ID < 1 {
+		return fmt.Errorf("message contains invalid id %d", d.ID)
+	}
+	is.apply(d.ID, d.Fields)
+	return nil
+}
+
+// Issue assigns the next number of the year to the invoice, computes the
+// totals and saves it. The date must not be before the date of the last
+// invoice of the year.
+func (is *Model) Issue(inv Invoice, w io.Writer) (int, error) {
+	if err := is.Check(inv); err != nil {
+		return 0, err
+	}
+	year := inv.Datum[:4]
+	count := 0
+	for _, other := range *is {
+		if other.Datum[:4] == year {
+			count++
+		}
+	}
+
+	inv.Nummer = fmt.Sprintf("%s-%04d", year, count+1)
+	inv.StorniertDurch = 0
+	inv.Netto = 0
+	for _, p := range inv.Positionen {
+		inv.Netto += p.Betrag
+	}
+	inv.Umsatzsteuer = percent(inv.Netto, inv.Steuersatz)
+	inv.Brutto = inv.Netto + inv.Umsatzsteuer
+
+	newID := is.maxInvoiceID() + 1
+	b, err := json.Marshal(decodedMsg{ID: newID, Fields: inv})
+	if err != nil {
+		return 0, fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return 0, fmt.Errorf("writing event data: %w", err)
+	}
+	is.apply(newID, inv)
+	return newID, nil
+}
+
+// Check returns an error if the invoice can not be issued.
+func (is Model) Check(inv Invoice) error {
+	if len(inv.Datum) != len(DateLayout) {
+		return fmt.Errorf("invalid Datum %q", inv.Datum)
+	}
+	if inv.Storno != 0 {
+		if err := is.CheckCancel(inv.Storno); err != nil {
+			return err
+		}
+	}
+	for _, other := range is {
+		if other.Datum[:4] == inv.Datum[:4] && other.Datum > inv.Datum {
+			return fmt.Errorf("Datum %s is before the date of invoice %s", inv.Datum, other.Nummer)
+		}
+	}
+	return nil
+}
+
+// Cancel issues a cancellation invoice for the invoice with the given id.
+func (is *Model) Cancel(id int, datum string, w io.Writer) (int, error) {
+	storno, err := is.Cancellation(id, datum)
+	if err != nil {
+		return 0, err
+	}
+	return is.Issue(storno, w)
+}
+
+// Cancellation returns the cancellation invoice for the invoice with the
+// given id without issuing it.
+func (is Model) Cancellation(id int, datum string) (Invoice, error) {
+	if err := is.CheckCancel(id); err != nil {
+		return Invoice{}, err
+	}
+	orig := is[id]
+	storno := Invoice{
+		CaseID:     orig.CaseID,
+		Datum:      datum,
+		Absender:   orig.Absender,
+		Empfaenger: orig.Empfaenger,
+		Steuersatz: orig.Steuersatz,
+		Storno:     id,
+	}
+	for _, p := range orig.Positionen {
+		p.Betrag = -p.Betrag
+		storno.Positionen = append(storno.Positionen, p)
+	}
+	return storno, nil
+}
+
+// CheckCancel returns an error if the invoice can not be cancelled.
+func (is Model) CheckCancel(id int) error {
+	inv, ok := is[id]
+	if !ok {
+		return fmt.Errorf("invoice %d does not exist", id)
+	}
+	if inv.Storno != 0 {
+		return fmt.Errorf("invoice %d is a cancellation invoice", id)
+	}
+	if inv.StorniertDurch != 0 {
+		return fmt.Errorf("invoice %d is already cancelled", id)
+	}
+	return nil
+}
+
+func (is *Model) apply(id int, inv Invoice) {
+	(*is)[id] = inv
+	if orig, ok := (*is)[inv.Storno]; ok && inv.Storno != 0 {
+		orig.StorniertDurch = id
+		(*is)[inv.Storno] = orig
+	}
+}
+
+func (is Model) maxInvoiceID() int {
+	var result int
+	for n := range is {
+		if n > result {
+			result = n
+		}
+	}
+	return result
+}
+
+func (is Model) Retrieve(id int) (Invoice, error) {
+	inv, ok := is[id]
+	if !ok {
+		return Invoice{}, fmt.Errorf("invoice %d does not exist", id)
+	}
+	return inv, nil
+}
+
+// OfCase returns all invoices of the case sorted by number.
+func (is Model) OfCase(caseID int) []Entry {
+	result := []Entry{}
+	for id, inv := range is {
+		if inv.CaseID == caseID {
+			result = append(result, Entry{ID: id, Invoice: inv})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Nummer < result[j].Nummer
+	})
+	return result
+}
+
+// percent returns the given percentage of the amount in cents, rounded half
+// away from zero.
+func percent(amount, rate int) int {
+	if amount < 0 {
+		return -percent(-amount, rate)
+	}
+	return (amount*rate + 50) / 100
+}
+
+// Load applies the event that sets the law firm issuing invoices.
+func (a *Absender) Load(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var n Absender
+	if err := json.Unmarshal(msg, &n); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	*a = n
+	return nil
+}
+
+// Set replaces the law firm issuing invoices. Issued invoices keep the
+// former values.
+func (a *Absender) Set(n Absender, w io.Writer) error {
+	b, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("writing event data: %w", err)
+	}
+	*a = n
+	return nil
+}
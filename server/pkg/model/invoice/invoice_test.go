@@ -0,0 +1,130 @@
+package invoice_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/invoice"
+)
+
+func newInvoice(datum string, betraege ...int) invoice.Invoice {
+	inv := invoice.Invoice{CaseID: 1, Datum: datum, Steuersatz: 19}
+	for _, b := range betraege {
+		inv.Positionen = append(inv.Positionen, invoice.Position{Art: "Gebuehr", Bezeichnung: "Gebühr", Betrag: b})
+	}
+	return inv
+}
+
+func TestIssue(t *testing.T) {
+	is := invoice.Model{}
+	buf := new(bytes.Buffer)
+
+	t.Run("numbers and totals", func(t *testing.T) {
+		for i, tc := range []struct {
+			datum    string
+			expected string
+		}{
+			{"2025-03-01", "2025-0001"},
+			{"2025-03-01", "2025-0002"},
+			{"2025-12-30", "2025-0003"},
+			{"2026-01-02", "2026-0001"},
+		} {
+			id, err := is.Issue(newInvoice(tc.datum, 21450, 4290), buf)
+			if err != nil {
+				t.Fatalf("issuing invoice: %v", err)
+			}
+			if id != i+1 {
+				t.Fatalf("wrong id: expected %d, got %d", i+1, id)
+			}
+			got := is[id]
+			if got.Nummer != tc.expected {
+				t.Fatalf("wrong Nummer: expected %q, got %q", tc.expected, got.Nummer)
+			}
+			if got.Netto != 25740 || got.Umsatzsteuer != 4891 || got.Brutto != 30631 {
+				t.Fatalf("wrong totals: expected 25740 4891 30631, got %d %d %d", got.Netto, got.Umsatzsteuer, got.Brutto)
+			}
+		}
+	})
+
+	t.Run("date before last invoice of the year", func(t *testing.T) {
+		_, err := is.Issue(newInvoice("2025-12-29", 100), buf)
+		expected := "Datum 2025-12-29 is before the date of invoice 2025-0003"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("wrong error: expected %q, got %v", expected, err)
+		}
+		if len(is) != 4 {
+			t.Fatalf("wrong number of invoices: expected 4, got %d", len(is))
+		}
+	})
+
+	t.Run("load", func(t *testing.T) {
+		loaded := invoice.Model{}
+		dec := json.NewDecoder(buf)
+		for dec.More() {
+			var msg json.RawMessage
+			if err := dec.Decode(&msg); err != nil {
+				t.Fatalf("decoding event: %v", err)
+			}
+			if err := loaded.Load(msg); err != nil {
+				t.Fatalf("loading event: %v", err)
+			}
+		}
+		if len(loaded) != 4 || loaded[4].Nummer != "2026-0001" {
+			t.Fatalf("wrong loaded invoices: got %v", loaded)
+		}
+	})
+}
+
+func TestCancel(t *testing.T) {
+	is := invoice.Model{}
+	buf := new(bytes.Buffer)
+	id, err := is.Issue(newInvoice("2025-03-01", 21450, 4290), buf)
+	if err != nil {
+		t.Fatalf("issuing invoice: %v", err)
+	}
+
+	stornoID, err := is.Cancel(id, "2025-04-01", buf)
+	if err != nil {
+		t.Fatalf("cancelling invoice: %v", err)
+	}
+	storno := is[stornoID]
+	if storno.Nummer != "2025-0002" || storno.Storno != id {
+		t.Fatalf("wrong cancellation invoice: got %q for %d", storno.Nummer, storno.Storno)
+	}
+	if storno.Brutto != -30631 || storno.Umsatzsteuer != -4891 {
+		t.Fatalf("wrong totals: expected -4891 -30631, got %d %d", storno.Umsatzsteuer, storno.Brutto)
+	}
+	if is[id].StorniertDurch != stornoID {
+		t.Fatalf("wrong StorniertDurch: expected %d, got %d", stornoID, is[id].StorniertDurch)
+	}
+
+	for _, tc := range []struct {
+		id       int
+		expected string
+	}{
+		{id, "invoice 1 is already cancelled"},
+		{stornoID, "invoice 2 is a cancellation invoice"},
+		{3, "invoice 3 does not exist"},
+	} {
+		_, err := is.Cancel(tc.id, "2025-04-02", buf)
+		if err == nil || err.Error() != tc.expected {
+			t.Fatalf("wrong error: expected %q, got %v", tc.expected, err)
+		}
+	}
+
+	loaded := invoice.Model{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var msg json.RawMessage
+		if err := dec.Decode(&msg); err != nil {
+			t.Fatalf("decoding event: %v", err)
+		}
+		if err := loaded.Load(msg); err != nil {
+			t.Fatalf("loading event: %v", err)
+		}
+	}
+	if loaded[id].StorniertDurch != stornoID {
+		t.Fatalf("wrong StorniertDurch after loading: expected %d, got %d", stornoID, loaded[id].StorniertDurch)
+	}
+}
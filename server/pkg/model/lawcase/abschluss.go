@@ -0,0 +1,126 @@
+package lawcase
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Ergebnisse is the catalog of outcomes of a case by their short names.
+var Ergebnisse = map[string]string{
+	"Freispruch":      "Freispruch",
+	"Einstellung153":  "Einstellung wegen Geringfügigkeit (§ 153 StPO)",
+	"Einstellung153a": "Einstellung gegen Auflagen (§ 153a StPO)",
+	"Einstellung170":  "Einstellung mangels hinreichenden Tatverdachts (§ 170 Abs. 2 StPO)",
+	"Strafbefehl":     "Strafbefehl (§§ 407 ff. StPO)",
+	"Verurteilung":    "Verurteilung",
+}
+
+// Strafe is the sentence of a conviction or a Strafbefehl.
+type Strafe struct {
+	// Freiheitsstrafe is the prison term in months.
+	Freiheitsstrafe int  `json:"Freiheitsstrafe,omitempty" validate:"min=0"`
+	Bewaehrung      bool `json:"Bewaehrung,omitempty"`
+
+	// Tagessaetze is the number of daily fines, Tagessatzhoehe the amount of
+	// one daily fine in euros (§ 40 StGB).
+	Tagessaetze    int `json:"Tagessaetze,omitempty" validate:"min=0,max=720"`
+	Tagessatzhoehe int `json:"Tagessatzhoehe,omitempty" validate:"min=0,max=30000"`
+
+	// Fahrverbot is the driving ban in months (§ 44 StGB).
+	Fahrverbot int `json:"Fahrverbot,omitempty" validate:"min=0,max=6"`
+}
+
+// Geldstrafe returns the total amount of the fine in euros.
+func (s Strafe) Geldstrafe() int {
+	return s.Tagessaetze * s.Tagessatzhoehe
+}
+
+// Abschluss is the event data for closing a case with its outcome. Strafe is
+// required for the outcomes Verurteilung and Strafbefehl and not allowed for
+// the others.
+type Abschluss struct {
+	ID       int     `json:"ID" validate:"required"`
+	Datum    string  `json:"Datum" validate:"required"`
+	Ergebnis string  `json:"Ergebnis" validate:"oneof=Freispruch Einstellung153 Einstellung153a Einstellung170 Strafbefehl Verurteilung"`
+	Strafe   *Strafe `json:"Strafe,omitempty"`
+}
+
+// HasStrafe reports whether the outcome comes with a sentence.
+func (a Abschluss) HasStrafe() bool {
+	return a.Ergebnis == "Verurteilung" || a.Ergebnis == "Strafbefehl"
+}
+
+// Check checks the sentence against the outcome and the rules of the StGB.
+func (a Abschluss) Check() error {
+	if !a.HasStrafe() {
+		if a.Strafe != nil {
+			return fmt.Errorf("Strafe: not allowed for outcome %s", a.Ergebnis)
+		}
+		return nil
+	}
+	s := a.Strafe
+	if s == nil {
+		return fmt.Errorf("Strafe: required for outcome %s", a.Ergebnis)
+	}
+	if s.Freiheitsstrafe == 0 && s.Tagessaetze == 0 {
+		return fmt.Errorf("Strafe: Freiheitsstrafe or Tagessaetze required")
+	}
+	if (s.Tagessaetze == 0) != (s.Tagessatzhoehe == 0) {
+		return fmt.Errorf("Strafe: Tagessaetze and Tagessatzhoehe must be given together")
+	}
+	if s.Bewaehrung && (s.Freiheitsstrafe == 0 || s.Freiheitsstrafe > 24) {
+		return fmt.Errorf("Strafe: probation requires a prison term of at most 24 months (§ 56 StGB)")
+	}
+	if a.Ergebnis == "Strafbefehl" && (s.Freiheitsstrafe > 12 || (s.Freiheitsstrafe > 0 && !s.Bewaehrung)) {
+		return fmt.Errorf("Strafe: a Strafbefehl allows a prison term of at most 12 months on probation (§ 407 Abs. 2 StPO)")
+	}
+	return nil
+}
+
+// LoadAbschluss applies an Abschluss event.
+func (cs *Model) LoadAbschluss(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var a Abschluss
+	if err := json.Unmarshal(msg, &a); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	return cs.applyAbschluss(a)
+}
+
+// CloseCase records the outcome of the case. A case can be closed again, e.g.
+// to correct the outcome.
+func (cs *Model) CloseCase(a Abschluss, w io.Writer) error {
+	if _, ok := (*cs)[a.ID]; !ok {
+		return fmt.Errorf("case %d does not exist", a.ID)
+	}
+	if err := a.Check(); err != nil {
+		return err
+	}
+	b, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("writing event data: %w", err)
+	}
+	return cs.applyAbschluss(a)
+}
+
+// applyAbschluss sets the outcome. The case ends with the date of the outcome
+// if it has no end yet. The free-text field Stand is left alone, a case is
+// closed if it has an Abschluss.
+func (cs *Model) applyAbschluss(a Abschluss) error {
+	c, ok := (*cs)[a.ID]
+	if !ok {
+		return fmt.Errorf("case %d does not exist", a.ID)
+	}
+	c.Abschluss = &a
+	if c.Ende == "" {
+		c.Ende = a.Datum
+	}
+	(*cs)[a.ID] = c
+	return nil
+}
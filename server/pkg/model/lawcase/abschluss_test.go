@@ -0,0 +1,72 @@
+package lawcase_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+func TestCloseCase(t *testing.T) {
+	m := lawcase.Model{1: {Rubrum: "A", Stand: "Berufung der StA angekündigt"}, 2: {Rubrum: "B", Ende: "2025-03-01", Stand: "laufend"}}
+	buf := bytes.NewBuffer(nil)
+
+	for _, a := range []lawcase.Abschluss{
+		{ID: 1, Datum: "2025-05-02", Ergebnis: "Verurteilung", Strafe: &lawcase.Strafe{Freiheitsstrafe: 18, Bewaehrung: true, Fahrverbot: 3}},
+		{ID: 2, Datum: "2025-04-01", Ergebnis: "Einstellung153a"},
+	} {
+		if err := m.CloseCase(a, buf); err != nil {
+			t.Fatalf("closing case %d: %v", a.ID, err)
+		}
+	}
+
+	t.Run("outcome", func(t *testing.T) {
+		c := m[1]
+		if c.Abschluss == nil || c.Abschluss.Strafe.Freiheitsstrafe != 18 || c.Ende != "2025-05-02" {
+			t.Fatalf("wrong closed case: %+v", c)
+		}
+		if c.Stand != "Berufung der StA angekündigt" {
+			t.Fatalf("wrong Stand: expected the free text to be kept, got %q", c.Stand)
+		}
+		if m[2].Ende != "2025-03-01" {
+			t.Fatalf("wrong end: expected %q to be kept, got %q", "2025-03-01", m[2].Ende)
+		}
+	})
+
+	t.Run("events can be loaded", func(t *testing.T) {
+		m2 := lawcase.Model{1: {Rubrum: "A"}, 2: {Rubrum: "B", Ende: "2025-03-01"}}
+		d := json.NewDecoder(buf)
+		for d.More() {
+			var msg json.RawMessage
+			if err := d.Decode(&msg); err != nil {
+				t.Fatalf("decoding event: %v", err)
+			}
+			if err := m2.LoadAbschluss(msg); err != nil {
+				t.Fatalf("loading event: %v", err)
+			}
+		}
+		if *m2[1].Abschluss.Strafe != *m[1].Abschluss.Strafe || m2[2].Abschluss.Ergebnis != "Einstellung153a" {
+			t.Fatalf("wrong loaded cases: %+v", m2)
+		}
+	})
+
+	t.Run("invalid sentences", func(t *testing.T) {
+		for _, tc := range []struct {
+			a        lawcase.Abschluss
+			expected string
+		}{
+			{lawcase.Abschluss{ID: 3, Ergebnis: "Freispruch"}, "case 3 does not exist"},
+			{lawcase.Abschluss{ID: 1, Ergebnis: "Verurteilung"}, "Strafe: required for outcome Verurteilung"},
+			{lawcase.Abschluss{ID: 1, Ergebnis: "Freispruch", Strafe: &lawcase.Strafe{Tagessaetze: 30, Tagessatzhoehe: 20}}, "Strafe: not allowed for outcome Freispruch"},
+			{lawcase.Abschluss{ID: 1, Ergebnis: "Verurteilung", Strafe: &lawcase.Strafe{Tagessaetze: 30}}, "Strafe: Tagessaetze and Tagessatzhoehe must be given together"},
+			{lawcase.Abschluss{ID: 1, Ergebnis: "Verurteilung", Strafe: &lawcase.Strafe{Freiheitsstrafe: 30, Bewaehrung: true}}, "Strafe: probation requires a prison term of at most 24 months (§ 56 StGB)"},
+			{lawcase.Abschluss{ID: 1, Ergebnis: "Strafbefehl", Strafe: &lawcase.Strafe{Freiheitsstrafe: 6}}, "Strafe: a Strafbefehl allows a prison term of at most 12 months on probation (§ 407 Abs. 2 StPO)"},
+		} {
+			err := m.CloseCase(tc.a, buf)
+			if err == nil || err.Error() != tc.expected {
+				t.Fatalf("expected error %q, got %v", tc.expected, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package lawcase
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/aktenzeichen"
+)
+
+// Aktenzeichen is a case number of the case at one authority. A criminal case
+// usually gets several numbers (police, Staatsanwaltschaft, courts and
+// Vollstreckung) while it moves through the proceeding.
+type Aktenzeichen struct {
+	Behoerde string `json:"Behoerde" validate:"oneof=Polizei Staatsanwaltschaft Amtsgericht Landgericht Oberlandesgericht Bundesgerichtshof Vollstreckung"`
+	Nummer   string `json:"Nummer" validate:"required"`
+
+	// Von and Bis are the validity period. An empty Bis means the number is
+	// still valid.
+	Von string `json:"Von"`
+	Bis string `json:"Bis"`
+}
+
+// AzChange is the event data for a new case number of a case.
+type AzChange struct {
+	ID       int    `json:"ID"`
+	Behoerde string `json:"Behoerde" validate:"oneof=Polizei Staatsanwaltschaft Amtsgericht Landgericht Oberlandesgericht Bundesgerichtshof Vollstreckung"`
+	Nummer   string `json:"Nummer" validate:"required"`
+	Datum    string `json:"Datum" validate:"required"`
+}
+
+// Warnings returns hints about questionable values of the change that do not
+// prevent saving it.
+func (ch AzChange) Warnings() []string {
+	if ch.Behoerde == "Polizei" {
+		return nil
+	}
+	a, err := aktenzeichen.Parse(ch.Nummer)
+	if err != nil {
+		return []string{fmt.Sprintf("Nummer: %v", err)}
+	}
+	isStA := ch.Behoerde == aktenzeichen.Staatsanwaltschaft || ch.Behoerde == "Vollstreckung"
+	if isStA != (a.Behoerde == aktenzeichen.Staatsanwaltschaft) {
+		return []string{fmt.Sprintf("Nummer: register sign %s does not belong to %s", a.Registerzeichen, ch.Behoerde)}
+	}
+	return nil
+}
+
+// LoadAz applies a AzChange event.
+func (cs *Model) LoadAz(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var ch AzChange
+	if err := json.Unmarshal(msg, &ch); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	return cs.applyAz(ch)
+}
+
+// ChangeAz adds a new case number to the case. A still valid case number of
+// the same authority ends at the date of the change. Valid case numbers are
+// normalized.
+func (cs *Model) ChangeAz(ch AzChange, w io.Writer) error {
+	if _, ok := (*cs)[ch.ID]; !ok {
+		return fmt.Errorf("case %d does not exist", ch.ID)
+	}
+	ch.Nummer = aktenzeichen.Normalize(ch.Nummer)
+	b, err := json.Marshal(ch)
+	if err != nil {
+		return fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("writing event data: %w", err)
+	}
+	return cs.applyAz(ch)
+}
+
+func (cs *Model) applyAz(ch AzChange) error {
+	c, ok := (*cs)[ch.ID]
+	if !ok {
+		return fmt.Errorf("case %d does not exist", ch.ID)
+	}
+	l := make([]Aktenzeichen, 0, len(c.Aktenzeichen)+1)
+	for _, a := range c.Aktenzeichen {
+		if a.Behoerde == ch.Behoerde && a.Bis == "" {
+			a.Bis = ch.Datum
+		}
+		l = append(l, a)
+	}
+	c.Aktenzeichen = append(l, Aktenzeichen{
+		Behoerde: ch.Behoerde,
+		Nummer:   ch.Nummer,
+		Von:      ch.Datum,
+	})
+	(*cs)[ch.ID] = c
+	return nil
+}
+
+// WithCaseNumber returns the ids of all cases with the given case number,
+// current or historical, sorted by id.
+func (cs Model) WithCaseNumber(a aktenzeichen.Aktenzeichen) []int {
+	ids := []int{}
+	for id, c := range cs {
+		for _, n := range c.CaseNumbers() {
+			if aktenzeichen.Compare(n, a) == 0 {
+				ids = append(ids, id)
+				break
+			}
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,102 @@
+package lawcase_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/aktenzeichen"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+func TestChangeAz(t *testing.T) {
+	m := lawcase.Model{1: {Rubrum: "A"}}
+	buf := bytes.NewBuffer(nil)
+
+	for _, ch := range []lawcase.AzChange{
+		{ID: 1, Behoerde: "Polizei", Nummer: "ST/0123456/2025", Datum: "2025-01-10"},
+		{ID: 1, Behoerde: "Staatsanwaltschaft", Nummer: "123  Js 4567/2025", Datum: "2025-02-01"},
+		{ID: 1, Behoerde: "Amtsgericht", Nummer: "5 Ls 12/26", Datum: "2026-01-15"},
+		{ID: 1, Behoerde: "Staatsanwaltschaft", Nummer: "124 Js 1/26", Datum: "2026-02-01"},
+	} {
+		if err := m.ChangeAz(ch, buf); err != nil {
+			t.Fatalf("changing case number: %v", err)
+		}
+	}
+
+	expected := "[{Polizei ST/0123456/2025 2025-01-10 } " +
+		"{Staatsanwaltschaft 123 Js 4567/25 2025-02-01 2026-02-01} " +
+		"{Amtsgericht 5 Ls 12/26 2026-01-15 } " +
+		"{Staatsanwaltschaft 124 Js 1/26 2026-02-01 }]"
+	if got := fmt.Sprint(m[1].Aktenzeichen); got != expected {
+		t.Fatalf("wrong case numbers: expected %s, got %s", expected, got)
+	}
+
+	t.Run("events can be loaded", func(t *testing.T) {
+		m2 := lawcase.Model{1: {Rubrum: "A"}}
+		d := json.NewDecoder(buf)
+		for d.More() {
+			var msg json.RawMessage
+			if err := d.Decode(&msg); err != nil {
+				t.Fatalf("decoding event: %v", err)
+			}
+			if err := m2.LoadAz(msg); err != nil {
+				t.Fatalf("loading event: %v", err)
+			}
+		}
+		if got := fmt.Sprint(m2[1].Aktenzeichen); got != expected {
+			t.Fatalf("wrong case numbers: expected %s, got %s", expected, got)
+		}
+	})
+
+	t.Run("search finds historical numbers", func(t *testing.T) {
+		text := m[1].TextFields()["Aktenzeichen"]
+		for _, n := range []string{"ST/0123456/2025", "123 Js 4567/25", "123Js4567/25", "124 Js 1/26"} {
+			if !strings.Contains(text, n) {
+				t.Fatalf("text field Aktenzeichen %q does not contain %q", text, n)
+			}
+		}
+	})
+
+	t.Run("cases with case number", func(t *testing.T) {
+		m[2] = lawcase.Case{Rubrum: "B", Az: "123 Js 4567/2025"}
+		m[3] = lawcase.Case{Rubrum: "C", Az: "5 Ls 13/26"}
+		a, err := aktenzeichen.Parse("123 Js 4567/25")
+		if err != nil {
+			t.Fatalf("parsing case number: %v", err)
+		}
+		if got := fmt.Sprint(m.WithCaseNumber(a)); got != "[1 2]" {
+			t.Fatalf("wrong cases: expected %s, got %s", "[1 2]", got)
+		}
+		delete(m, 2)
+		delete(m, 3)
+	})
+
+	t.Run("unknown case", func(t *testing.T) {
+		err := m.ChangeAz(lawcase.AzChange{ID: 2}, buf)
+		expectedErrMsg := "case 2 does not exist"
+		if err == nil || err.Error() != expectedErrMsg {
+			t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+		}
+	})
+}
+
+func TestAzChangeWarnings(t *testing.T) {
+	for _, tc := range []struct {
+		ch       lawcase.AzChange
+		expected string
+	}{
+		{lawcase.AzChange{Behoerde: "Polizei", Nummer: "ST/0123456/2025"}, "[]"},
+		{lawcase.AzChange{Behoerde: "Staatsanwaltschaft", Nummer: "123 Js 4567/25"}, "[]"},
+		{lawcase.AzChange{Behoerde: "Landgericht", Nummer: "1 KLs 3/26"}, "[]"},
+		{lawcase.AzChange{Behoerde: "Amtsgericht", Nummer: "123 Js 4567/25"}, "[Nummer: register sign Js does not belong to Amtsgericht]"},
+		{lawcase.AzChange{Behoerde: "Amtsgericht", Nummer: "foo"}, `[Nummer: unrecognized case number "foo"]`},
+	} {
+		got := fmt.Sprint(tc.ch.Warnings())
+		if got != tc.expected {
+			t.Fatalf("wrong warnings for %v: expected %s, got %s", tc.ch, tc.expected, got)
+		}
+	}
+}
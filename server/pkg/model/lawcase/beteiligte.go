@@ -0,0 +1,53 @@
+package lawcase
+
+// Beteiligter links a person (see package person) to a case with the role of
+// the person in the case. Mandant is the person we represent.
+type Beteiligter struct {
+	PersonID int    `json:"PersonID" validate:"required"`
+	Rolle    string `json:"Rolle" validate:"oneof=Mandant Beschuldigter Mitbeschuldigter Verletzter Zeuge"`
+}
+
+// Seite returns the side of the proceeding we are on, i. e. the role of our
+// client: Beschuldigter, Verletzter or Zeuge. It is derived from the main role
+// of the case.
+func (c Case) Seite() string {
+	switch c.Art {
+	case "Verteidiger":
+		return "Beschuldigter"
+	case "Nebenkläger", "Adhäsionskläger", "Verletztenbeistand":
+		return "Verletzter"
+	default:
+		return "Zeuge"
+	}
+}
+
+// Gegner returns the ids of all persons in the case whose interests are
+// opposed to the interests of our client, e. g. the victim if we are defence
+// counsel or the accused if we represent the victim.
+func (c Case) Gegner() []int {
+	var ids []int
+	for _, b := range c.Beteiligte {
+		switch c.Seite() {
+		case "Beschuldigter":
+			if b.Rolle == "Verletzter" {
+				ids = append(ids, b.PersonID)
+			}
+		case "Verletzter":
+			if b.Rolle == "Beschuldigter" || b.Rolle == "Mitbeschuldigter" {
+				ids = append(ids, b.PersonID)
+			}
+		}
+	}
+	return ids
+}
+
+// Mandanten returns the ids of all persons we represent in the case.
+func (c Case) Mandanten() []int {
+	var ids []int
+	for _, b := range c.Beteiligte {
+		if b.Rolle == "Mandant" {
+			ids = append(ids, b.PersonID)
+		}
+	}
+	return ids
+}
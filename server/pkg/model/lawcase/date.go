@@ -0,0 +1,53 @@
+package lawcase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayout is the layout used for all dates stored in a case, e.g. the
+// field Beginn. It is the format of the HTML date input element.
+const DateLayout = "2006-01-02"
+
+var dateLayouts = []string{
+	DateLayout,
+	"02.01.2006",
+	"2.1.2006",
+	"02.01.06",
+	"2.1.06",
+	"2 January 2006",
+	"2 Jan 2006",
+}
+
+var germanMonths = strings.NewReplacer(
+	"Januar", "January",
+	"Jänner", "January",
+	"Februar", "February",
+	"März", "March",
+	"Mär", "Mar",
+	"Maerz", "March",
+	"Mai", "May",
+	"Juni", "June",
+	"Juli", "July",
+	"Oktober", "October",
+	"Dezember", "December",
+	"Mrz", "Mar",
+	"Okt", "Oct",
+	"Dez", "Dec",
+)
+
+// ParseDate parses a date given in ISO format (2022-03-01) or in one of the
+// usual German formats (01.03.2022, 1.3.22 or 1. März 2022).
+func ParseDate(s string) (time.Time, error) {
+	v := strings.TrimSpace(s)
+	v = strings.ReplaceAll(v, ". ", " ")
+	v = strings.Join(strings.Fields(v), " ")
+	v = germanMonths.Replace(v)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
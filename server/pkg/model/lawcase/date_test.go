@@ -0,0 +1,40 @@
+package lawcase_test
+
+import (
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+func TestParseDate(t *testing.T) {
+	for _, tc := range []struct {
+		input    string
+		expected string
+	}{
+		{"2022-03-01", "2022-03-01"},
+		{"01.03.2022", "2022-03-01"},
+		{"1.3.2022", "2022-03-01"},
+		{"01.03.22", "2022-03-01"},
+		{" 1. März 2022 ", "2022-03-01"},
+		{"1. Dez. 2021", "2021-12-01"},
+		{"24. Juli 2020", "2020-07-24"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := lawcase.ParseDate(tc.input)
+			if err != nil {
+				t.Fatalf("parsing date: %v", err)
+			}
+			if got.Format(lawcase.DateLayout) != tc.expected {
+				t.Fatalf("wrong date: expected %q, got %q", tc.expected, got.Format(lawcase.DateLayout))
+			}
+		})
+	}
+
+	t.Run("invalid date", func(t *testing.T) {
+		_, err := lawcase.ParseDate("noch anhängig")
+		expectedErrMsg := `invalid date "noch anhängig"`
+		if err == nil || err.Error() != expectedErrMsg {
+			t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+		}
+	})
+}
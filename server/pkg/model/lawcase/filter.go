@@ -0,0 +1,64 @@
+package lawcase
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Filter selects cases. Empty fields are ignored.
+type Filter struct {
+	// Art must be one of the roles of the case (see Case.Roles).
+	Art string
+
+	// Stand must be a prefix of the field Stand (case insensitive) because
+	// it is free text like "abgeschlossen, rechtskräftig seit ...".
+	Stand string
+
+	// Gericht must be contained in the field Gericht (case insensitive).
+	Gericht string
+
+	// From and To select all cases that were active at least one day in the
+	// given range. A case without a valid Ende date is active up to now.
+	From time.Time
+	To   time.Time
+}
+
+// Match reports whether the given case is selected by the filter.
+func (f Filter) Match(c Case) bool {
+	if f.Art != "" && !c.HasRole(f.Art) {
+		return false
+	}
+	if f.Stand != "" && !strings.HasPrefix(strings.ToLower(c.Stand), strings.ToLower(f.Stand)) {
+		return false
+	}
+	if f.Gericht != "" && !strings.Contains(strings.ToLower(c.Gericht), strings.ToLower(f.Gericht)) {
+		return false
+	}
+	if f.From.IsZero() && f.To.IsZero() {
+		return true
+	}
+	beginn, err := ParseDate(c.Beginn)
+	if err != nil {
+		return false
+	}
+	if !f.To.IsZero() && beginn.After(f.To) {
+		return false
+	}
+	if ende, err := ParseDate(c.Ende); err == nil && !f.From.IsZero() && ende.Before(f.From) {
+		return false
+	}
+	return true
+}
+
+// IDs returns the ids of all cases selected by the filter in ascending order.
+func (cs Model) IDs(f Filter) []int {
+	ids := []int{}
+	for id, c := range cs {
+		if f.Match(c) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
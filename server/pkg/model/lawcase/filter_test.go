@@ -0,0 +1,46 @@
+package lawcase_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+func TestFilter(t *testing.T) {
+	m := lawcase.Model{
+		3: {Rubrum: "C", Art: "Verteidiger", Beginn: "2021-06-01", Ende: "noch anhängig", Stand: "laufend"},
+		1: {Rubrum: "A", Art: "Verteidiger", Beginn: "2019-01-01", Ende: "2019-12-31", Stand: "abgeschlossen"},
+		2: {Rubrum: "B", Art: "Nebenkläger", Beginn: "2020-03-01", Ende: "01.03.2021", Stand: "Abgeschlossen, rechtskräftig"},
+		4: {Rubrum: "D", Art: "Verteidiger", Beginn: "irgendwann", Stand: "laufend"},
+	}
+	date := func(s string) time.Time {
+		d, err := time.Parse(lawcase.DateLayout, s)
+		if err != nil {
+			t.Fatalf("parsing date: %v", err)
+		}
+		return d
+	}
+
+	for _, tc := range []struct {
+		name     string
+		filter   lawcase.Filter
+		expected []int
+	}{
+		{"no filter", lawcase.Filter{}, []int{1, 2, 3, 4}},
+		{"art", lawcase.Filter{Art: "Verteidiger"}, []int{1, 3, 4}},
+		{"stand", lawcase.Filter{Stand: "abgeschlossen"}, []int{1, 2}},
+		{"from", lawcase.Filter{From: date("2020-01-01")}, []int{2, 3}},
+		{"to", lawcase.Filter{To: date("2020-12-31")}, []int{1, 2}},
+		{"range", lawcase.Filter{From: date("2021-03-01"), To: date("2021-05-31")}, []int{2}},
+		{"art and stand", lawcase.Filter{Art: "Verteidiger", Stand: "laufend"}, []int{3, 4}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := m.IDs(tc.filter)
+			if fmt.Sprint(got) != fmt.Sprint(tc.expected) {
+				t.Fatalf("wrong ids: expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
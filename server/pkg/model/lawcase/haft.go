@@ -0,0 +1,19 @@
+package lawcase
+
+// Haft is the custody of the client in a case. Dates are given in DateLayout.
+type Haft struct {
+	Festnahme  string `json:"Festnahme" validate:"required,datetime=2006-01-02"`
+	Anstalt    string `json:"Anstalt"`
+	Haftbefehl string `json:"Haftbefehl" validate:"oneof=Untersuchungshaft Hauptverhandlungshaft Sicherungshaft Unterbringung Auslieferungshaft"`
+
+	// Urteil is the date of a judgment sentencing the client to a prison
+	// term. It ends the reviews under § 121 StPO.
+	Urteil string `json:"Urteil,omitempty" validate:"omitempty,datetime=2006-01-02"`
+
+	Entlassung string `json:"Entlassung,omitempty" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// InHaft reports whether the client is in custody in this case.
+func (c Case) InHaft() bool {
+	return c.Haft != nil && c.Haft.Entlassung == ""
+}
@@ -0,0 +1,161 @@
+package lawcase
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Verbindung references another case and the date of the joinder or the
+// severance (§§ 2–4 StPO).
+type Verbindung struct {
+	CaseID int    `json:"CaseID"`
+	Datum  string `json:"Datum"`
+}
+
+// Merge is the event data for joining the case From to the case ID. Both cases
+// are kept with their histories. The case numbers of the joined case are
+// added to the history of the case ID.
+type Merge struct {
+	ID    int    `json:"ID" validate:"required"`
+	From  int    `json:"From" validate:"required"`
+	Datum string `json:"Datum" validate:"required"`
+}
+
+// Split is the event data for severing a part of the case ID. The new case
+// gets NewID and the given fields. If the new case has no case numbers, it
+// gets the history of case numbers of the case ID.
+type Split struct {
+	ID     int    `json:"ID" validate:"required"`
+	NewID  int    `json:"NewID"`
+	Datum  string `json:"Datum" validate:"required"`
+	Fields Case   `json:"Fields"`
+}
+
+// LoadMerge applies a Merge event.
+func (cs *Model) LoadMerge(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var mg Merge
+	if err := json.Unmarshal(msg, &mg); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	return cs.applyMerge(mg)
+}
+
+// MergeCases joins the case mg.From to the case mg.ID. A case can only be
+// joined once and a joined case can not take other cases.
+func (cs *Model) MergeCases(mg Merge, w io.Writer) error {
+	if err := cs.checkMerge(mg); err != nil {
+		return err
+	}
+	b, err := json.Marshal(mg)
+	if err != nil {
+		return fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("writing event data: %w", err)
+	}
+	return cs.applyMerge(mg)
+}
+
+func (cs Model) checkMerge(mg Merge) error {
+	target, ok := cs[mg.ID]
+	if !ok {
+		return fmt.Errorf("case %d does not exist", mg.ID)
+	}
+	from, ok := cs[mg.From]
+	if !ok {
+		return fmt.Errorf("case %d does not exist", mg.From)
+	}
+	if mg.ID == mg.From {
+		return fmt.Errorf("case %d can not be joined to itself", mg.ID)
+	}
+	if from.VerbundenIn != nil {
+		return fmt.Errorf("case %d is already joined to case %d", mg.From, from.VerbundenIn.CaseID)
+	}
+	if target.VerbundenIn != nil {
+		return fmt.Errorf("case %d is joined to case %d", mg.ID, target.VerbundenIn.CaseID)
+	}
+	return nil
+}
+
+func (cs *Model) applyMerge(mg Merge) error {
+	if err := cs.checkMerge(mg); err != nil {
+		return err
+	}
+	target := (*cs)[mg.ID]
+	from := (*cs)[mg.From]
+
+	from.VerbundenIn = &Verbindung{CaseID: mg.ID, Datum: mg.Datum}
+	target.Verbunden = append(append([]Verbindung{}, target.Verbunden...), Verbindung{CaseID: mg.From, Datum: mg.Datum})
+	l := append([]Aktenzeichen{}, target.Aktenzeichen...)
+	for _, a := range from.Aktenzeichen {
+		if a.Bis == "" {
+			a.Bis = mg.Datum
+		}
+		l = append(l, a)
+	}
+	target.Aktenzeichen = l
+
+	(*cs)[mg.From] = from
+	(*cs)[mg.ID] = target
+	return nil
+}
+
+// LoadSplit applies a Split event.
+func (cs *Model) LoadSplit(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var sp Split
+	if err := json.Unmarshal(msg, &sp); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	if sp.NewID < 1 {
+		return fmt.Errorf("message contains invalid id %d", sp.NewID)
+	}
+	return cs.applySplit(sp)
+}
+
+// SplitCase severs a new case from the case sp.ID and returns the id of the
+// new case.
+func (cs *Model) SplitCase(sp Split, w io.Writer) (int, error) {
+	if _, ok := (*cs)[sp.ID]; !ok {
+		return 0, fmt.Errorf("case %d does not exist", sp.ID)
+	}
+	sp.NewID = cs.maxCaseID() + 1
+	b, err := json.Marshal(sp)
+	if err != nil {
+		return 0, fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return 0, fmt.Errorf("writing event data: %w", err)
+	}
+	if err := cs.applySplit(sp); err != nil {
+		return 0, err
+	}
+	return sp.NewID, nil
+}
+
+func (cs *Model) applySplit(sp Split) error {
+	source, ok := (*cs)[sp.ID]
+	if !ok {
+		return fmt.Errorf("case %d does not exist", sp.ID)
+	}
+	if _, ok := (*cs)[sp.NewID]; ok {
+		return fmt.Errorf("case %d already exists", sp.NewID)
+	}
+
+	c := sp.Fields
+	c.AbgetrenntVon = &Verbindung{CaseID: sp.ID, Datum: sp.Datum}
+	if len(c.Aktenzeichen) == 0 {
+		c.Aktenzeichen = append([]Aktenzeichen{}, source.Aktenzeichen...)
+	}
+	source.Abgetrennt = append(append([]Verbindung{}, source.Abgetrennt...), Verbindung{CaseID: sp.NewID, Datum: sp.Datum})
+
+	(*cs)[sp.ID] = source
+	(*cs)[sp.NewID] = c
+	return nil
+}
@@ -0,0 +1,88 @@
+package lawcase_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+func TestMergeCases(t *testing.T) {
+	cs := lawcase.Model{
+		1: {Rubrum: "A", Aktenzeichen: []lawcase.Aktenzeichen{{Behoerde: "Staatsanwaltschaft", Nummer: "123 Js 4567/25", Von: "2025-01-02"}}},
+		2: {Rubrum: "B", Aktenzeichen: []lawcase.Aktenzeichen{{Behoerde: "Staatsanwaltschaft", Nummer: "123 Js 8910/25", Von: "2025-02-03"}}},
+		3: {Rubrum: "C"},
+	}
+	buf := bytes.NewBuffer(nil)
+
+	if err := cs.MergeCases(lawcase.Merge{ID: 1, From: 2, Datum: "2025-04-01"}, buf); err != nil {
+		t.Fatalf("merging cases: %v", err)
+	}
+	expectedMsg := `{"ID":1,"From":2,"Datum":"2025-04-01"}`
+	if buf.String() != expectedMsg {
+		t.Fatalf("wrong message, expected %q, got %q", expectedMsg, buf.String())
+	}
+	if got := cs[2].VerbundenIn; got == nil || got.CaseID != 1 {
+		t.Fatalf("wrong back-reference of case 2: %v", got)
+	}
+	if got := cs[1].Verbunden; len(got) != 1 || got[0].CaseID != 2 {
+		t.Fatalf("wrong joined cases of case 1: %v", got)
+	}
+	a := cs[1].Aktenzeichen
+	if len(a) != 2 || a[1].Nummer != "123 Js 8910/25" || a[1].Bis != "2025-04-01" {
+		t.Fatalf("wrong case numbers of case 1: %v", a)
+	}
+	if len(cs[2].Aktenzeichen) != 1 || cs[2].Aktenzeichen[0].Bis != "" {
+		t.Fatalf("history of case 2 changed: %v", cs[2].Aktenzeichen)
+	}
+
+	for _, tc := range []struct {
+		merge    lawcase.Merge
+		expected string
+	}{
+		{lawcase.Merge{ID: 3, From: 2, Datum: "2025-05-01"}, "case 2 is already joined to case 1"},
+		{lawcase.Merge{ID: 2, From: 3, Datum: "2025-05-01"}, "case 2 is joined to case 1"},
+		{lawcase.Merge{ID: 3, From: 3, Datum: "2025-05-01"}, "case 3 can not be joined to itself"},
+		{lawcase.Merge{ID: 3, From: 42, Datum: "2025-05-01"}, "case 42 does not exist"},
+	} {
+		err := cs.MergeCases(tc.merge, buf)
+		if err == nil || err.Error() != tc.expected {
+			t.Fatalf("expected error %q, got %v", tc.expected, err)
+		}
+	}
+}
+
+func TestSplitCase(t *testing.T) {
+	cs := lawcase.Model{
+		1: {Rubrum: "A, B", Aktenzeichen: []lawcase.Aktenzeichen{{Behoerde: "Staatsanwaltschaft", Nummer: "123 Js 4567/25", Von: "2025-01-02"}}},
+	}
+	buf := bytes.NewBuffer(nil)
+
+	id, err := cs.SplitCase(lawcase.Split{ID: 1, Datum: "2025-06-01", Fields: lawcase.Case{Rubrum: "B"}}, buf)
+	if err != nil {
+		t.Fatalf("splitting case: %v", err)
+	}
+	if id != 2 {
+		t.Fatalf("wrong id: expected 2, got %d", id)
+	}
+	if got := cs[2].AbgetrenntVon; got == nil || got.CaseID != 1 || got.Datum != "2025-06-01" {
+		t.Fatalf("wrong back-reference of case 2: %v", got)
+	}
+	if got := cs[1].Abgetrennt; len(got) != 1 || got[0].CaseID != 2 {
+		t.Fatalf("wrong severed cases of case 1: %v", got)
+	}
+	if len(cs[2].Aktenzeichen) != 1 {
+		t.Fatalf("case numbers not taken over: %v", cs[2].Aktenzeichen)
+	}
+
+	t.Run("load event", func(t *testing.T) {
+		loaded := lawcase.Model{1: {Rubrum: "A, B"}}
+		if err := loaded.LoadSplit(json.RawMessage(buf.Bytes())); err != nil {
+			t.Fatalf("loading event: %v", err)
+		}
+		if loaded[2].Rubrum != "B" || loaded[2].AbgetrenntVon == nil {
+			t.Fatalf("wrong new case: %v", loaded[2])
+		}
+	})
+}
@@ -7,6 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/aktenzeichen"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/offence"
 )
 
 type Model map[int]Case
@@ -15,12 +19,139 @@ type Case struct {
 	Rubrum       string `json:"Rubrum" validate:"required"`
 	Az           string `json:"Az"`
 	Gericht      string `json:"Gericht"`
+	GerichtID    int    `json:"GerichtID,omitempty"`
 	Beginn       string `json:"Beginn" validate:"required"`
 	Ende         string `json:"Ende"`
 	Gegenstand   string `json:"Gegenstand"`
-	Art          string `json:"Art" validate:"oneof=Verteidiger Nebenkläger Zeugenbeistand Adhäsionskläger"`
+	Art          string `json:"Art" validate:"rolle"`
 	Beschreibung string `json:"Beschreibung"`
 	Stand        string `json:"Stand" validate:"required"`
+
+	// Art is the main role in the case, Rollen are further roles, e.g.
+	// Adhäsionskläger besides Nebenkläger. See Rollen for the catalog.
+	Rollen []string `json:"Rollen,omitempty" validate:"dive,rolle"`
+
+	Aktenzeichen []Aktenzeichen `json:"Aktenzeichen,omitempty" validate:"dive"`
+
+	Beteiligte []Beteiligter `json:"Beteiligte,omitempty" validate:"dive"`
+
+	// Tatvorwuerfe are the offences charged. Gegenstand is the free-text
+	// description.
+	Tatvorwuerfe []Tatvorwurf `json:"Tatvorwuerfe,omitempty" validate:"dive"`
+
+	// Tatende is the date the offence was completed (§ 78a StGB). Together
+	// with Strafdrohung or Tatvorwuerfe and Unterbrechungen it determines the
+	// limitation of prosecution, see package limitation.
+	Tatende         string          `json:"Tatende,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	Strafdrohung    *Strafdrohung   `json:"Strafdrohung,omitempty"`
+	Unterbrechungen []Unterbrechung `json:"Unterbrechungen,omitempty" validate:"dive"`
+
+	// Haft is set if the client is or was in custody in this case.
+	Haft *Haft `json:"Haft,omitempty"`
+
+	// Hauptverhandlungstage are the dates (see DateLayout) of the days of the
+	// main hearing in this instance.
+	Hauptverhandlungstage []string `json:"Hauptverhandlungstage,omitempty" validate:"dive,datetime=2006-01-02"`
+
+	// Abschluss is the outcome of the case, see abschluss.go. A case is closed
+	// if it is set, independent of the free-text field Stand.
+	Abschluss *Abschluss `json:"Abschluss,omitempty"`
+
+	// Verbunden are the cases joined to this case, VerbundenIn is the case
+	// this case was joined to. Abgetrennt are the cases severed from this
+	// case, AbgetrenntVon is the case this case was severed from. See join.go.
+	Verbunden     []Verbindung `json:"Verbunden,omitempty"`
+	VerbundenIn   *Verbindung  `json:"VerbundenIn,omitempty"`
+	Abgetrennt    []Verbindung `json:"Abgetrennt,omitempty"`
+	AbgetrenntVon *Verbindung  `json:"AbgetrenntVon,omitempty"`
+}
+
+// TextFields returns all text of the case by field name, e.g. for the
+// full-text search. The field Aktenzeichen contains all current and historical
+// case numbers of the case and all case numbers found in Az and Gericht. Valid
+// case numbers are given in normalized and in compact form, so that "123 Js
+// 4567/25" and "123Js4567/25" are found.
+func (c Case) TextFields() map[string]string {
+	var numbers []string
+	for _, a := range c.Aktenzeichen {
+		if _, err := aktenzeichen.Parse(a.Nummer); err != nil {
+			numbers = append(numbers, a.Nummer)
+		}
+	}
+	for _, a := range c.CaseNumbers() {
+		numbers = append(numbers, a.String(), strings.ReplaceAll(a.String(), " ", ""))
+	}
+	var tatvorwuerfe []string
+	for _, t := range c.Tatvorwuerfe {
+		if n, ok := offence.Lookup(t.Norm); ok {
+			tatvorwuerfe = append(tatvorwuerfe, n.String())
+		} else {
+			tatvorwuerfe = append(tatvorwuerfe, t.Norm)
+		}
+	}
+	return map[string]string{
+		"Rubrum":       c.Rubrum,
+		"Az":           c.Az,
+		"Aktenzeichen": strings.Join(numbers, " "),
+		"Gericht":      c.Gericht,
+		"Gegenstand":   c.Gegenstand,
+		"Tatvorwuerfe": strings.Join(tatvorwuerfe, " "),
+		"Art":          strings.Join(c.Roles(), " "),
+		"Beschreibung": c.Beschreibung,
+		"Stand":        c.Stand,
+	}
+}
+
+// Roles returns all roles in the case, the main role first.
+func (c Case) Roles() []string {
+	var roles []string
+	for _, r := range append([]string{c.Art}, c.Rollen...) {
+		if r == "" || contains(roles, r) {
+			continue
+		}
+		roles = append(roles, r)
+	}
+	return roles
+}
+
+// HasRole reports whether the case has the given role.
+func (c Case) HasRole(role string) bool {
+	return contains(c.Roles(), role)
+}
+
+func contains(l []string, s string) bool {
+	for _, v := range l {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// CaseNumbers returns all valid case numbers of prosecutor's offices and
+// courts of the case, i. e. the current and historical ones and those found in
+// the fields Az and Gericht.
+func (c Case) CaseNumbers() []aktenzeichen.Aktenzeichen {
+	var result []aktenzeichen.Aktenzeichen
+	for _, a := range c.Aktenzeichen {
+		if p, err := aktenzeichen.Parse(a.Nummer); err == nil {
+			result = append(result, p)
+		}
+	}
+	result = append(result, aktenzeichen.Find(c.Az)...)
+	return append(result, aktenzeichen.Find(c.Gericht)...)
+}
+
+// Warnings returns hints about questionable values of the case that do not
+// prevent saving it.
+func (c Case) Warnings() []string {
+	var warnings []string
+	if c.Az != "" {
+		if _, err := aktenzeichen.Parse(c.Az); err != nil {
+			warnings = append(warnings, fmt.Sprintf("Az: %v", err))
+		}
+	}
+	return warnings
 }
 
 type decodedMsg struct {
@@ -60,6 +191,27 @@ func (cs *Model) AddCase(c Case, w io.Writer) (int, error) {
 	return newID, nil
 }
 
+// UpdateCase replaces the case with the given id. The event is the same as
+// for new cases.
+func (cs *Model) UpdateCase(id int, c Case, w io.Writer) error {
+	if _, ok := (*cs)[id]; !ok {
+		return fmt.Errorf("case %d does not exist", id)
+	}
+	d := decodedMsg{
+		ID:     id,
+		Fields: c,
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("writing event data: %w", err)
+	}
+	(*cs)[id] = c
+	return nil
+}
+
 func (cs Model) maxCaseID() int {
 	var result int
 	for result = range cs {
@@ -131,3 +131,97 @@ func TestAddCase(t *testing.T) {
 
 	})
 }
+
+func TestUpdateCase(t *testing.T) {
+	m := lawcase.Model{1: {Rubrum: "old"}}
+	buf := bytes.NewBuffer(nil)
+
+	if err := m.UpdateCase(1, lawcase.Case{Rubrum: "new", GerichtID: 3}, buf); err != nil {
+		t.Fatalf("updating case: %v", err)
+	}
+	expectedMsg := `{"ID":1,"Fields":{"Rubrum":"new","Az":"","Gericht":"","GerichtID":3,"Beginn":"","Ende":"","Gegenstand":"","Art":"","Beschreibung":"","Stand":""}}`
+	if buf.String() != expectedMsg {
+		t.Fatalf("wrong message, expected %q, got %q", expectedMsg, buf.String())
+	}
+	if m[1].Rubrum != "new" {
+		t.Fatalf("wrong content of model")
+	}
+
+	err := m.UpdateCase(2, lawcase.Case{}, buf)
+	expectedErrMsg := "case 2 does not exist"
+	if err == nil || err.Error() != expectedErrMsg {
+		t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	for az, expected := range map[string]string{
+		"":                 "[]",
+		"123 Js 4567/25":   "[]",
+		"000234/2022 M.M.": `[Az: unrecognized case number "000234/2022 M.M."]`,
+	} {
+		got := fmt.Sprint(lawcase.Case{Az: az}.Warnings())
+		if got != expected {
+			t.Fatalf("wrong warnings for %q: expected %s, got %s", az, expected, got)
+		}
+	}
+}
+
+func TestTextFields(t *testing.T) {
+	c := lawcase.Case{Az: "123  Js 4567/2025", Gericht: "AG Leipzig 5 Ls 12/26"}
+	expected := "123 Js 4567/25 123Js4567/25 5 Ls 12/26 5Ls12/26"
+	got := c.TextFields()["Aktenzeichen"]
+	if got != expected {
+		t.Fatalf("wrong field Aktenzeichen: expected %q, got %q", expected, got)
+	}
+}
+
+func TestRoles(t *testing.T) {
+	c := lawcase.Case{Art: "Nebenkläger", Rollen: []string{"Adhäsionskläger", "Nebenkläger"}}
+	expected := "[Nebenkläger Adhäsionskläger]"
+	if got := fmt.Sprint(c.Roles()); got != expected {
+		t.Fatalf("wrong roles: expected %s, got %s", expected, got)
+	}
+	if !c.HasRole("Adhäsionskläger") || c.HasRole("Verteidiger") {
+		t.Fatalf("wrong result of HasRole for %v", c.Roles())
+	}
+
+	v := lawcase.NewValidator()
+	for _, tc := range []struct {
+		c     lawcase.Case
+		valid bool
+	}{
+		{lawcase.Case{Rubrum: "A", Beginn: "2025-01-01", Stand: "laufend", Art: "Verletztenbeistand"}, true},
+		{lawcase.Case{Rubrum: "A", Beginn: "2025-01-01", Stand: "laufend", Art: "Verteidiger", Rollen: []string{"Vernehmungsbeistand"}}, true},
+		{lawcase.Case{Rubrum: "A", Beginn: "2025-01-01", Stand: "laufend", Art: "Verteidiger", Rollen: []string{"Sachverständiger"}}, false},
+	} {
+		err := v.Struct(tc.c)
+		if (err == nil) != tc.valid {
+			t.Fatalf("wrong validation result for %v: %v", tc.c, err)
+		}
+	}
+}
+
+func TestHoechststrafe(t *testing.T) {
+	for _, tc := range []struct {
+		c        lawcase.Case
+		expected string
+	}{
+		{lawcase.Case{}, "{0 false false 0 }  false"},
+		{lawcase.Case{Strafdrohung: &lawcase.Strafdrohung{Monate: 36}, Tatvorwuerfe: []lawcase.Tatvorwurf{{Norm: "§ 211 StGB"}}}, "{36 false false 0 }  true"},
+		{lawcase.Case{Tatvorwuerfe: []lawcase.Tatvorwurf{{Norm: "§ 263 Abs. 3 StGB"}, {Norm: "§ 267 Abs. 1 StGB"}}}, "{60 false false 0 } Betrug (§ 263 Abs. 1 StGB) true"},
+		{lawcase.Case{Tatvorwuerfe: []lawcase.Tatvorwurf{{Norm: "§ 223 Abs. 1 StGB"}, {Norm: "§ 224 Abs. 1 StGB"}}}, "{120 false false 0 } Gefährliche Körperverletzung (§ 224 Abs. 1 StGB) true"},
+		{lawcase.Case{Tatvorwuerfe: []lawcase.Tatvorwurf{{Norm: "§ 224 Abs. 1 StGB"}, {Norm: "§ 370 Abs. 3 AO"}}}, "{120 false false 15 § 376 Abs. 1 AO} Steuerhinterziehung, besonders schwerer Fall (§ 370 Abs. 3 AO) true"},
+	} {
+		s, source, ok := tc.c.Hoechststrafe()
+		if got := fmt.Sprint(s, " ", source, " ", ok); got != tc.expected {
+			t.Fatalf("wrong maximum penalty: expected %q, got %q", tc.expected, got)
+		}
+	}
+
+	v := lawcase.NewValidator()
+	c := lawcase.Case{Rubrum: "A", Beginn: "2025-01-01", Stand: "laufend", Art: "Verteidiger", Tatvorwuerfe: []lawcase.Tatvorwurf{{Norm: "§ 263 StGB"}}}
+	if err := v.Struct(c); err == nil {
+		t.Fatalf("expected validation error for unknown offence")
+	}
+}
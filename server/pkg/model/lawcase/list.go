@@ -0,0 +1,196 @@
+package lawcase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/aktenzeichen"
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// ListOptions select, sort and paginate the cases returned by List.
+type ListOptions struct {
+	Filter Filter
+
+	// Sort is the name of the field used for sorting. Default is ID. Cases
+	// with equal values are always sorted by ID.
+	Sort string
+	Desc bool
+
+	// Cursor is the NextCursor of the previous page. Empty means first page.
+	Cursor string
+
+	// Limit is the maximum number of cases per page. Zero means no limit.
+	Limit int
+}
+
+// ListResult is one page of cases.
+type ListResult struct {
+	Total      int     `json:"Total"`
+	Cases      []Entry `json:"Cases"`
+	NextCursor string  `json:"NextCursor"`
+}
+
+// Entry is a case together with its id.
+type Entry struct {
+	ID int `json:"ID"`
+	Case
+}
+
+// cursor marks the last case of a page by its sort value and id.
+type cursor struct {
+	Value string `json:"V"`
+	ID    int    `json:"ID"`
+}
+
+// List returns the cases selected by the filter, sorted by the given field
+// and paginated. Strings are compared using German collation, so "Ärger"
+// comes right after "Anton" and not after "Zander".
+func (cs Model) List(o ListOptions) (ListResult, error) {
+	field := o.Sort
+	if field == "" {
+		field = "ID"
+	}
+	if !isSortField(field) {
+		return ListResult{}, fmt.Errorf("unknown sort field %q", field)
+	}
+	if o.Limit < 0 {
+		return ListResult{}, fmt.Errorf("limit must not be negative")
+	}
+
+	col := collate.New(language.German)
+	after := func(v1 string, id1 int, v2 string, id2 int) bool {
+		c := compareValues(col, field, v1, v2)
+		if o.Desc {
+			c = -c
+		}
+		if c != 0 {
+			return c > 0
+		}
+		return id1 > id2
+	}
+
+	ids := cs.IDs(o.Filter)
+	values := make(map[int]string, len(ids))
+	for _, id := range ids {
+		values[id] = sortValue(id, cs[id], field)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return after(values[ids[j]], ids[j], values[ids[i]], ids[i])
+	})
+
+	start := 0
+	if o.Cursor != "" {
+		cur, err := decodeCursor(o.Cursor)
+		if err != nil {
+			return ListResult{}, err
+		}
+		start = sort.Search(len(ids), func(i int) bool {
+			return after(values[ids[i]], ids[i], cur.Value, cur.ID)
+		})
+	}
+	end := len(ids)
+	if o.Limit > 0 && start+o.Limit < end {
+		end = start + o.Limit
+	}
+
+	result := ListResult{
+		Total: len(ids),
+		Cases: []Entry{},
+	}
+	for _, id := range ids[start:end] {
+		result.Cases = append(result.Cases, Entry{ID: id, Case: cs[id]})
+	}
+	if end < len(ids) {
+		last := ids[end-1]
+		result.NextCursor = encodeCursor(cursor{Value: values[last], ID: last})
+	}
+	return result, nil
+}
+
+func isSortField(field string) bool {
+	if field == "ID" {
+		return true
+	}
+	f, ok := reflect.TypeOf(Case{}).FieldByName(field)
+	return ok && f.Type.Kind() == reflect.String
+}
+
+func sortValue(id int, c Case, field string) string {
+	if field == "ID" {
+		return strconv.Itoa(id)
+	}
+	return reflect.ValueOf(c).FieldByName(field).String()
+}
+
+// compareValues returns -1, 0 or 1. IDs are compared as numbers. In the date
+// fields Beginn and Ende, dates (even in German format) are compared
+// chronologically and come before all other values. Valid case numbers in the
+// field Az are compared by year, register sign and number and come before all
+// other values. All other values are compared using the collator.
+func compareValues(col *collate.Collator, field, a, b string) int {
+	switch field {
+	case "ID":
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return compareInts(x, y)
+	case "Az":
+		aa, errA := aktenzeichen.Parse(a)
+		ab, errB := aktenzeichen.Parse(b)
+		switch {
+		case errA == nil && errB == nil:
+			return aktenzeichen.Compare(aa, ab)
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		}
+		return col.CompareString(a, b)
+	case "Beginn", "Ende":
+	default:
+		return col.CompareString(a, b)
+	}
+	da, errA := ParseDate(a)
+	db, errB := ParseDate(b)
+	switch {
+	case errA == nil && errB == nil:
+		return compareInts(int(da.Unix()), int(db.Unix()))
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	}
+	return col.CompareString(a, b)
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func encodeCursor(c cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cursor{}, fmt.Errorf("invalid cursor %q", s)
+	}
+	var c cursor
+	if err := json.Unmarshal(b, &c); err != nil {
+		return cursor{}, fmt.Errorf("invalid cursor %q", s)
+	}
+	return c, nil
+}
@@ -0,0 +1,84 @@
+package lawcase_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+func TestList(t *testing.T) {
+	m := lawcase.Model{
+		1: {Rubrum: "Zander", Beginn: "2021-01-01", Gericht: "AG Leipzig", Art: "Verteidiger", Az: "000234/2022"},
+		2: {Rubrum: "Ärger", Beginn: "2019-05-01", Gericht: "LG Leipzig", Art: "Verteidiger", Az: "100 Js 12/19"},
+		3: {Rubrum: "anton", Beginn: "02.03.2020", Gericht: "AG Dresden", Art: "Nebenkläger", Az: "2 Ls 3/20"},
+		4: {Rubrum: "Becker", Beginn: "2020-03-02", Gericht: "AG Leipzig", Art: "Verteidiger", Az: "100 Js 1/20"},
+	}
+	ids := func(r lawcase.ListResult) string {
+		var l []int
+		for _, e := range r.Cases {
+			l = append(l, e.ID)
+		}
+		return fmt.Sprint(l)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		opts     lawcase.ListOptions
+		expected string
+	}{
+		{"default", lawcase.ListOptions{}, "[1 2 3 4]"},
+		{"German collation", lawcase.ListOptions{Sort: "Rubrum"}, "[3 2 4 1]"},
+		{"descending", lawcase.ListOptions{Sort: "Rubrum", Desc: true}, "[1 4 2 3]"},
+		{"dates with equal values sorted by ID", lawcase.ListOptions{Sort: "Beginn"}, "[2 3 4 1]"},
+		{"case numbers", lawcase.ListOptions{Sort: "Az"}, "[2 4 3 1]"},
+		{"filter by Gericht", lawcase.ListOptions{Sort: "Rubrum", Filter: lawcase.Filter{Gericht: "ag leipzig"}}, "[4 1]"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := m.List(tc.opts)
+			if err != nil {
+				t.Fatalf("listing cases: %v", err)
+			}
+			if ids(got) != tc.expected {
+				t.Fatalf("wrong order: expected %s, got %s", tc.expected, ids(got))
+			}
+		})
+	}
+
+	t.Run("pagination", func(t *testing.T) {
+		opts := lawcase.ListOptions{Sort: "Rubrum", Desc: true, Limit: 3}
+		var pages []string
+		for {
+			got, err := m.List(opts)
+			if err != nil {
+				t.Fatalf("listing cases: %v", err)
+			}
+			if got.Total != 4 {
+				t.Fatalf("wrong total: expected 4, got %d", got.Total)
+			}
+			pages = append(pages, ids(got))
+			if got.NextCursor == "" {
+				break
+			}
+			opts.Cursor = got.NextCursor
+		}
+		expected := "[[1 4 2] [3]]"
+		if fmt.Sprint(pages) != expected {
+			t.Fatalf("wrong pages: expected %s, got %s", expected, fmt.Sprint(pages))
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := m.List(lawcase.ListOptions{Sort: "Unknown"})
+		expectedErrMsg := `unknown sort field "Unknown"`
+		if err == nil || err.Error() != expectedErrMsg {
+			t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+		}
+
+		_, err = m.List(lawcase.ListOptions{Cursor: "!!!"})
+		expectedErrMsg = `invalid cursor "!!!"`
+		if err == nil || err.Error() != expectedErrMsg {
+			t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+		}
+	})
+}
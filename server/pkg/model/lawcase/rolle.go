@@ -0,0 +1,50 @@
+package lawcase
+
+import (
+	"github.com/go-playground/validator/v10"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/offence"
+)
+
+// Rolle is a role of the lawyer in a case.
+type Rolle struct {
+	Name         string `json:"Name"`
+	Beschreibung string `json:"Beschreibung"`
+}
+
+// Rollen is the catalog of all allowed roles. This is the only place where
+// roles are defined. Add new roles at the end.
+var Rollen = []Rolle{
+	{"Verteidiger", "Verteidigung des Beschuldigten (§§ 137 ff. StPO)"},
+	{"Nebenkläger", "Vertretung des Nebenklägers (§§ 395 ff. StPO)"},
+	{"Zeugenbeistand", "Beistand eines Zeugen (§ 68b StPO)"},
+	{"Adhäsionskläger", "Vertretung im Adhäsionsverfahren (§§ 403 ff. StPO)"},
+	{"Vernehmungsbeistand", "Beistand bei einer Vernehmung (§ 68b Abs. 2 StPO)"},
+	{"Verletztenbeistand", "Beistand des Verletzten (§ 406f StPO)"},
+}
+
+// IsRolle reports whether the name is in the catalog of roles.
+func IsRolle(name string) bool {
+	for _, r := range Rollen {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// NewValidator returns a validator that knows the tag rolle which checks a
+// value against the catalog of roles and the tag norm which checks a value
+// against the catalog of offences. Use it for all structs that contain a
+// case.
+func NewValidator() *validator.Validate {
+	v := validator.New()
+	// The errors can only occur if the tags are invalid.
+	_ = v.RegisterValidation("rolle", func(fl validator.FieldLevel) bool {
+		return IsRolle(fl.Field().String())
+	})
+	_ = v.RegisterValidation("norm", func(fl validator.FieldLevel) bool {
+		_, ok := offence.Lookup(fl.Field().String())
+		return ok
+	})
+	return v
+}
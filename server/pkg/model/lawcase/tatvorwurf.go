@@ -0,0 +1,61 @@
+package lawcase
+
+import (
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/offence"
+)
+
+// Tatvorwurf is an offence charged in the case. Norm is the ID of the offence
+// in the catalog of package offence, e.g. "§ 263 Abs. 1 StGB".
+type Tatvorwurf struct {
+	Norm    string `json:"Norm" validate:"required,norm"`
+	Versuch bool   `json:"Versuch,omitempty"`
+	Faelle  int    `json:"Faelle,omitempty" validate:"min=0"`
+	Notiz   string `json:"Notiz,omitempty"`
+}
+
+// Hoechststrafe returns the maximum statutory penalty that determines the
+// limitation period. An explicit Strafdrohung wins, else it is the penalty of
+// the offence charged with the longest limitation period. The second value is this offence or empty
+// for an explicit Strafdrohung. It returns false if the penalty is unknown.
+func (c Case) Hoechststrafe() (Strafdrohung, string, bool) {
+	if c.Strafdrohung != nil {
+		return *c.Strafdrohung, "", true
+	}
+
+	var result Strafdrohung
+	var source string
+	found := false
+	for _, t := range c.Tatvorwuerfe {
+		n, ok := offence.Lookup(t.Norm)
+		if !ok {
+			continue
+		}
+		b := offence.ForLimitation(n)
+		s := Strafdrohung{
+			Monate:            b.Hoechststrafe,
+			Lebenslang:        b.Lebenslang,
+			Unverjaehrbar:     b.Unverjaehrbar,
+			Verjaehrungsfrist: b.Verjaehrungsfrist,
+			Verjaehrungsnorm:  b.Verjaehrungsnorm,
+		}
+		if !found || moreSevere(s, result) {
+			result, source, found = s, b.String(), true
+		}
+	}
+	return result, source, found
+}
+
+func moreSevere(a, b Strafdrohung) bool {
+	if a.Unverjaehrbar != b.Unverjaehrbar {
+		return a.Unverjaehrbar
+	}
+	if ya, _ := a.Frist(); ya != 0 {
+		if yb, _ := b.Frist(); ya != yb {
+			return ya > yb
+		}
+	}
+	if a.Lebenslang != b.Lebenslang {
+		return a.Lebenslang
+	}
+	return a.Monate > b.Monate
+}
@@ -0,0 +1,67 @@
+package lawcase
+
+// Strafdrohung is the maximum statutory penalty of the offence charged. It
+// determines the limitation period (§ 78 Abs. 3 StGB).
+type Strafdrohung struct {
+	// Monate is the maximum term of imprisonment in months. Offences
+	// punishable by a fine only have 0.
+	Monate     int  `json:"Monate,omitempty" validate:"min=0"`
+	Lebenslang bool `json:"Lebenslang,omitempty"`
+
+	// Unverjaehrbar is set for Mord and the crimes of §§ 6 to 13 VStGB
+	// (§ 78 Abs. 2 StGB).
+	Unverjaehrbar bool `json:"Unverjaehrbar,omitempty"`
+
+	// Verjaehrungsfrist is a special limitation period in years that
+	// replaces the period of § 78 Abs. 3 StGB, e.g. 15 years under § 376
+	// Abs. 1 AO. Verjaehrungsnorm is the norm that provides it.
+	Verjaehrungsfrist int    `json:"Verjaehrungsfrist,omitempty" validate:"min=0"`
+	Verjaehrungsnorm  string `json:"Verjaehrungsnorm,omitempty"`
+}
+
+// Frist returns the limitation period in years and the number of § 78 Abs. 3
+// StGB. The number is 0 for a special limitation period. It returns 0 years
+// for offences that are not subject to limitation.
+func (s Strafdrohung) Frist() (years int, nr int) {
+	switch {
+	case s.Unverjaehrbar:
+		return 0, 0
+	case s.Verjaehrungsfrist > 0:
+		return s.Verjaehrungsfrist, 0
+	case s.Lebenslang:
+		return 30, 1
+	case s.Monate > 10*12:
+		return 20, 2
+	case s.Monate > 5*12:
+		return 10, 3
+	case s.Monate > 12:
+		return 5, 4
+	default:
+		return 3, 5
+	}
+}
+
+// Unterbrechungen is the catalog of acts that interrupt the limitation
+// period by their number in § 78c Abs. 1 Satz 1 StGB.
+var Unterbrechungen = map[int]string{
+	1:  "erste Vernehmung des Beschuldigten oder Bekanntgabe des Ermittlungsverfahrens",
+	2:  "richterliche Vernehmung des Beschuldigten",
+	3:  "Beauftragung eines Sachverständigen",
+	4:  "richterliche Beschlagnahme- oder Durchsuchungsanordnung",
+	5:  "Haftbefehl, Unterbringungsbefehl, Vorführungsbefehl",
+	6:  "Erhebung der öffentlichen Klage",
+	7:  "Eröffnung des Hauptverfahrens",
+	8:  "Anberaumung einer Hauptverhandlung",
+	9:  "Strafbefehl oder andere urteilsersetzende Entscheidung",
+	10: "vorläufige gerichtliche Einstellung wegen Abwesenheit",
+	11: "vorläufige gerichtliche Einstellung wegen Verhandlungsunfähigkeit",
+	12: "richterliches Ersuchen um eine Untersuchungshandlung im Ausland",
+}
+
+// Unterbrechung is an act that interrupts the limitation period. Nr is the
+// number in § 78c Abs. 1 Satz 1 StGB, see Unterbrechungen.
+type Unterbrechung struct {
+	Datum string `json:"Datum" validate:"required,datetime=2006-01-02"`
+	Nr    int    `json:"Nr" validate:"min=1,max=12"`
+	Notiz string `json:"Notiz,omitempty"`
+}
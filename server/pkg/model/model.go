@@ -8,7 +8,14 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/appointment"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/court"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/deadline"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/invoice"
 	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/person"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/proceeding"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/search"
 )
 
 type Eventstore interface {
@@ -17,8 +24,18 @@ type Eventstore interface {
 }
 
 type Model struct {
-	eventstore Eventstore
-	Case       lawcase.Model
+	eventstore  Eventstore
+	Case        lawcase.Model
+	Court       court.Model
+	Proceeding  proceeding.Model
+	Person      person.Model
+	Deadline    deadline.Model
+	Appointment appointment.Model
+	Feed        appointment.Feeds
+	Buffer      appointment.Buffers
+	Invoice     invoice.Model
+	Absender    invoice.Absender
+	Search      *search.Index
 }
 
 type decodedEvent struct {
@@ -28,8 +45,17 @@ type decodedEvent struct {
 
 func New(es Eventstore) (*Model, error) {
 	m := Model{
-		eventstore: es,
-		Case:       lawcase.Model{},
+		eventstore:  es,
+		Case:        lawcase.Model{},
+		Court:       court.Model{},
+		Proceeding:  proceeding.Model{},
+		Person:      person.Model{},
+		Deadline:    deadline.Model{},
+		Appointment: appointment.Model{},
+		Feed:        appointment.Feeds{},
+		Buffer:      appointment.Buffers{},
+		Invoice:     invoice.Model{},
+		Search:      search.New(),
 	}
 
 	events, err := es.Retrieve()
@@ -48,6 +74,62 @@ func New(es Eventstore) (*Model, error) {
 			if err := m.Case.Load(d.Data); err != nil {
 				return nil, fmt.Errorf("loading case: %w", err)
 			}
+		case "CaseAz":
+			if err := m.Case.LoadAz(d.Data); err != nil {
+				return nil, fmt.Errorf("loading case number change: %w", err)
+			}
+		case "CaseMerge":
+			if err := m.Case.LoadMerge(d.Data); err != nil {
+				return nil, fmt.Errorf("loading case joinder: %w", err)
+			}
+		case "CaseClose":
+			if err := m.Case.LoadAbschluss(d.Data); err != nil {
+				return nil, fmt.Errorf("loading case outcome: %w", err)
+			}
+		case "CaseSplit":
+			if err := m.Case.LoadSplit(d.Data); err != nil {
+				return nil, fmt.Errorf("loading case severance: %w", err)
+			}
+		case "Court":
+			if err := m.Court.Load(d.Data); err != nil {
+				return nil, fmt.Errorf("loading court: %w", err)
+			}
+		case "Proceeding":
+			if err := m.Proceeding.Load(d.Data); err != nil {
+				return nil, fmt.Errorf("loading proceeding: %w", err)
+			}
+		case "Person":
+			if err := m.Person.Load(d.Data); err != nil {
+				return nil, fmt.Errorf("loading person: %w", err)
+			}
+		case "Deadline":
+			if err := m.Deadline.Load(d.Data); err != nil {
+				return nil, fmt.Errorf("loading deadline: %w", err)
+			}
+		case "DeadlineDone":
+			if err := m.Deadline.LoadDone(d.Data); err != nil {
+				return nil, fmt.Errorf("loading done deadline: %w", err)
+			}
+		case "Appointment":
+			if err := m.Appointment.Load(d.Data); err != nil {
+				return nil, fmt.Errorf("loading appointment: %w", err)
+			}
+		case "CalendarFeed":
+			if err := m.Feed.Load(d.Data); err != nil {
+				return nil, fmt.Errorf("loading calendar feed: %w", err)
+			}
+		case "AppointmentBuffer":
+			if err := m.Buffer.Load(d.Data); err != nil {
+				return nil, fmt.Errorf("loading appointment buffer: %w", err)
+			}
+		case "Invoice":
+			if err := m.Invoice.Load(d.Data); err != nil {
+				return nil, fmt.Errorf("loading invoice: %w", err)
+			}
+		case "InvoiceSender":
+			if err := m.Absender.Load(d.Data); err != nil {
+				return nil, fmt.Errorf("loading invoice sender: %w", err)
+			}
 		case "Theme":
 			return nil, fmt.Errorf("not implemented")
 		default:
@@ -55,9 +137,82 @@ func New(es Eventstore) (*Model, error) {
 		}
 	}
 
+	for id := range m.Case {
+		m.reindexCase(id)
+	}
+
 	return &m, nil
 }
 
+// AddCase adds a new case and updates the search index.
+func (m *Model) AddCase(c lawcase.Case) (int, error) {
+	id, err := m.Case.AddCase(c, m.WriteEvent("Case"))
+	if err != nil {
+		return 0, err
+	}
+	m.reindexCase(id)
+	return id, nil
+}
+
+// UpdateCase replaces an existing case and updates the search index.
+func (m *Model) UpdateCase(id int, c lawcase.Case) error {
+	if err := m.Case.UpdateCase(id, c, m.WriteEvent("Case")); err != nil {
+		return err
+	}
+	m.reindexCase(id)
+	return nil
+}
+
+// ChangeAz adds a new case number to a case and updates the search index.
+func (m *Model) ChangeAz(ch lawcase.AzChange) error {
+	if err := m.Case.ChangeAz(ch, m.WriteEvent("CaseAz")); err != nil {
+		return err
+	}
+	m.reindexCase(ch.ID)
+	return nil
+}
+
+// MergeCases joins two cases and updates the search index.
+func (m *Model) MergeCases(mg lawcase.Merge) error {
+	if err := m.Case.MergeCases(mg, m.WriteEvent("CaseMerge")); err != nil {
+		return err
+	}
+	m.reindexCase(mg.ID)
+	m.reindexCase(mg.From)
+	return nil
+}
+
+// SplitCase severs a new case from an existing one and updates the search
+// index.
+func (m *Model) SplitCase(sp lawcase.Split) (int, error) {
+	id, err := m.Case.SplitCase(sp, m.WriteEvent("CaseSplit"))
+	if err != nil {
+		return 0, err
+	}
+	m.reindexCase(sp.ID)
+	m.reindexCase(id)
+	return id, nil
+}
+
+// CloseCase records the outcome of a case and updates the search index.
+func (m *Model) CloseCase(a lawcase.Abschluss) error {
+	if err := m.Case.CloseCase(a, m.WriteEvent("CaseClose")); err != nil {
+		return err
+	}
+	m.reindexCase(a.ID)
+	return nil
+}
+
+// reindexCase updates the search index for the case with the given id.
+func (m *Model) reindexCase(id int) {
+	c, ok := m.Case[id]
+	if !ok {
+		m.Search.Remove(id)
+		return
+	}
+	m.Search.Set(id, c.TextFields())
+}
+
 func (m *Model) WriteEvent(name string) io.Writer {
 	return WriteEventer{
 		Name:        name,
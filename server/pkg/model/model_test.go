@@ -64,3 +64,64 @@ func TestModel(t *testing.T) {
 		}
 	})
 }
+
+func TestSearchIndex(t *testing.T) {
+	logger := log.Default()
+	es, _, cleanup := testutils.CreateEventstore(t, logger)
+	defer cleanup()
+
+	msg := `{"Name": "Case", "Data": {"ID": 1, "Fields": {"Rubrum": "Müller wegen Betrugs"}}}`
+	if _, err := es.Write(json.RawMessage(msg)); err != nil {
+		t.Fatalf("writing first case: %v", err)
+	}
+
+	m, err := model.New(es)
+	if err != nil {
+		t.Fatalf("creating model: %v", err)
+	}
+
+	t.Run("index built from loaded events", func(t *testing.T) {
+		res := m.Search.Search("betrug", 0)
+		if len(res) != 1 || res[0].ID != 1 {
+			t.Fatalf("wrong search result: expected case 1, got %v", res)
+		}
+	})
+
+	t.Run("index updated by AddCase", func(t *testing.T) {
+		id, err := m.AddCase(lawcase.Case{Rubrum: "Schulze", Gegenstand: "Betrug"})
+		if err != nil {
+			t.Fatalf("adding case: %v", err)
+		}
+		res := m.Search.Search("schulze betrug", 0)
+		if len(res) != 1 || res[0].ID != id {
+			t.Fatalf("wrong search result: expected case %d, got %v", id, res)
+		}
+	})
+}
+
+func TestCaseAzEvent(t *testing.T) {
+	logger := log.Default()
+	es, _, cleanup := testutils.CreateEventstore(t, logger)
+	defer cleanup()
+
+	for _, msg := range []string{
+		`{"Name": "Case", "Data": {"ID": 1, "Fields": {"Rubrum": "A"}}}`,
+		`{"Name": "CaseAz", "Data": {"ID": 1, "Behoerde": "Staatsanwaltschaft", "Nummer": "123 Js 4567/25", "Datum": "2025-02-01"}}`,
+	} {
+		if _, err := es.Write(json.RawMessage(msg)); err != nil {
+			t.Fatalf("writing event: %v", err)
+		}
+	}
+
+	m, err := model.New(es)
+	if err != nil {
+		t.Fatalf("creating model: %v", err)
+	}
+	if len(m.Case[1].Aktenzeichen) != 1 {
+		t.Fatalf("wrong case numbers: %v", m.Case[1].Aktenzeichen)
+	}
+	res := m.Search.Search("4567/25", 0)
+	if len(res) != 1 || res[0].ID != 1 {
+		t.Fatalf("wrong search result: expected case 1, got %v", res)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Package person is about persons involved in cases, e. g. our clients,
+co-defendants, victims and witnesses. Persons are linked to cases via the
+field Beteiligte of the case.
+*/
+package person
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type Model map[int]Person
+
+type Person struct {
+	Name         string `json:"Name" validate:"required"`
+	Vorname      string `json:"Vorname"`
+	Geburtsdatum string `json:"Geburtsdatum" validate:"omitempty,datetime=2006-01-02"`
+	Anschrift    string `json:"Anschrift"`
+	Notiz        string `json:"Notiz"`
+}
+
+// FullName returns first name and name of the person.
+func (p Person) FullName() string {
+	return strings.TrimSpace(p.Vorname + " " + p.Name)
+}
+
+type decodedMsg struct {
+	ID     int    `json:"ID"`
+	Fields Person `json:"Fields"`
+}
+
+func (ps *Model) Load(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var d decodedMsg
+	if err := json.Unmarshal(msg, &d); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	if d.ID < 1 {
+		return fmt.Errorf("message contains invalid id %d", d.ID)
+	}
+	(*ps)[d.ID] = d.Fields
+	return nil
+}
+
+func (ps *Model) AddPerson(p Person, w io.Writer) (int, error) {
+	newID := ps.maxPersonID() + 1
+	d := decodedMsg{
+		ID:     newID,
+		Fields: p,
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		return 0, fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return 0, fmt.Errorf("writing event data: %w", err)
+	}
+	(*ps)[newID] = p
+	return newID, nil
+}
+
+func (ps Model) maxPersonID() int {
+	var result int
+	for n := range ps {
+		if n > result {
+			result = n
+		}
+	}
+	return result
+}
+
+func (ps Model) Retrieve(id int) (Person, error) {
+	p, ok := ps[id]
+	if !ok {
+		return Person{}, fmt.Errorf("person %d does not exist", id)
+	}
+	return p, nil
+}
+
+// Find returns the ids of all persons with the given name, first name and
+// date of birth (case insensitive). Empty values match everything.
+func (ps Model) Find(name, vorname, geburtsdatum string) []int {
+	var ids []int
+	for id, p := range ps {
+		if !strings.EqualFold(p.Name, strings.TrimSpace(name)) {
+			continue
+		}
+		if vorname != "" && !strings.EqualFold(p.Vorname, strings.TrimSpace(vorname)) {
+			continue
+		}
+		if geburtsdatum != "" && p.Geburtsdatum != "" && p.Geburtsdatum != geburtsdatum {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,68 @@
+package person_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/person"
+)
+
+func TestLoad(t *testing.T) {
+	m := person.Model{}
+	msg := json.RawMessage(`{"ID": 4, "Fields": {"Name": "Müller", "Vorname": "Anna"}}`)
+	if err := m.Load(msg); err != nil {
+		t.Fatalf("loading message: %v", err)
+	}
+	if m[4].FullName() != "Anna Müller" {
+		t.Fatalf("wrong name: expected %q, got %q", "Anna Müller", m[4].FullName())
+	}
+
+	err := m.Load(json.RawMessage(`{"Fields": {}}`))
+	expectedErrMsg := "message contains invalid id 0"
+	if err == nil || err.Error() != expectedErrMsg {
+		t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+	}
+}
+
+func TestAddPerson(t *testing.T) {
+	m := person.Model{}
+	buf := bytes.NewBuffer(nil)
+
+	id, err := m.AddPerson(person.Person{Name: "Müller", Vorname: "Anna", Geburtsdatum: "1980-02-03"}, buf)
+	if err != nil {
+		t.Fatalf("adding person: %v", err)
+	}
+	if id != 1 {
+		t.Fatalf("wrong id: expected 1, got %d", id)
+	}
+	expectedMsg := `{"ID":1,"Fields":{"Name":"Müller","Vorname":"Anna","Geburtsdatum":"1980-02-03","Anschrift":"","Notiz":""}}`
+	if buf.String() != expectedMsg {
+		t.Fatalf("wrong message, expected %q, got %q", expectedMsg, buf.String())
+	}
+	if _, err := m.AddPerson(person.Person{Name: "Müller", Vorname: "Bernd"}, buf); err != nil {
+		t.Fatalf("adding person: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name, vorname, geburtsdatum string
+		expected                    string
+	}{
+		{"müller", "", "", "[1 2]"},
+		{"Müller", "anna", "", "[1]"},
+		{"Müller", "Anna", "1981-01-01", "[]"},
+		{"Schulze", "", "", "[]"},
+	} {
+		got := fmt.Sprint(m.Find(tc.name, tc.vorname, tc.geburtsdatum))
+		if got != tc.expected {
+			t.Fatalf("wrong result for %q %q %q: expected %s, got %s", tc.name, tc.vorname, tc.geburtsdatum, tc.expected, got)
+		}
+	}
+
+	_, err = m.Retrieve(42)
+	expectedErrMsg := "person 42 does not exist"
+	if err == nil || err.Error() != expectedErrMsg {
+		t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+	}
+}
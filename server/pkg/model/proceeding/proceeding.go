@@ -0,0 +1,162 @@
+/*
+Package proceeding is about proceedings, i. e. one matter that runs through
+several instances like Amtsgericht, Berufung before the Landgericht and
+Revision before the Oberlandesgericht. Every instance is a case.
+*/
+package proceeding
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+type Model map[int]Proceeding
+
+type Proceeding struct {
+	Bezeichnung string `json:"Bezeichnung" validate:"required"`
+
+	// Instanzen are in procedural order, the first instance comes first.
+	Instanzen []Instanz `json:"Instanzen" validate:"dive"`
+}
+
+// Instanz is one case of the proceeding.
+type Instanz struct {
+	CaseID   int    `json:"CaseID" validate:"required"`
+	Stufe    string `json:"Stufe" validate:"oneof=Ermittlungsverfahren Erstinstanz Berufung Revision Beschwerde Wiederaufnahme"`
+	Ergebnis string `json:"Ergebnis"`
+}
+
+type decodedMsg struct {
+	ID     int        `json:"ID"`
+	Fields Proceeding `json:"Fields"`
+}
+
+func (ps *Model) Load(msg json.RawMessage) error {
+	if msg == nil {
+		return fmt.Errorf("message must not be nil")
+	}
+	var d decodedMsg
+	if err := json.Unmarshal(msg, &d); err != nil {
+		return fmt.Errorf("unmarshalling JSON: %v", err)
+	}
+	if d.ID < 1 {
+		return fmt.Errorf("message contains invalid id %d", d.ID)
+	}
+	(*ps)[d.ID] = d.Fields
+	return nil
+}
+
+func (ps *Model) AddProceeding(p Proceeding, w io.Writer) (int, error) {
+	if err := ps.checkCases(0, p); err != nil {
+		return 0, err
+	}
+	newID := ps.maxProceedingID() + 1
+	if err := ps.write(newID, p, w); err != nil {
+		return 0, err
+	}
+	return newID, nil
+}
+
+// UpdateProceeding replaces the proceeding with the given id. The event is
+// the same as for new proceedings.
+func (ps *Model) UpdateProceeding(id int, p Proceeding, w io.Writer) error {
+	if _, ok := (*ps)[id]; !ok {
+		return fmt.Errorf("proceeding %d does not exist", id)
+	}
+	if err := ps.checkCases(id, p); err != nil {
+		return err
+	}
+	return ps.write(id, p, w)
+}
+
+// Link adds a case as instance to the proceeding. The position is the zero
+// based index in the list of instances. A negative position or a position
+// behind the last instance appends the case.
+func (ps *Model) Link(id int, position int, i Instanz, w io.Writer) error {
+	p, ok := (*ps)[id]
+	if !ok {
+		return fmt.Errorf("proceeding %d does not exist", id)
+	}
+	if position < 0 || position > len(p.Instanzen) {
+		position = len(p.Instanzen)
+	}
+	l := make([]Instanz, 0, len(p.Instanzen)+1)
+	l = append(l, p.Instanzen[:position]...)
+	l = append(l, i)
+	p.Instanzen = append(l, p.Instanzen[position:]...)
+	return ps.UpdateProceeding(id, p, w)
+}
+
+func (ps *Model) write(id int, p Proceeding, w io.Writer) error {
+	d := decodedMsg{
+		ID:     id,
+		Fields: p,
+	}
+	b, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("marshalling JSON event data: %w", err)
+	}
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("writing event data: %w", err)
+	}
+	(*ps)[id] = p
+	return nil
+}
+
+// checkCases returns an error if a case is used twice in the proceeding or
+// in another proceeding than the one with the given id.
+func (ps Model) checkCases(id int, p Proceeding) error {
+	seen := make(map[int]bool)
+	for _, i := range p.Instanzen {
+		if seen[i.CaseID] {
+			return fmt.Errorf("case %d is used twice", i.CaseID)
+		}
+		seen[i.CaseID] = true
+		if other, ok := ps.OfCase(i.CaseID); ok && other != id {
+			return fmt.Errorf("case %d already belongs to proceeding %d", i.CaseID, other)
+		}
+	}
+	return nil
+}
+
+// OfCase returns the id of the proceeding the case belongs to.
+func (ps Model) OfCase(caseID int) (int, bool) {
+	for id, p := range ps {
+		for _, i := range p.Instanzen {
+			if i.CaseID == caseID {
+				return id, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func (ps Model) maxProceedingID() int {
+	var result int
+	for n := range ps {
+		if n > result {
+			result = n
+		}
+	}
+	return result
+}
+
+func (ps Model) Retrieve(id int) (Proceeding, error) {
+	p, ok := ps[id]
+	if !ok {
+		return Proceeding{}, fmt.Errorf("proceeding %d does not exist", id)
+	}
+	return p, nil
+}
+
+// IDs returns the ids of all proceedings in ascending order.
+func (ps Model) IDs() []int {
+	ids := make([]int, 0, len(ps))
+	for id := range ps {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,90 @@
+package proceeding_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/proceeding"
+)
+
+func TestLoad(t *testing.T) {
+	m := proceeding.Model{}
+	msg := json.RawMessage(`{"ID": 2, "Fields": {"Bezeichnung": "Müller", "Instanzen": [{"CaseID": 5, "Stufe": "Erstinstanz"}]}}`)
+	if err := m.Load(msg); err != nil {
+		t.Fatalf("loading message: %v", err)
+	}
+	if m[2].Instanzen[0].CaseID != 5 {
+		t.Fatalf("wrong case id: expected 5, got %d", m[2].Instanzen[0].CaseID)
+	}
+
+	err := m.Load(json.RawMessage(`{"Fields": {}}`))
+	expectedErrMsg := "message contains invalid id 0"
+	if err == nil || err.Error() != expectedErrMsg {
+		t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+	}
+}
+
+func TestAddProceedingAndLink(t *testing.T) {
+	m := proceeding.Model{}
+	buf := bytes.NewBuffer(nil)
+	p := proceeding.Proceeding{
+		Bezeichnung: "Müller wegen Betrugs",
+		Instanzen:   []proceeding.Instanz{{CaseID: 1, Stufe: "Erstinstanz", Ergebnis: "Verurteilung"}},
+	}
+
+	id, err := m.AddProceeding(p, buf)
+	if err != nil {
+		t.Fatalf("adding proceeding: %v", err)
+	}
+	if id != 1 {
+		t.Fatalf("wrong id: expected 1, got %d", id)
+	}
+	expectedMsg := `{"ID":1,"Fields":{"Bezeichnung":"Müller wegen Betrugs","Instanzen":[{"CaseID":1,"Stufe":"Erstinstanz","Ergebnis":"Verurteilung"}]}}`
+	if buf.String() != expectedMsg {
+		t.Fatalf("wrong message, expected %q, got %q", expectedMsg, buf.String())
+	}
+
+	t.Run("link appends and inserts", func(t *testing.T) {
+		if err := m.Link(1, -1, proceeding.Instanz{CaseID: 3, Stufe: "Revision"}, buf); err != nil {
+			t.Fatalf("linking case: %v", err)
+		}
+		if err := m.Link(1, 1, proceeding.Instanz{CaseID: 2, Stufe: "Berufung"}, buf); err != nil {
+			t.Fatalf("linking case: %v", err)
+		}
+		var got []int
+		for _, i := range m[1].Instanzen {
+			got = append(got, i.CaseID)
+		}
+		if len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+			t.Fatalf("wrong order of instances: expected [1 2 3], got %v", got)
+		}
+		if pid, ok := m.OfCase(2); !ok || pid != 1 {
+			t.Fatalf("wrong proceeding of case 2: expected 1, got %d (%v)", pid, ok)
+		}
+	})
+
+	t.Run("case in another proceeding", func(t *testing.T) {
+		_, err := m.AddProceeding(proceeding.Proceeding{Bezeichnung: "X", Instanzen: []proceeding.Instanz{{CaseID: 2}}}, buf)
+		expectedErrMsg := "case 2 already belongs to proceeding 1"
+		if err == nil || err.Error() != expectedErrMsg {
+			t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+		}
+	})
+
+	t.Run("case linked twice", func(t *testing.T) {
+		err := m.Link(1, -1, proceeding.Instanz{CaseID: 3}, buf)
+		expectedErrMsg := "case 3 is used twice"
+		if err == nil || err.Error() != expectedErrMsg {
+			t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+		}
+	})
+
+	t.Run("unknown proceeding", func(t *testing.T) {
+		err := m.Link(42, -1, proceeding.Instanz{CaseID: 7}, buf)
+		expectedErrMsg := "proceeding 42 does not exist"
+		if err == nil || err.Error() != expectedErrMsg {
+			t.Fatalf("expected error %q, got %v", expectedErrMsg, err)
+		}
+	})
+}
@@ -0,0 +1,213 @@
+/*
+Package offence provides the catalog of criminal offences (StGB, BtMG, StVG,
+AO and others) with their statutory penalties. The catalog is the embedded
+file catalog.csv. Add new offences there.
+*/
+package offence
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed catalog.csv
+var catalogData []byte
+
+// DefaultMaximum is the maximum term of imprisonment in months if the law
+// only gives a minimum (§ 38 Abs. 2 StGB).
+const DefaultMaximum = 15 * 12
+
+// Norm is one offence of the catalog. Penalties are terms of imprisonment in
+// months.
+type Norm struct {
+	ID          string `json:"ID"`
+	Gesetz      string `json:"Gesetz"`
+	Paragraph   string `json:"Paragraph"`
+	Absatz      string `json:"Absatz,omitempty"`
+	Bezeichnung string `json:"Bezeichnung"`
+
+	Mindeststrafe int  `json:"Mindeststrafe"`
+	Hoechststrafe int  `json:"Hoechststrafe"`
+	Lebenslang    bool `json:"Lebenslang,omitempty"`
+
+	// Geldstrafe is set if the offence may be punished by a fine instead of
+	// imprisonment.
+	Geldstrafe bool `json:"Geldstrafe,omitempty"`
+
+	// Grundtatbestand is the ID of the basic offence if the norm is only a
+	// rule for especially serious cases (besonders schwerer Fall). Such rules
+	// do not change the limitation period (§ 78 Abs. 4 StGB).
+	Grundtatbestand string `json:"Grundtatbestand,omitempty"`
+
+	Unverjaehrbar bool `json:"Unverjaehrbar,omitempty"`
+
+	// Verjaehrungsfrist is a special limitation period in years that
+	// replaces the period of § 78 Abs. 3 StGB, e.g. 15 years for especially
+	// serious tax evasion. Verjaehrungsnorm is the norm that provides it.
+	Verjaehrungsfrist int    `json:"Verjaehrungsfrist,omitempty"`
+	Verjaehrungsnorm  string `json:"Verjaehrungsnorm,omitempty"`
+}
+
+// Citation returns the usual citation of a norm like "§ 263 Abs. 1 StGB".
+func Citation(gesetz, paragraph, absatz string) string {
+	if absatz == "" {
+		return fmt.Sprintf("§ %s %s", paragraph, gesetz)
+	}
+	return fmt.Sprintf("§ %s Abs. %s %s", paragraph, absatz, gesetz)
+}
+
+// String returns the description with the citation, e.g. "Betrug (§ 263
+// Abs. 1 StGB)".
+func (n Norm) String() string {
+	return fmt.Sprintf("%s (%s)", n.Bezeichnung, n.ID)
+}
+
+var catalog = mustParse(catalogData)
+
+// Catalog returns all offences sorted by law and paragraph.
+func Catalog() []Norm {
+	return append([]Norm{}, catalog...)
+}
+
+// Lookup returns the offence with the given ID.
+func Lookup(id string) (Norm, bool) {
+	for _, n := range catalog {
+		if n.ID == id {
+			return n, true
+		}
+	}
+	return Norm{}, false
+}
+
+// ForLimitation returns the norm whose penalty determines the limitation
+// period, i. e. the basic offence for rules for especially serious cases
+// unless the rule has a special limitation period of its own.
+func ForLimitation(n Norm) Norm {
+	if n.Verjaehrungsfrist > 0 {
+		return n
+	}
+	if b, ok := Lookup(n.Grundtatbestand); ok {
+		return b
+	}
+	return n
+}
+
+func mustParse(data []byte) []Norm {
+	l, err := parse(data)
+	if err != nil {
+		panic(fmt.Sprintf("parsing embedded catalog of offences: %v", err))
+	}
+	return l
+}
+
+func parse(data []byte) ([]Norm, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = ';'
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing header")
+	}
+
+	var result []Norm
+	seen := map[string]bool{}
+	for i, rec := range records[1:] {
+		line := i + 2
+		n := Norm{
+			Gesetz:           rec[0],
+			Paragraph:        rec[1],
+			Absatz:           rec[2],
+			Bezeichnung:      rec[3],
+			Geldstrafe:       rec[6] == "ja",
+			Grundtatbestand:  rec[7],
+			Unverjaehrbar:    rec[8] == "ja",
+			Verjaehrungsnorm: rec[10],
+		}
+		n.ID = Citation(n.Gesetz, n.Paragraph, n.Absatz)
+		if seen[n.ID] {
+			return nil, fmt.Errorf("line %d: %s is used twice", line, n.ID)
+		}
+		seen[n.ID] = true
+
+		if rec[4] != "lebenslang" {
+			if n.Mindeststrafe, err = strconv.Atoi(rec[4]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid Mindeststrafe %q", line, rec[4])
+			}
+		}
+		switch rec[5] {
+		case "lebenslang":
+			n.Lebenslang = true
+			n.Hoechststrafe = DefaultMaximum
+		case "":
+			n.Hoechststrafe = DefaultMaximum
+		default:
+			if n.Hoechststrafe, err = strconv.Atoi(rec[5]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid Hoechststrafe %q", line, rec[5])
+			}
+		}
+		if rec[4] == "lebenslang" {
+			n.Mindeststrafe = n.Hoechststrafe
+		}
+		if rec[9] != "" {
+			if n.Verjaehrungsfrist, err = strconv.Atoi(rec[9]); err != nil || n.Verjaehrungsfrist < 1 {
+				return nil, fmt.Errorf("line %d: invalid Verjaehrungsfrist %q", line, rec[9])
+			}
+			if n.Verjaehrungsnorm == "" {
+				return nil, fmt.Errorf("line %d: Verjaehrungsnorm is missing", line)
+			}
+		}
+		result = append(result, n)
+	}
+
+	for _, n := range result {
+		if n.Grundtatbestand != "" && !seen[n.Grundtatbestand] {
+			return nil, fmt.Errorf("%s: unknown Grundtatbestand %q", n.ID, n.Grundtatbestand)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Gesetz != b.Gesetz {
+			return lawOrder(a.Gesetz) < lawOrder(b.Gesetz)
+		}
+		if a.Paragraph != b.Paragraph {
+			return paragraphLess(a.Paragraph, b.Paragraph)
+		}
+		return a.Absatz < b.Absatz
+	})
+	return result, nil
+}
+
+// lawOrder puts the StGB first and all other laws in alphabetical order.
+func lawOrder(gesetz string) string {
+	if gesetz == "StGB" {
+		return ""
+	}
+	return gesetz
+}
+
+// paragraphLess compares paragraphs like "263" and "263a" by number first.
+func paragraphLess(a, b string) bool {
+	na, sa := splitParagraph(a)
+	nb, sb := splitParagraph(b)
+	if na != nb {
+		return na < nb
+	}
+	return sa < sb
+}
+
+func splitParagraph(p string) (int, string) {
+	i := strings.IndexFunc(p, func(r rune) bool { return r < '0' || r > '9' })
+	if i < 0 {
+		i = len(p)
+	}
+	n, _ := strconv.Atoi(p[:i])
+	return n, p[i:]
+}
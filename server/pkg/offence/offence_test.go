@@ -0,0 +1,64 @@
+package offence_test
+
+import (
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/offence"
+)
+
+func TestCatalog(t *testing.T) {
+	l := offence.Catalog()
+	if len(l) == 0 {
+		t.Fatalf("empty catalog")
+	}
+	if l[0].ID != "§ 113 Abs. 1 StGB" {
+		t.Fatalf("wrong first offence: expected %q, got %q", "§ 113 Abs. 1 StGB", l[0].ID)
+	}
+
+	t.Run("lookup", func(t *testing.T) {
+		n, ok := offence.Lookup("§ 263 Abs. 1 StGB")
+		if !ok {
+			t.Fatalf("offence %q not found", "§ 263 Abs. 1 StGB")
+		}
+		if got := n.String(); got != "Betrug (§ 263 Abs. 1 StGB)" {
+			t.Fatalf("wrong offence: expected %q, got %q", "Betrug (§ 263 Abs. 1 StGB)", got)
+		}
+		if n.Hoechststrafe != 60 || !n.Geldstrafe {
+			t.Fatalf("wrong penalty: %+v", n)
+		}
+		if _, ok := offence.Lookup("§ 999 StGB"); ok {
+			t.Fatalf("found unknown offence")
+		}
+	})
+
+	t.Run("penalties", func(t *testing.T) {
+		for id, expected := range map[string]int{
+			"§ 212 Abs. 1 StGB": offence.DefaultMaximum,
+			"§ 244 Abs. 1 StGB": 120,
+			"§ 29a Abs. 1 BtMG": offence.DefaultMaximum,
+		} {
+			n, _ := offence.Lookup(id)
+			if n.Hoechststrafe != expected {
+				t.Fatalf("wrong maximum penalty of %s: expected %d, got %d", id, expected, n.Hoechststrafe)
+			}
+		}
+		if n, _ := offence.Lookup("§ 211 StGB"); !n.Lebenslang || !n.Unverjaehrbar {
+			t.Fatalf("wrong penalty of Mord: %+v", n)
+		}
+	})
+
+	t.Run("basic offence for limitation", func(t *testing.T) {
+		n, _ := offence.Lookup("§ 263 Abs. 3 StGB")
+		if got := offence.ForLimitation(n).ID; got != "§ 263 Abs. 1 StGB" {
+			t.Fatalf("wrong basic offence: expected %q, got %q", "§ 263 Abs. 1 StGB", got)
+		}
+		n, _ = offence.Lookup("§ 244 Abs. 1 StGB")
+		if got := offence.ForLimitation(n).ID; got != n.ID {
+			t.Fatalf("wrong basic offence: expected %q, got %q", n.ID, got)
+		}
+		n, _ = offence.Lookup("§ 370 Abs. 3 AO")
+		if got := offence.ForLimitation(n); got.ID != n.ID || got.Verjaehrungsfrist != 15 {
+			t.Fatalf("wrong norm for special limitation period: expected %q with 15 years, got %+v", n.ID, got)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+/*
+Package pdf writes simple PDF documents with text and lines on A4 pages. It
+only uses the standard fonts Helvetica and Helvetica-Bold, which every PDF
+viewer provides, so no fonts are embedded. Text is encoded with
+WinAnsiEncoding (Windows-1252), which covers German umlauts, ß, § and €.
+
+Coordinates are in points (1/72 inch) from the lower left corner of the page.
+*/
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Width and Height are the size of an A4 page in points.
+const (
+	Width  = 595.28
+	Height = 841.89
+)
+
+// Document is a PDF document.
+type Document struct {
+	pages []*Page
+}
+
+// Page is one page of a document.
+type Page struct {
+	content bytes.Buffer
+}
+
+// AddPage adds a new empty page to the document.
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Text writes the text with its baseline starting at the given position.
+func (p *Page) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n", font, num(size), num(x), num(y), escape(encode(text)))
+}
+
+// TextRight writes the text so that it ends at the given position.
+func (p *Page) TextRight(x, y, size float64, bold bool, text string) {
+	p.Text(x-TextWidth(text, size), y, size, bold, text)
+}
+
+// Line draws a thin line between the two positions.
+func (p *Page) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %s %s m %s %s l S\n", num(x1), num(y1), num(x2), num(y2))
+}
+
+// TextWidth returns the width of the text in Helvetica of the given size.
+// Digits and the usual characters of amounts have the same width in both
+// fonts, so it can be used to align amounts at the right.
+func TextWidth(text string, size float64) float64 {
+	var units int
+	for _, r := range text {
+		w, ok := widths[r]
+		if !ok {
+			w = 556
+		}
+		units += w
+	}
+	return float64(units) * size / 1000
+}
+
+// widths contains the widths of some characters in Helvetica in 1/1000 of
+// the font size. Other characters are assumed to have the width of a digit.
+var widths = map[rune]int{
+	' ': 278, ',': 278, '.': 278, '-': 333, ':': 278, '%': 889, '/': 278,
+	'(': 333, ')': 333, '§': 556, '€': 556,
+	'i': 222, 'j': 222, 'l': 222, 'f': 278, 't': 278, 'r': 333, 'I': 278,
+	'm': 833, 'w': 722, 'M': 833, 'W': 944,
+}
+
+// WriteTo writes the document.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1 to 4 are the catalog, the page tree and the two fonts.
+	// Every page consists of the page object and its content stream.
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+2*i))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, p := range d.pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(Width), num(Height), 6+2*i,
+		))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, o := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", o)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+// encode converts the text to Windows-1252. Characters that do not exist
+// there are replaced by a question mark.
+func encode(text string) []byte {
+	result := make([]byte, 0, len(text))
+	for _, r := range text {
+		b, ok := charmap.Windows1252.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
+// escape escapes the characters with a special meaning in PDF strings.
+func escape(text []byte) string {
+	var sb strings.Builder
+	for _, b := range text {
+		switch b {
+		case '\\', '(', ')':
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(b)
+	}
+	return sb.String()
+}
+
+// num formats a number with at most two decimals.
+func num(f float64) string {
+	s := fmt.Sprintf("%.2f", f)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
@@ -0,0 +1,38 @@
+package pdf_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/pdf"
+)
+
+func TestWriteTo(t *testing.T) {
+	doc := &pdf.Document{}
+	doc.AddPage().Text(70, 700, 10, false, "Gebühr (§ 14) 5 €")
+	doc.AddPage().Line(70, 60, 500, 60)
+
+	buf := new(bytes.Buffer)
+	if _, err := doc.WriteTo(buf); err != nil {
+		t.Fatalf("writing document: %v", err)
+	}
+	got := buf.String()
+
+	if !strings.HasPrefix(got, "%PDF-1.4\n") {
+		t.Fatalf("wrong start of document: got %q", got[:10])
+	}
+	if !strings.HasSuffix(got, "%%EOF\n") {
+		t.Fatalf("wrong end of document: got %q", got[len(got)-10:])
+	}
+	for _, expected := range []string{
+		"/Kids [5 0 R 7 0 R] /Count 2",
+		"BT /F1 10 Tf 70 700 Td (Geb\xfchr \\(\xa7 14\\) 5 \x80) Tj ET",
+		"0.5 w 70 60 m 500 60 l S",
+		"xref\n0 9\n",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Fatalf("document does not contain %q", expected)
+		}
+	}
+}
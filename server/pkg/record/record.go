@@ -0,0 +1,319 @@
+/*
+Package record computes when convictions are removed from the criminal record
+(Bundeszentralregister). It covers the periods for the inclusion in a
+certificate of good conduct (Führungszeugnis, §§ 32 to 34 BZRG) and the
+erasure periods (Tilgungsfristen, §§ 45 to 47 BZRG).
+
+All periods begin on the day of the first judgment (§ 36, § 47 Abs. 1 BZRG).
+As long as several convictions are registered, none of them is erased before
+all of them are ready for erasure (§ 47 Abs. 3 BZRG) and all of them are
+included in a certificate as long as one of them is (§ 38 Abs. 1 BZRG). A
+conviction of a later judgment only prolongs the periods of earlier
+convictions if these were not ready for erasure on the day of the judgment.
+Measures of reform and prevention, the removal of the blemish of a youth
+sentence and the exceptions of § 38 Abs. 2 BZRG are not taken into account.
+*/
+package record
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/offence"
+)
+
+// DateLayout is the layout of all dates.
+const DateLayout = "2006-01-02"
+
+// Ueberliegefrist is the number of years a conviction remains in the
+// register after it is ready for erasure (§ 45 Abs. 2 BZRG). No information
+// about it is given during this time.
+const Ueberliegefrist = 1
+
+// Verurteilung is a conviction registered in the Bundeszentralregister.
+type Verurteilung struct {
+	// CaseID is the case of the conviction. It is 0 for convictions entered
+	// manually.
+	CaseID int `json:"CaseID,omitempty"`
+
+	// Datum is the day of the first judgment.
+	Datum string `json:"Datum" validate:"required,datetime=2006-01-02"`
+
+	Art         string `json:"Art" validate:"oneof=Geldstrafe Freiheitsstrafe Jugendstrafe"`
+	Monate      int    `json:"Monate" validate:"min=0"`
+	Lebenslang  bool   `json:"Lebenslang,omitempty"`
+	Tagessaetze int    `json:"Tagessaetze" validate:"min=0"`
+	Bewaehrung  bool   `json:"Bewaehrung"`
+
+	// Sexualdelikt is set for convictions for an offence under §§ 174 to
+	// 180 or 182 StGB.
+	Sexualdelikt bool `json:"Sexualdelikt"`
+}
+
+// FromCase returns the conviction recorded in the outcome of the case. It
+// returns false if the case did not end with a conviction. The day of the
+// outcome is used as the day of the first judgment.
+func FromCase(id int, c lawcase.Case) (Verurteilung, bool) {
+	a := c.Abschluss
+	if a == nil || !a.HasStrafe() || a.Strafe == nil {
+		return Verurteilung{}, false
+	}
+	v := Verurteilung{
+		CaseID:      id,
+		Datum:       a.Datum,
+		Art:         "Geldstrafe",
+		Tagessaetze: a.Strafe.Tagessaetze,
+	}
+	if a.Strafe.Freiheitsstrafe > 0 {
+		v.Art = "Freiheitsstrafe"
+		v.Monate = a.Strafe.Freiheitsstrafe
+		v.Bewaehrung = a.Strafe.Bewaehrung
+	}
+	for _, t := range c.Tatvorwuerfe {
+		if n, ok := offence.Lookup(t.Norm); ok && IsSexualdelikt(n) {
+			v.Sexualdelikt = true
+		}
+	}
+	return v, true
+}
+
+// IsSexualdelikt reports whether the offence is one of §§ 174 to 180 or 182
+// StGB that lead to longer periods.
+func IsSexualdelikt(n offence.Norm) bool {
+	if n.Gesetz != "StGB" {
+		return false
+	}
+	p, err := strconv.Atoi(strings.TrimRightFunc(n.Paragraph, func(r rune) bool {
+		return r < '0' || r > '9'
+	}))
+	if err != nil {
+		return false
+	}
+	return (p >= 174 && p <= 180) || p == 182
+}
+
+// Eintrag is a conviction with its periods.
+type Eintrag struct {
+	Verurteilung
+
+	// Tilgungsfrist is the erasure period in years and Tilgungsnorm its
+	// legal basis. Tilgungsreife is the day the conviction on its own is
+	// ready for erasure. It is empty for convictions that are never erased.
+	Tilgungsfrist int    `json:"Tilgungsfrist"`
+	Tilgungsnorm  string `json:"Tilgungsnorm"`
+	Tilgungsreife string `json:"Tilgungsreife,omitempty"`
+
+	// Fuehrungszeugnis is the last day the conviction on its own is included
+	// in a certificate of good conduct. It is empty if it is not included at
+	// all (see Aufnahme) or as long as it is registered.
+	Aufnahme         bool   `json:"Aufnahme"`
+	Fuehrungszeugnis string `json:"Fuehrungszeugnis,omitempty"`
+	Fuehrungsnorm    string `json:"Fuehrungsnorm"`
+}
+
+// Result contains the periods of all convictions and the days that count
+// for the client: the last day of the inclusion in a certificate of good
+// conduct and the day all convictions are ready for erasure and erased.
+type Result struct {
+	Eintraege []Eintrag `json:"Eintraege"`
+
+	// Tilgungsreife and Tilgung are empty if the convictions are never
+	// erased. Fuehrungszeugnis is empty if no conviction is included in a
+	// certificate.
+	Tilgungsreife    string `json:"Tilgungsreife,omitempty"`
+	Tilgung          string `json:"Tilgung,omitempty"`
+	Fuehrungszeugnis string `json:"Fuehrungszeugnis,omitempty"`
+
+	// Tilgungsreif reports whether all convictions are ready for erasure on
+	// the given day.
+	Tilgungsreif bool `json:"Tilgungsreif"`
+
+	// Begruendung explains every step of the computation.
+	Begruendung []string `json:"Begruendung"`
+}
+
+// Compute computes the periods of the convictions at the given day.
+func Compute(vs []Verurteilung, today time.Time) (Result, error) {
+	if len(vs) == 0 {
+		return Result{}, fmt.Errorf("no convictions given")
+	}
+	vs = append([]Verurteilung{}, vs...)
+	sort.SliceStable(vs, func(i, j int) bool {
+		return vs[i].Datum < vs[j].Datum
+	})
+
+	r := Result{}
+	var group []Eintrag
+	var reife time.Time
+	for _, v := range vs {
+		datum, err := time.Parse(DateLayout, v.Datum)
+		if err != nil {
+			return Result{}, fmt.Errorf("Datum: %w", err)
+		}
+		if v.Art == "Geldstrafe" && (v.Monate > 0 || v.Lebenslang) {
+			return Result{}, fmt.Errorf("conviction of %s: Geldstrafe must not have Monate", v.Datum)
+		}
+		if group != nil && !reife.IsZero() && !datum.Before(reife) {
+			r.Eintraege = append(r.Eintraege, group...)
+			r.Begruendung = append(r.Begruendung, fmt.Sprintf(
+				"%d conviction(s) up to %s were ready for erasure on %s before the judgment of %s",
+				len(group), group[len(group)-1].Datum, reife.Format(DateLayout), v.Datum,
+			))
+			group = nil
+		}
+		group = append(group, Eintrag{Verurteilung: v})
+		group, reife = periods(group)
+	}
+	r.Eintraege = append(r.Eintraege, group...)
+
+	never := false
+	for _, e := range group {
+		if e.Lebenslang {
+			never = true
+			r.Begruendung = append(r.Begruendung, fmt.Sprintf("conviction of %s to life imprisonment is never erased and always included in a certificate of good conduct (%s)", e.Datum, e.Tilgungsnorm))
+			continue
+		}
+		r.Begruendung = append(r.Begruendung, fmt.Sprintf("conviction of %s: erasure period of %d years (%s) ends on %s", e.Datum, e.Tilgungsfrist, e.Tilgungsnorm, e.Tilgungsreife))
+		if !e.Aufnahme {
+			r.Begruendung = append(r.Begruendung, fmt.Sprintf("conviction of %s is not included in a certificate of good conduct (%s)", e.Datum, e.Fuehrungsnorm))
+			continue
+		}
+		r.Begruendung = append(r.Begruendung, fmt.Sprintf("conviction of %s is included in a certificate of good conduct until %s (%s)", e.Datum, e.Fuehrungszeugnis, e.Fuehrungsnorm))
+		if e.Fuehrungszeugnis > r.Fuehrungszeugnis {
+			r.Fuehrungszeugnis = e.Fuehrungszeugnis
+		}
+	}
+	if never {
+		r.Fuehrungszeugnis = ""
+		return r, nil
+	}
+	if len(group) > 1 {
+		r.Begruendung = append(r.Begruendung, fmt.Sprintf("%d convictions are registered: none is erased before all are ready for erasure (§ 47 Abs. 3 BZRG)", len(group)))
+		if r.Fuehrungszeugnis != "" {
+			r.Begruendung = append(r.Begruendung, fmt.Sprintf("all convictions are included in a certificate of good conduct until %s (§ 38 Abs. 1 BZRG)", r.Fuehrungszeugnis))
+		}
+	}
+	r.Tilgungsreife = reife.Format(DateLayout)
+	r.Tilgung = reife.AddDate(Ueberliegefrist, 0, 0).Format(DateLayout)
+	r.Tilgungsreif = !today.Before(reife)
+	r.Begruendung = append(r.Begruendung, fmt.Sprintf("all convictions are ready for erasure on %s and erased after the further year on %s (§ 45 Abs. 2 BZRG)", r.Tilgungsreife, r.Tilgung))
+	return r, nil
+}
+
+// periods computes the periods of the convictions registered at the same
+// time and returns the day all of them are ready for erasure. The day is
+// zero if one of them is never erased.
+func periods(group []Eintrag) ([]Eintrag, time.Time) {
+	var reife time.Time
+	never := false
+	for i, e := range group {
+		if e.Lebenslang {
+			e.Tilgungsfrist, e.Tilgungsnorm, e.Tilgungsreife = 0, "§ 45 Abs. 3 Nr. 1 BZRG", ""
+			e.Aufnahme, e.Fuehrungszeugnis, e.Fuehrungsnorm = true, "", "§ 34 Abs. 1 Nr. 3 BZRG"
+			group[i] = e
+			never = true
+			continue
+		}
+		others := append(append([]Eintrag{}, group[:i]...), group[i+1:]...)
+		datum, _ := time.Parse(DateLayout, e.Datum)
+
+		years, norm, extend := tilgungsfrist(e.Verurteilung, others)
+		end := datum.AddDate(years, 0, 0)
+		if extend {
+			end = end.AddDate(0, e.Monate, 0)
+		}
+		e.Tilgungsfrist, e.Tilgungsnorm, e.Tilgungsreife = years, norm, end.Format(DateLayout)
+		if end.After(reife) {
+			reife = end
+		}
+
+		years, norm, extend = fuehrungszeugnis(e.Verurteilung, others)
+		e.Aufnahme, e.Fuehrungsnorm, e.Fuehrungszeugnis = years > 0, norm, ""
+		if e.Aufnahme {
+			end := datum.AddDate(years, 0, -1)
+			if extend {
+				end = end.AddDate(0, e.Monate, 0)
+			}
+			e.Fuehrungszeugnis = end.Format(DateLayout)
+		}
+		group[i] = e
+	}
+	if never {
+		return group, time.Time{}
+	}
+	return group, reife
+}
+
+// tilgungsfrist returns the erasure period in years according to § 46 Abs. 1
+// BZRG and whether it is extended by the length of the sentence (§ 46 Abs. 3
+// BZRG).
+func tilgungsfrist(v Verurteilung, others []Eintrag) (int, string, bool) {
+	switch {
+	case v.Art == "Geldstrafe" && v.Tagessaetze <= 90 && !anyImprisonment(others):
+		return 5, "§ 46 Abs. 1 Nr. 1 Buchst. a BZRG", false
+	case v.Art == "Freiheitsstrafe" && v.Monate <= 3 && len(others) == 0:
+		return 5, "§ 46 Abs. 1 Nr. 1 Buchst. b BZRG", false
+	case v.Art == "Jugendstrafe" && v.Monate <= 12:
+		return 5, "§ 46 Abs. 1 Nr. 1 Buchst. c BZRG", false
+	case v.Art == "Jugendstrafe" && v.Monate <= 24 && v.Bewaehrung:
+		return 5, "§ 46 Abs. 1 Nr. 1 Buchst. d BZRG", false
+	case v.Art == "Jugendstrafe" && v.Bewaehrung:
+		return 5, "§ 46 Abs. 1 Nr. 1 Buchst. e BZRG", true
+	case v.Sexualdelikt && v.Art != "Geldstrafe" && v.Monate > 12:
+		return 20, "§ 46 Abs. 1 Nr. 3 BZRG", true
+	case v.Sexualdelikt && v.Art != "Geldstrafe":
+		return 10, "§ 46 Abs. 1 Nr. 2 Buchst. d BZRG", false
+	case v.Art == "Geldstrafe" || v.Monate <= 3:
+		return 10, "§ 46 Abs. 1 Nr. 2 Buchst. a BZRG", false
+	case v.Art == "Freiheitsstrafe" && v.Monate <= 12 && v.Bewaehrung && !anyImprisonment(others):
+		return 10, "§ 46 Abs. 1 Nr. 2 Buchst. b BZRG", false
+	case v.Art == "Jugendstrafe":
+		return 10, "§ 46 Abs. 1 Nr. 2 Buchst. c BZRG", true
+	default:
+		return 15, "§ 46 Abs. 1 Nr. 4 BZRG", true
+	}
+}
+
+// fuehrungszeugnis returns the period of the inclusion in a certificate of
+// good conduct in years according to § 34 Abs. 1 BZRG and whether it is
+// extended by the length of the sentence (§ 34 Abs. 2 BZRG). It returns 0 if
+// the conviction is not included at all (§ 32 Abs. 2 BZRG).
+func fuehrungszeugnis(v Verurteilung, others []Eintrag) (int, string, bool) {
+	if !v.Sexualdelikt {
+		switch {
+		case v.Art == "Jugendstrafe" && v.Monate <= 24 && v.Bewaehrung:
+			return 0, "§ 32 Abs. 2 Nr. 3 BZRG", false
+		case v.Art == "Geldstrafe" && v.Tagessaetze <= 90 && len(others) == 0:
+			return 0, "§ 32 Abs. 2 Nr. 5 Buchst. a BZRG", false
+		case v.Art == "Freiheitsstrafe" && v.Monate <= 3 && len(others) == 0:
+			return 0, "§ 32 Abs. 2 Nr. 5 Buchst. b BZRG", false
+		}
+	}
+	switch {
+	case v.Art == "Geldstrafe" && v.Tagessaetze <= 90 && !anyImprisonment(others):
+		return 3, "§ 34 Abs. 1 Nr. 1 Buchst. a BZRG", false
+	case v.Art == "Freiheitsstrafe" && v.Monate <= 3 && len(others) == 0:
+		return 3, "§ 34 Abs. 1 Nr. 1 Buchst. b BZRG", false
+	case v.Art == "Jugendstrafe" && v.Monate <= 12:
+		return 3, "§ 34 Abs. 1 Nr. 1 Buchst. c BZRG", false
+	case v.Art == "Jugendstrafe" && v.Monate <= 24 && v.Bewaehrung:
+		return 3, "§ 34 Abs. 1 Nr. 1 Buchst. d BZRG", false
+	case v.Sexualdelikt && v.Art != "Geldstrafe" && v.Monate > 12:
+		return 10, "§ 34 Abs. 1 Nr. 2 BZRG", true
+	default:
+		return 5, "§ 34 Abs. 1 Nr. 3 BZRG", true
+	}
+}
+
+func anyImprisonment(es []Eintrag) bool {
+	for _, e := range es {
+		if e.Art != "Geldstrafe" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,150 @@
+package record_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/offence"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/record"
+)
+
+func TestCompute(t *testing.T) {
+	today := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name     string
+		vs       []record.Verurteilung
+		expected string
+	}{
+		{
+			"small fine",
+			[]record.Verurteilung{{Datum: "2020-03-10", Art: "Geldstrafe", Tagessaetze: 60}},
+			"2025-03-10 2026-03-10  true",
+		},
+		{
+			"prison without probation",
+			[]record.Verurteilung{{Datum: "2020-01-15", Art: "Freiheitsstrafe", Monate: 18}},
+			"2036-07-15 2037-07-15 2026-07-14 false",
+		},
+		{
+			"prison on probation",
+			[]record.Verurteilung{{Datum: "2020-01-15", Art: "Freiheitsstrafe", Monate: 8, Bewaehrung: true}},
+			"2030-01-15 2031-01-15 2025-09-14 false",
+		},
+		{
+			"sexual offence",
+			[]record.Verurteilung{{Datum: "2020-01-15", Art: "Freiheitsstrafe", Monate: 24, Bewaehrung: true, Sexualdelikt: true}},
+			"2042-01-15 2043-01-15 2032-01-14 false",
+		},
+		{
+			"second conviction within the period",
+			[]record.Verurteilung{
+				{Datum: "2023-06-01", Art: "Geldstrafe", Tagessaetze: 120},
+				{Datum: "2020-03-10", Art: "Geldstrafe", Tagessaetze: 60},
+			},
+			"2033-06-01 2034-06-01 2028-05-31 false",
+		},
+		{
+			"second conviction after the period",
+			[]record.Verurteilung{
+				{Datum: "2010-01-01", Art: "Geldstrafe", Tagessaetze: 60},
+				{Datum: "2016-05-01", Art: "Geldstrafe", Tagessaetze: 50},
+			},
+			"2021-05-01 2022-05-01  true",
+		},
+		{
+			"life imprisonment",
+			[]record.Verurteilung{
+				{Datum: "2001-01-01", Art: "Freiheitsstrafe", Lebenslang: true},
+				{Datum: "2020-01-01", Art: "Geldstrafe", Tagessaetze: 50},
+			},
+			"   false",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := record.Compute(tc.vs, today)
+			if err != nil {
+				t.Fatalf("computing periods: %v", err)
+			}
+			got := fmt.Sprint(r.Tilgungsreife, " ", r.Tilgung, " ", r.Fuehrungszeugnis, " ", r.Tilgungsreif)
+			if got != tc.expected {
+				t.Fatalf("wrong result: expected %q, got %q\n%v", tc.expected, got, r.Begruendung)
+			}
+			if len(r.Eintraege) != len(tc.vs) {
+				t.Fatalf("wrong number of entries: expected %d, got %d", len(tc.vs), len(r.Eintraege))
+			}
+		})
+	}
+
+	t.Run("periods of entries", func(t *testing.T) {
+		r, err := record.Compute([]record.Verurteilung{
+			{Datum: "2020-03-10", Art: "Geldstrafe", Tagessaetze: 60},
+			{Datum: "2023-06-01", Art: "Geldstrafe", Tagessaetze: 120},
+		}, today)
+		if err != nil {
+			t.Fatalf("computing periods: %v", err)
+		}
+		for i, expected := range []string{
+			"5 § 46 Abs. 1 Nr. 1 Buchst. a BZRG 2025-03-10 2023-03-09 § 34 Abs. 1 Nr. 1 Buchst. a BZRG",
+			"10 § 46 Abs. 1 Nr. 2 Buchst. a BZRG 2033-06-01 2028-05-31 § 34 Abs. 1 Nr. 3 BZRG",
+		} {
+			e := r.Eintraege[i]
+			got := fmt.Sprint(e.Tilgungsfrist, " ", e.Tilgungsnorm, " ", e.Tilgungsreife, " ", e.Fuehrungszeugnis, " ", e.Fuehrungsnorm)
+			if got != expected {
+				t.Fatalf("wrong entry %d: expected %q, got %q", i, expected, got)
+			}
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := record.Compute(nil, today)
+		expected := "no convictions given"
+		if err == nil || err.Error() != expected {
+			t.Fatalf("expected error %q, got %v", expected, err)
+		}
+	})
+}
+
+func TestFromCase(t *testing.T) {
+	for _, tc := range []struct {
+		c        lawcase.Case
+		expected string
+	}{
+		{lawcase.Case{}, "{0   0 false 0 false false} false"},
+		{lawcase.Case{Abschluss: &lawcase.Abschluss{Datum: "2025-01-02", Ergebnis: "Einstellung153a"}}, "{0   0 false 0 false false} false"},
+		{
+			lawcase.Case{Abschluss: &lawcase.Abschluss{Datum: "2025-01-02", Ergebnis: "Strafbefehl", Strafe: &lawcase.Strafe{Tagessaetze: 40, Tagessatzhoehe: 30}}},
+			"{7 2025-01-02 Geldstrafe 0 false 40 false false} true",
+		},
+		{
+			lawcase.Case{
+				Tatvorwuerfe: []lawcase.Tatvorwurf{{Norm: "§ 263 Abs. 1 StGB"}, {Norm: "§ 177 Abs. 1 StGB"}},
+				Abschluss:    &lawcase.Abschluss{Datum: "2025-01-02", Ergebnis: "Verurteilung", Strafe: &lawcase.Strafe{Freiheitsstrafe: 10, Bewaehrung: true}},
+			},
+			"{7 2025-01-02 Freiheitsstrafe 10 false 0 true true} true",
+		},
+	} {
+		v, ok := record.FromCase(7, tc.c)
+		if got := fmt.Sprint(v, " ", ok); got != tc.expected {
+			t.Fatalf("wrong conviction: expected %q, got %q", tc.expected, got)
+		}
+	}
+}
+
+func TestIsSexualdelikt(t *testing.T) {
+	for id, expected := range map[string]bool{
+		"§ 176 Abs. 1 StGB": true,
+		"§ 177 Abs. 6 StGB": true,
+		"§ 263 Abs. 1 StGB": false,
+	} {
+		n, ok := offence.Lookup(id)
+		if !ok {
+			t.Fatalf("offence %q not found", id)
+		}
+		if got := record.IsSexualdelikt(n); got != expected {
+			t.Fatalf("wrong result for %q: expected %v, got %v", id, expected, got)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+var umlauts = strings.NewReplacer(
+	"ä", "ae",
+	"ö", "oe",
+	"ü", "ue",
+	"ß", "ss",
+	"ẞ", "ss",
+)
+
+// token is one word of a text together with its position in the text.
+type token struct {
+	term  string
+	start int
+	end   int
+}
+
+// tokenize splits the text into words and returns their normalized stems.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, newToken(text, start, i))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, newToken(text, start, len(text)))
+	}
+	return tokens
+}
+
+func newToken(text string, start, end int) token {
+	return token{term: stem(normalize(text[start:end])), start: start, end: end}
+}
+
+// normalize folds the case and replaces umlauts and ß, so that "Müller",
+// "MUELLER" and "müller" are the same.
+func normalize(word string) string {
+	return umlauts.Replace(strings.ToLower(word))
+}
+
+// stem removes common German inflection suffixes from a normalized word. It is
+// a simplified version of the CISTEM stemmer and errs on the side of removing
+// too little.
+func stem(word string) string {
+	if strings.IndexFunc(word, unicode.IsDigit) >= 0 {
+		return word
+	}
+	for {
+		switch {
+		case len(word) > 5 && hasAnySuffix(word, "em", "er", "nd"):
+			word = word[:len(word)-2]
+		case len(word) > 3 && hasAnySuffix(word, "e", "s", "n", "t"):
+			word = word[:len(word)-1]
+		default:
+			return word
+		}
+	}
+}
+
+func hasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
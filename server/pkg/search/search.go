@@ -0,0 +1,245 @@
+/*
+Package search provides an in-memory full-text index for the text fields of
+model objects. Words are normalized (case folding, umlauts, ß) and stemmed, and
+every query word matches all indexed words starting with it.
+*/
+package search
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FieldWeights rank matches in important fields higher. Fields not in the map
+// have weight 1.
+var FieldWeights = map[string]float64{
+	"Rubrum":       3,
+	"Az":           3,
+	"Gericht":      2,
+	"Gegenstand":   2,
+	"Tatvorwuerfe": 2,
+}
+
+// Exact matches of a word rank higher than prefix matches.
+const prefixFactor = 0.5
+
+// Index is the inverted index. It is safe for concurrent use.
+type Index struct {
+	mu sync.RWMutex
+
+	// postings maps a term to the documents and the weighted number of
+	// occurrences.
+	postings map[string]map[int]float64
+
+	// docs contains the original fields of every document for snippets and
+	// for removing the document from the postings.
+	docs map[int]map[string]string
+}
+
+// Result is one document found by a query.
+type Result struct {
+	ID       int       `json:"ID"`
+	Score    float64   `json:"Score"`
+	Snippets []Snippet `json:"Snippets"`
+}
+
+// Snippet is a part of a field with all matches wrapped in <mark> tags. The
+// rest of the text is HTML escaped.
+type Snippet struct {
+	Field string `json:"Field"`
+	Text  string `json:"Text"`
+}
+
+// New returns an empty index.
+func New() *Index {
+	return &Index{
+		postings: make(map[string]map[int]float64),
+		docs:     make(map[int]map[string]string),
+	}
+}
+
+// Set adds the document with the given id and text fields to the index. An
+// existing document with this id is replaced.
+func (ix *Index) Set(id int, fields map[string]string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+	ix.docs[id] = fields
+	for field, text := range fields {
+		w := fieldWeight(field)
+		for _, t := range tokenize(text) {
+			if ix.postings[t.term] == nil {
+				ix.postings[t.term] = make(map[int]float64)
+			}
+			ix.postings[t.term][id] += w
+		}
+	}
+}
+
+// Remove removes the document with the given id from the index.
+func (ix *Index) Remove(id int) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(id)
+}
+
+func (ix *Index) remove(id int) {
+	for _, text := range ix.docs[id] {
+		for _, t := range tokenize(text) {
+			delete(ix.postings[t.term], id)
+			if len(ix.postings[t.term]) == 0 {
+				delete(ix.postings, t.term)
+			}
+		}
+	}
+	delete(ix.docs, id)
+}
+
+// Search returns all documents that match every word of the query, ranked by
+// relevance. A limit of zero means no limit.
+func (ix *Index) Search(query string, limit int) []Result {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	words := tokenize(query)
+	if len(words) == 0 {
+		return []Result{}
+	}
+
+	var scores map[int]float64
+	terms := make(map[string]bool)
+	for _, w := range words {
+		s := ix.scoreWord(w.term, terms)
+		if scores == nil {
+			scores = s
+			continue
+		}
+		for id := range scores {
+			if _, ok := s[id]; !ok {
+				delete(scores, id)
+				continue
+			}
+			scores[id] += s[id]
+		}
+	}
+
+	results := []Result{}
+	for id, score := range scores {
+		results = append(results, Result{
+			ID:       id,
+			Score:    math.Round(score*1000) / 1000,
+			Snippets: ix.snippets(id, terms),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// scoreWord returns the score of all documents containing a term starting
+// with the given word. Rare terms score higher (inverse document frequency).
+// All matching terms are added to the given set.
+func (ix *Index) scoreWord(word string, matched map[string]bool) map[int]float64 {
+	scores := make(map[int]float64)
+	for term, docs := range ix.postings {
+		if !strings.HasPrefix(term, word) {
+			continue
+		}
+		matched[term] = true
+		factor := 1.0
+		if term != word {
+			factor = prefixFactor
+		}
+		idf := math.Log(1 + float64(len(ix.docs))/float64(len(docs)))
+		for id, tf := range docs {
+			scores[id] += tf * idf * factor
+		}
+	}
+	return scores
+}
+
+// snippets returns one snippet for every field of the document that contains
+// at least one of the terms. The fields are sorted by name.
+func (ix *Index) snippets(id int, terms map[string]bool) []Snippet {
+	const context = 40
+
+	snippets := []Snippet{}
+	for field, text := range ix.docs[id] {
+		var hits []token
+		for _, t := range tokenize(text) {
+			if terms[t.term] {
+				hits = append(hits, t)
+			}
+		}
+		if len(hits) == 0 {
+			continue
+		}
+
+		start := startOfWord(text, hits[0].start-context)
+		end := endOfWord(text, hits[0].end+context)
+
+		var b strings.Builder
+		if start > 0 {
+			b.WriteString("…")
+		}
+		pos := start
+		for _, h := range hits {
+			if h.end > end {
+				break
+			}
+			b.WriteString(html.EscapeString(text[pos:h.start]))
+			b.WriteString("<mark>" + html.EscapeString(text[h.start:h.end]) + "</mark>")
+			pos = h.end
+		}
+		b.WriteString(html.EscapeString(text[pos:end]))
+		if end < len(text) {
+			b.WriteString("…")
+		}
+		snippets = append(snippets, Snippet{Field: field, Text: b.String()})
+	}
+	sort.Slice(snippets, func(i, j int) bool {
+		return snippets[i].Field < snippets[j].Field
+	})
+	return snippets
+}
+
+func fieldWeight(field string) float64 {
+	if w, ok := FieldWeights[field]; ok {
+		return w
+	}
+	return 1
+}
+
+// startOfWord moves the position back to the start of a word.
+func startOfWord(text string, pos int) int {
+	if pos <= 0 {
+		return 0
+	}
+	if i := strings.LastIndexAny(text[:pos], " \n\t"); i >= 0 {
+		return i + 1
+	}
+	return 0
+}
+
+// endOfWord moves the position forward to the end of a word.
+func endOfWord(text string, pos int) int {
+	if pos >= len(text) {
+		return len(text)
+	}
+	if i := strings.IndexAny(text[pos:], " \n\t"); i >= 0 {
+		return pos + i
+	}
+	return len(text)
+}
@@ -0,0 +1,103 @@
+package search_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/search"
+)
+
+func ids(results []search.Result) string {
+	var l []int
+	for _, r := range results {
+		l = append(l, r.ID)
+	}
+	return fmt.Sprint(l)
+}
+
+func TestSearch(t *testing.T) {
+	ix := search.New()
+	ix.Set(1, map[string]string{
+		"Rubrum":       "Müller, M. wegen Betrugs",
+		"Beschreibung": "Hauptverhandlung vor dem Schöffengericht",
+	})
+	ix.Set(2, map[string]string{
+		"Rubrum":     "Schulze, K.",
+		"Gegenstand": "Betrug u.a. in 12 Fällen, Straßenverkehrsgefährdung",
+	})
+	ix.Set(3, map[string]string{
+		"Rubrum":       "Mueller, Anna",
+		"Beschreibung": "Verteidigung gegen Vorwurf der Körperverletzung",
+	})
+
+	for _, tc := range []struct {
+		query    string
+		expected string
+	}{
+		{"müller", "[1 3]"},
+		{"MUELLER", "[1 3]"},
+		{"betrug", "[1 2]"},
+		{"betrugs", "[1 2]"},
+		{"Fälle", "[2]"},
+		{"strasse", "[2]"},
+		{"schöff", "[1]"},
+		{"müller betrug", "[1]"},
+		{"körperverletzungen", "[3]"},
+		{"verteidiger", "[3]"},
+		{"unbekannt", "[]"},
+		{"", "[]"},
+	} {
+		t.Run(tc.query, func(t *testing.T) {
+			got := ids(ix.Search(tc.query, 0))
+			if got != tc.expected {
+				t.Fatalf("wrong results: expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+
+	t.Run("limit", func(t *testing.T) {
+		got := ids(ix.Search("müller", 1))
+		if got != "[1]" {
+			t.Fatalf("wrong results: expected [1], got %s", got)
+		}
+	})
+
+	t.Run("snippets", func(t *testing.T) {
+		res := ix.Search("betrug", 0)
+		expected := `[{Gegenstand <mark>Betrug</mark> u.a. in 12 Fällen, Straßenverkehrsgefährdung}]`
+		got := fmt.Sprint(res[1].Snippets)
+		if got != expected {
+			t.Fatalf("wrong snippets: expected %s, got %s", expected, got)
+		}
+	})
+
+	t.Run("update and remove", func(t *testing.T) {
+		ix.Set(2, map[string]string{"Rubrum": "Schulze, K. wegen Diebstahls"})
+		if got := ids(ix.Search("betrug", 0)); got != "[1]" {
+			t.Fatalf("wrong results after update: expected [1], got %s", got)
+		}
+		if got := ids(ix.Search("diebstahl", 0)); got != "[2]" {
+			t.Fatalf("wrong results after update: expected [2], got %s", got)
+		}
+		ix.Remove(2)
+		if got := ids(ix.Search("diebstahl", 0)); got != "[]" {
+			t.Fatalf("wrong results after remove: expected [], got %s", got)
+		}
+	})
+}
+
+func TestSnippetContext(t *testing.T) {
+	ix := search.New()
+	ix.Set(1, map[string]string{
+		"Beschreibung": "Sehr lange Beschreibung mit vielen Wörtern, bevor endlich der Haftbefehl <aufgehoben> wurde und danach noch viel mehr Text folgt, der nicht mehr im Ausschnitt steht.",
+	})
+
+	res := ix.Search("haftbefehl", 0)
+	if len(res) != 1 {
+		t.Fatalf("wrong number of results: expected 1, got %d", len(res))
+	}
+	expected := "…Beschreibung mit vielen Wörtern, bevor endlich der <mark>Haftbefehl</mark> &lt;aufgehoben&gt; wurde und danach noch viel…"
+	if res[0].Snippets[0].Text != expected {
+		t.Fatalf("wrong snippet: expected %q, got %q", expected, res[0].Snippets[0].Text)
+	}
+}
@@ -0,0 +1,615 @@
+package srv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/aktenzeichen"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/ical"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/appointment"
+)
+
+type AppointmentHandler struct {
+	Logger Logger
+	Model  *model.Model
+}
+
+func NewAppointmentHandler(logger Logger, m *model.Model) *AppointmentHandler {
+	return &AppointmentHandler{
+		Logger: logger,
+		Model:  m,
+	}
+}
+
+func (h AppointmentHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/retrieve", h.RetrieveAppointments())
+	mux.HandleFunc("/new", h.NewAppointment())
+	mux.HandleFunc("/update", h.UpdateAppointment())
+	mux.HandleFunc("/cancel", h.CancelAppointment())
+	mux.HandleFunc("/import", h.ImportAppointments())
+	mux.HandleFunc("/unassigned", h.UnassignedAppointments())
+	mux.HandleFunc("/assign", h.AssignAppointment())
+	mux.HandleFunc("/conflicts", h.Conflicts())
+	mux.HandleFunc("/buffers", h.RetrieveBuffers())
+	mux.HandleFunc("/buffer", h.SetBuffer())
+	mux.HandleFunc("/feed", h.NewFeed())
+	mux.HandleFunc("/ical/", h.ICal())
+	mux.ServeHTTP(w, r)
+}
+
+func (h AppointmentHandler) RetrieveAppointments() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, h.Logger, http.StatusOK, h.Model.Appointment)
+		},
+	)
+}
+
+// checkAppointment validates the appointment and checks the references to
+// the case and the court.
+func (h AppointmentHandler) checkAppointment(a appointment.Appointment) error {
+	v := validator.New()
+	if err := v.Struct(a); err != nil {
+		return fmt.Errorf("\n%v", err)
+	}
+	if _, err := h.Model.Case.Retrieve(a.CaseID); err != nil {
+		return err
+	}
+	if a.GerichtID != 0 {
+		if _, err := h.Model.Court.Retrieve(a.GerichtID); err != nil {
+			return err
+		}
+	}
+	start, _ := a.Start()
+	stop, _ := a.Stop()
+	if !stop.After(start) {
+		return fmt.Errorf("Ende must be after Beginn")
+	}
+	return nil
+}
+
+func (h AppointmentHandler) NewAppointment() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			a := appointment.Appointment{}
+			if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+			a.UID = ""
+			a.Abgesagt = false
+
+			if err := h.checkAppointment(a); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			id, err := h.Model.Appointment.AddAppointment(a, time.Now(), h.Model.WriteEvent("Appointment"))
+			if err != nil {
+				msg := fmt.Sprintf("Error: adding appointment: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			h.writeResult(w, id)
+		},
+	)
+}
+
+// writeResult writes the id of the appointment and warnings about scheduling
+// conflicts with other appointments.
+func (h AppointmentHandler) writeResult(w http.ResponseWriter, id int) {
+	if warnings := h.warnings(id); len(warnings) > 0 {
+		writeJSON(w, h.Logger, http.StatusOK, newCaseResult{ID: id, Warnings: warnings})
+		return
+	}
+	writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": id})
+}
+
+// warnings returns the warnings about scheduling conflicts of the appointment
+// with other appointments.
+func (h AppointmentHandler) warnings(id int) []string {
+	var warnings []string
+	for _, c := range h.Model.Appointment.ConflictsOf(id, h.Model.Appointment[id], h.Model.Buffer) {
+		warnings = append(warnings, c.Warning(id))
+	}
+	return warnings
+}
+
+type updateAppointmentRequest struct {
+	ID     int                     `json:"ID"`
+	Fields appointment.Appointment `json:"Fields"`
+}
+
+// UpdateAppointment replaces an appointment. Calendars get the change because
+// the sequence number is increased.
+func (h AppointmentHandler) UpdateAppointment() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req updateAppointmentRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+			old, err := h.Model.Appointment.Retrieve(req.ID)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			req.Fields.Abgesagt = old.Abgesagt
+
+			if err := h.checkAppointment(req.Fields); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			if err := h.Model.Appointment.UpdateAppointment(req.ID, req.Fields, time.Now(), h.Model.WriteEvent("Appointment")); err != nil {
+				msg := fmt.Sprintf("Error: updating appointment: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			h.writeResult(w, req.ID)
+		},
+	)
+}
+
+// CancelAppointment marks an appointment as cancelled. It stays in the
+// calendar feed with status CANCELLED.
+func (h AppointmentHandler) CancelAppointment() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req struct {
+				ID int `json:"ID"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			if err := h.Model.Appointment.Cancel(req.ID, time.Now(), h.Model.WriteEvent("Appointment")); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": req.ID})
+		},
+	)
+}
+
+type importedAppointment struct {
+	ID          int                     `json:"ID"`
+	Appointment appointment.Appointment `json:"Appointment"`
+
+	// Candidates are the cases with a case number found in the event if
+	// there is more than one.
+	Candidates []int `json:"Candidates,omitempty"`
+
+	// Warnings are about scheduling conflicts with other appointments.
+	Warnings []string `json:"Warnings,omitempty"`
+}
+
+type appointmentImportResult struct {
+	Assigned   []importedAppointment `json:"Assigned"`
+	Unassigned []importedAppointment `json:"Unassigned"`
+
+	// Updated contains the appointments that were imported before and
+	// changed or cancelled by a newer version of the event.
+	Updated []importedAppointment `json:"Updated"`
+
+	// Skipped contains the UIDs of events that were imported before and did
+	// not change.
+	Skipped []string `json:"Skipped"`
+}
+
+// ImportAppointments imports the events of an iCalendar file, e.g. a summons
+// of a court. An event is assigned to a case if exactly one case has a case
+// number found in the summary or the description of the event. Other events
+// are imported without case and can be assigned later. An event with a UID
+// that was imported before updates or cancels the appointment if its SEQUENCE
+// is higher than the stored one and is skipped otherwise. Imported and updated
+// appointments contain warnings about scheduling conflicts.
+func (h AppointmentHandler) ImportAppointments() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if mt != "text/calendar" {
+				http.Error(w, "Error: Content-Type must be text/calendar", http.StatusBadRequest)
+				return
+			}
+
+			events, err := ical.Parse(r.Body, appointment.Location)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: reading iCalendar data: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			result := appointmentImportResult{
+				Assigned:   []importedAppointment{},
+				Unassigned: []importedAppointment{},
+				Updated:    []importedAppointment{},
+				Skipped:    []string{},
+			}
+			for _, e := range events {
+				if id, ok := h.Model.Appointment.ByUID(e.UID); ok {
+					updated, err := h.Model.Appointment.UpdateImported(id, fromEvent(e), time.Now(), h.Model.WriteEvent("Appointment"))
+					if err != nil {
+						msg := fmt.Sprintf("Error: updating appointment %s: %v", e.UID, err)
+						h.Logger.Printf(msg)
+						http.Error(w, msg, http.StatusInternalServerError)
+						return
+					}
+					if !updated {
+						result.Skipped = append(result.Skipped, e.UID)
+						continue
+					}
+					result.Updated = append(result.Updated, importedAppointment{ID: id, Appointment: h.Model.Appointment[id]})
+					continue
+				}
+
+				a := fromEvent(e)
+				candidates := h.candidates(e.Summary + "\n" + e.Description)
+				if len(candidates) == 1 {
+					a.CaseID = candidates[0]
+				}
+
+				id, err := h.Model.Appointment.Import(a, time.Now(), h.Model.WriteEvent("Appointment"))
+				if err != nil {
+					msg := fmt.Sprintf("Error: adding appointment %s: %v", e.UID, err)
+					h.Logger.Printf(msg)
+					http.Error(w, msg, http.StatusInternalServerError)
+					return
+				}
+
+				imported := importedAppointment{ID: id, Appointment: h.Model.Appointment[id]}
+				if a.CaseID != 0 {
+					result.Assigned = append(result.Assigned, imported)
+					continue
+				}
+				if len(candidates) > 1 {
+					imported.Candidates = candidates
+				}
+				result.Unassigned = append(result.Unassigned, imported)
+			}
+
+			// The warnings are computed after the import so that they also
+			// contain the conflicts between the imported appointments.
+			for _, l := range [][]importedAppointment{result.Assigned, result.Unassigned, result.Updated} {
+				for i := range l {
+					l[i].Warnings = h.warnings(l[i].ID)
+				}
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, result)
+		},
+	)
+}
+
+// candidates returns the ids of all cases with a case number found in the
+// text.
+func (h AppointmentHandler) candidates(text string) []int {
+	var ids []int
+	for _, a := range aktenzeichen.Find(text) {
+		for _, id := range h.Model.Case.WithCaseNumber(a) {
+			if !containsInt(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func containsInt(l []int, n int) bool {
+	for _, v := range l {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+// fromEvent returns an appointment without case for an imported event. The
+// kind of appointment is guessed from the summary.
+func fromEvent(e ical.Event) appointment.Appointment {
+	a := appointment.Appointment{
+		Art:            "Sonstiges",
+		Titel:          e.Summary,
+		Beginn:         e.Start.In(appointment.Location).Format(appointment.TimeLayout),
+		Ort:            e.Location,
+		Notiz:          e.Description,
+		UID:            e.UID,
+		ImportSequence: e.Sequence,
+		Abgesagt:       e.Cancelled,
+	}
+	if e.End.After(e.Start) {
+		a.Ende = e.End.In(appointment.Location).Format(appointment.TimeLayout)
+	}
+
+	summary := strings.ToLower(e.Summary)
+	switch {
+	case strings.Contains(summary, "verhandlung"):
+		a.Art = "Hauptverhandlung"
+	case strings.Contains(summary, "akteneinsicht"):
+		a.Art = "Akteneinsicht"
+	case strings.Contains(summary, "haftbesuch") || strings.Contains(summary, "jva"):
+		a.Art = "Haftbesuch"
+	}
+	return a
+}
+
+// UnassignedAppointments returns all imported appointments without case.
+func (h AppointmentHandler) UnassignedAppointments() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			result := []importedAppointment{}
+			for _, id := range h.Model.Appointment.Unassigned() {
+				a := h.Model.Appointment[id]
+				imported := importedAppointment{ID: id, Appointment: a}
+				if candidates := h.candidates(a.Titel + "\n" + a.Notiz); len(candidates) > 1 {
+					imported.Candidates = candidates
+				}
+				result = append(result, imported)
+			}
+			writeJSON(w, h.Logger, http.StatusOK, result)
+		},
+	)
+}
+
+type assignAppointmentRequest struct {
+	ID     int `json:"ID"`
+	CaseID int `json:"CaseID"`
+}
+
+// AssignAppointment assigns an appointment to a case, e.g. an imported
+// appointment without case. Like NewAppointment it returns warnings about
+// scheduling conflicts.
+func (h AppointmentHandler) AssignAppointment() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req assignAppointmentRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+			a, err := h.Model.Appointment.Retrieve(req.ID)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			if _, err := h.Model.Case.Retrieve(req.CaseID); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			a.CaseID = req.CaseID
+
+			if err := h.Model.Appointment.UpdateAppointment(req.ID, a, time.Now(), h.Model.WriteEvent("Appointment")); err != nil {
+				msg := fmt.Sprintf("Error: updating appointment: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			h.writeResult(w, req.ID)
+		},
+	)
+}
+
+// Conflicts returns all overlaps and too short gaps between appointments that
+// end after the given date (query parameter date, default today).
+func (h AppointmentHandler) Conflicts() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			today, err := parseToday(r)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			after := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, appointment.Location)
+			writeJSON(w, h.Logger, http.StatusOK, h.Model.Appointment.Conflicts(after, h.Model.Buffer))
+		},
+	)
+}
+
+func (h AppointmentHandler) RetrieveBuffers() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, h.Logger, http.StatusOK, h.Model.Buffer)
+		},
+	)
+}
+
+// SetBuffer sets the time needed between appointments at two courts.
+func (h AppointmentHandler) SetBuffer() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			b := appointment.Buffer{}
+			if err := json.NewDecoder(r.Body).Decode(&b); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(b); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+			for _, id := range []int{b.GerichtA, b.GerichtB} {
+				if _, err := h.Model.Court.Retrieve(id); err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+					return
+				}
+			}
+
+			id, err := h.Model.Buffer.SetBuffer(b, h.Model.WriteEvent("AppointmentBuffer"))
+			if err != nil {
+				msg := fmt.Sprintf("Error: setting appointment buffer: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": id})
+		},
+	)
+}
+
+type newFeedResult struct {
+	ID    int    `json:"id"`
+	Token string `json:"Token"`
+	URL   string `json:"URL"`
+}
+
+// NewFeed creates a calendar feed with all appointments for a new subscriber,
+// e.g. a phone. The response contains the secret URL of the feed.
+func (h AppointmentHandler) NewFeed() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			f := appointment.Feed{}
+			if err := json.NewDecoder(r.Body).Decode(&f); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(f); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+
+			id, f, err := h.Model.Feed.AddFeed(f, h.Model.WriteEvent("CalendarFeed"))
+			if err != nil {
+				msg := fmt.Sprintf("Error: adding calendar feed: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, newFeedResult{
+				ID:    id,
+				Token: f.Token,
+				URL:   "/" + APIPrefix + "/appointment/ical/" + f.Token + ".ics",
+			})
+		},
+	)
+}
+
+// ICal returns the calendar feed with all appointments. The path contains the
+// secret token of the feed.
+func (h AppointmentHandler) ICal() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			token := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/ical/"), ".ics")
+			f, ok := h.Model.Feed.ByToken(token)
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+
+			var events []ical.Event
+			for _, id := range h.Model.Appointment.IDs() {
+				events = append(events, h.event(h.Model.Appointment[id]))
+			}
+
+			buf := new(bytes.Buffer)
+			if err := ical.Write(buf, "Termine "+f.Name, events); err != nil {
+				msg := fmt.Sprintf("Error: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				h.Logger.Printf("Error: writing response body: %v", err)
+			}
+		},
+	)
+}
+
+// event returns the calendar event of the appointment. The summary contains
+// the kind of appointment and the Rubrum of the case.
+func (h AppointmentHandler) event(a appointment.Appointment) ical.Event {
+	start, _ := a.Start()
+	stop, _ := a.Stop()
+	stamp, _ := time.Parse(time.RFC3339, a.Geaendert)
+
+	summary := a.Art
+	if a.Titel != "" {
+		summary += " " + a.Titel
+	}
+	if c, ok := h.Model.Case[a.CaseID]; ok {
+		summary += ": " + c.Rubrum
+		if c.Az != "" {
+			summary += " (" + c.Az + ")"
+		}
+	}
+
+	location := a.Ort
+	if g, ok := h.Model.Court[a.GerichtID]; ok && location == "" {
+		location = g.Name
+	}
+
+	return ical.Event{
+		UID:         a.UID,
+		Sequence:    a.Sequence,
+		Stamp:       stamp,
+		Start:       start,
+		End:         stop,
+		Summary:     summary,
+		Location:    location,
+		Description: a.Notiz,
+		Cancelled:   a.Abgesagt,
+	}
+}
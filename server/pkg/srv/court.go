@@ -0,0 +1,131 @@
+package srv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/court"
+)
+
+type CourtHandler struct {
+	Logger Logger
+	Model  *model.Model
+}
+
+func NewCourtHandler(logger Logger, m *model.Model) *CourtHandler {
+	return &CourtHandler{
+		Logger: logger,
+		Model:  m,
+	}
+}
+
+func (h CourtHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/retrieve", h.RetrieveCourts())
+	mux.HandleFunc("/new", h.NewCourt())
+	mux.HandleFunc("/suggestions", h.Suggestions())
+	mux.HandleFunc("/assign", h.Assign())
+	mux.ServeHTTP(w, r)
+}
+
+func (h CourtHandler) RetrieveCourts() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, h.Logger, http.StatusOK, h.Model.Court)
+		},
+	)
+}
+
+func (h CourtHandler) NewCourt() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			d := json.NewDecoder(r.Body)
+			c := court.Court{}
+			if err := d.Decode(&c); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(c); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+
+			id, err := h.Model.Court.AddCourt(c, h.Model.WriteEvent("Court"))
+			if err != nil {
+				msg := fmt.Sprintf("Error: adding court: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": id})
+		},
+	)
+}
+
+// Suggestions returns suggested courts for all cases that do not reference a
+// court yet. It is used to migrate the free-text field Gericht.
+func (h CourtHandler) Suggestions() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, h.Logger, http.StatusOK, h.Model.CourtSuggestions())
+		},
+	)
+}
+
+type assignRequest struct {
+	Case  int `json:"Case"`
+	Court int `json:"Court"`
+}
+
+// Assign sets the court reference of a case.
+func (h CourtHandler) Assign() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req assignRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			c, err := h.Model.Case.Retrieve(req.Case)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			if _, err := h.Model.Court.Retrieve(req.Court); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			c.GerichtID = req.Court
+			if err := h.Model.UpdateCase(req.Case, c); err != nil {
+				msg := fmt.Sprintf("Error: updating case: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": req.Case})
+		},
+	)
+}
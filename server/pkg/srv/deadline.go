@@ -0,0 +1,408 @@
+package srv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/enforcement"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/deadline"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+// DefaultUpcomingDays is the number of days for the list of upcoming
+// deadlines if the query parameter days is not given.
+const DefaultUpcomingDays = 14
+
+// DefaultVorfrist is the number of days before a key date of the enforcement
+// of a sentence from which on the deadline is listed as upcoming if the
+// request does not contain Vorfrist. The application for the suspension of the
+// remainder of a sentence needs time, because the court hears the prison and
+// possibly an expert (§ 454 StPO).
+const DefaultVorfrist = 90
+
+type DeadlineHandler struct {
+	Logger Logger
+	Model  *model.Model
+}
+
+func NewDeadlineHandler(logger Logger, m *model.Model) *DeadlineHandler {
+	return &DeadlineHandler{
+		Logger: logger,
+		Model:  m,
+	}
+}
+
+func (h DeadlineHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/retrieve", h.RetrieveDeadlines())
+	mux.HandleFunc("/rules", h.Rules())
+	mux.HandleFunc("/calculate", h.Calculate())
+	mux.HandleFunc("/new", h.NewDeadline())
+	mux.HandleFunc("/upcoming", h.Upcoming())
+	mux.HandleFunc("/overdue", h.Overdue())
+	mux.HandleFunc("/done", h.Done())
+	mux.HandleFunc("/custody", h.Custody())
+	mux.HandleFunc("/enforcement", h.Enforcement())
+	mux.ServeHTTP(w, r)
+}
+
+// RetrieveDeadlines returns all deadlines as map from id to deadline. With
+// the query parameter case it returns the list of deadlines of this case.
+func (h DeadlineHandler) RetrieveDeadlines() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			v := r.URL.Query().Get("case")
+			if v == "" {
+				writeJSON(w, h.Logger, http.StatusOK, h.Model.Deadline)
+				return
+			}
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: query parameter case: invalid value %q", v), http.StatusBadRequest)
+				return
+			}
+			l := h.Model.Deadline.OfCase(id)
+			if l == nil {
+				l = []deadline.Entry{}
+			}
+			writeJSON(w, h.Logger, http.StatusOK, l)
+		},
+	)
+}
+
+// Rules returns the deadlines that can be calculated.
+func (h DeadlineHandler) Rules() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, h.Logger, http.StatusOK, deadline.Rules)
+		},
+	)
+}
+
+type calculateResult struct {
+	Art        string `json:"Art"`
+	Ereignis   string `json:"Ereignis"`
+	Bundesland string `json:"Bundesland"`
+	Ende       string `json:"Ende"`
+}
+
+// Calculate returns the end of a deadline without saving it. Use the query
+// parameters art, ereignis (the day of the event) and land (the Bundesland)
+// or case (a case with a court of the court directory).
+func (h DeadlineHandler) Calculate() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			event, err := lawcase.ParseDate(q.Get("ereignis"))
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: query parameter ereignis: %v", err), http.StatusBadRequest)
+				return
+			}
+			land := q.Get("land")
+			if v := q.Get("case"); v != "" && land == "" {
+				id, err := strconv.Atoi(v)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: query parameter case: invalid value %q", v), http.StatusBadRequest)
+					return
+				}
+				land = h.Model.CaseLand(id)
+			}
+
+			end, err := deadline.Calculate(q.Get("art"), event, land)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, calculateResult{
+				Art:        q.Get("art"),
+				Ereignis:   event.Format(deadline.DateLayout),
+				Bundesland: land,
+				Ende:       end.Format(deadline.DateLayout),
+			})
+		},
+	)
+}
+
+// NewDeadline adds a new deadline. If Ende is empty, it is calculated from Art
+// and Ereignis. If Bundesland is empty, the Bundesland of the court of the
+// case is used.
+func (h DeadlineHandler) NewDeadline() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			dl := deadline.Deadline{}
+			if err := json.NewDecoder(r.Body).Decode(&dl); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			if _, err := h.Model.Case.Retrieve(dl.CaseID); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			if dl.Bundesland == "" {
+				dl.Bundesland = h.Model.CaseLand(dl.CaseID)
+			}
+			for _, f := range []*string{&dl.Ereignis, &dl.Ende} {
+				if *f == "" {
+					continue
+				}
+				t, err := lawcase.ParseDate(*f)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+					return
+				}
+				*f = t.Format(deadline.DateLayout)
+			}
+			if dl.Ende == "" && dl.Ereignis != "" {
+				event, _ := time.Parse(deadline.DateLayout, dl.Ereignis)
+				end, err := deadline.Calculate(dl.Art, event, dl.Bundesland)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+					return
+				}
+				dl.Ende = end.Format(deadline.DateLayout)
+			}
+
+			v := validator.New()
+			if err := v.Struct(dl); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+
+			id, err := h.Model.Deadline.AddDeadline(dl, h.Model.WriteEvent("Deadline"))
+			if err != nil {
+				msg := fmt.Sprintf("Error: adding deadline: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, deadline.Entry{ID: id, Deadline: dl})
+		},
+	)
+}
+
+// Upcoming returns the open deadlines ending within the next days. Use the
+// query parameter days (default DefaultUpcomingDays) and date (default today).
+func (h DeadlineHandler) Upcoming() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			today, err := parseToday(r)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			days := DefaultUpcomingDays
+			if v := r.URL.Query().Get("days"); v != "" {
+				d, err := strconv.Atoi(v)
+				if err != nil || d < 0 {
+					http.Error(w, fmt.Sprintf("Error: invalid request: query parameter days: invalid value %q", v), http.StatusBadRequest)
+					return
+				}
+				days = d
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, h.Model.Deadline.Upcoming(today, days))
+		},
+	)
+}
+
+// Custody returns the cases where the client is in custody with the next
+// review date under § 121 StPO, the most urgent first. The query parameter
+// date replaces today.
+func (h DeadlineHandler) Custody() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			today, err := parseToday(r)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, h.Logger, http.StatusOK, deadline.Custody(h.Model.Case, today))
+		},
+	)
+}
+
+type enforcementRequest struct {
+	CaseID int `json:"CaseID" validate:"required"`
+	enforcement.Strafe
+
+	// Vorfrist is the number of days before every key date from which on it
+	// is listed as upcoming, see DefaultVorfrist.
+	Vorfrist *int `json:"Vorfrist" validate:"omitempty,min=0"`
+}
+
+type enforcementResult struct {
+	enforcement.Result
+	Fristen []deadline.Entry `json:"Fristen"`
+}
+
+// Enforcement computes the key dates of the enforcement of a prison sentence
+// and adds them as deadlines of the case.
+func (h DeadlineHandler) Enforcement() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req enforcementRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			if _, err := h.Model.Case.Retrieve(req.CaseID); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			fields := []*string{&req.Beginn}
+			for i := range req.Anrechnung {
+				fields = append(fields, &req.Anrechnung[i].Von, &req.Anrechnung[i].Bis)
+			}
+			for _, f := range fields {
+				if *f == "" {
+					continue
+				}
+				t, err := lawcase.ParseDate(*f)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+					return
+				}
+				*f = t.Format(enforcement.DateLayout)
+			}
+
+			v := validator.New()
+			if err := v.Struct(req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+
+			res, err := enforcement.Compute(req.Strafe)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			vorfrist := DefaultVorfrist
+			if req.Vorfrist != nil {
+				vorfrist = *req.Vorfrist
+			}
+			land := h.Model.CaseLand(req.CaseID)
+			result := enforcementResult{Result: res, Fristen: []deadline.Entry{}}
+			for _, d := range res.Dates() {
+				end, _ := time.Parse(enforcement.DateLayout, d.Datum)
+				dl := deadline.Deadline{
+					CaseID:      req.CaseID,
+					Art:         d.Art,
+					Bezeichnung: fmt.Sprintf("%s (%s)", d.Art, d.Norm),
+					Ereignis:    req.Beginn,
+					Ende:        d.Datum,
+					Bundesland:  land,
+					Vorfrist:    end.AddDate(0, 0, -vorfrist).Format(deadline.DateLayout),
+				}
+				id, err := h.Model.Deadline.AddDeadline(dl, h.Model.WriteEvent("Deadline"))
+				if err != nil {
+					msg := fmt.Sprintf("Error: adding deadline: %v", err)
+					h.Logger.Printf(msg)
+					http.Error(w, msg, http.StatusInternalServerError)
+					return
+				}
+				result.Fristen = append(result.Fristen, deadline.Entry{ID: id, Deadline: dl})
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, result)
+		},
+	)
+}
+
+// Overdue returns the open deadlines that ended before today. The query
+// parameter date replaces today.
+func (h DeadlineHandler) Overdue() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			today, err := parseToday(r)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, h.Model.Deadline.Overdue(today))
+		},
+	)
+}
+
+// Done marks a deadline as done. Without Datum the deadline is done today.
+func (h DeadlineHandler) Done() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var d deadline.Done
+			if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+			if d.Datum == "" {
+				d.Datum = time.Now().Format(deadline.DateLayout)
+			}
+			t, err := lawcase.ParseDate(d.Datum)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: field Datum: %v", err), http.StatusBadRequest)
+				return
+			}
+			d.Datum = t.Format(deadline.DateLayout)
+
+			if err := h.Model.Deadline.MarkDone(d, h.Model.WriteEvent("DeadlineDone")); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": d.ID})
+		},
+	)
+}
+
+// parseToday returns the query parameter date or today.
+func parseToday(r *http.Request) (time.Time, error) {
+	v := r.URL.Query().Get("date")
+	if v == "" {
+		return today(), nil
+	}
+	t, err := lawcase.ParseDate(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("query parameter date: %w", err)
+	}
+	return t, nil
+}
+
+// today returns the current calendar date at midnight UTC like the dates
+// parsed by lawcase.ParseDate.
+func today() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,75 @@
+package srv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/export"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+type ExportHandler struct {
+	Logger Logger
+	Model  *model.Model
+}
+
+func NewExportHandler(logger Logger, m *model.Model) *ExportHandler {
+	return &ExportHandler{
+		Logger: logger,
+		Model:  m,
+	}
+}
+
+func (h ExportHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/cases.csv", h.exportCases(
+		"text/csv; charset=utf-8",
+		"faelle.csv",
+		export.CSV,
+	))
+	mux.HandleFunc("/cases.xlsx", h.exportCases(
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"faelle.xlsx",
+		export.XLSX,
+	))
+	mux.ServeHTTP(w, r)
+}
+
+// exportCases returns a handler that writes all cases selected by the query
+// parameters with the given export function as file download.
+func (h ExportHandler) exportCases(
+	contentType string,
+	filename string,
+	fn func(io.Writer, lawcase.Model, lawcase.Filter) error,
+) func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			f, err := parseCaseFilter(r)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			buf := new(bytes.Buffer)
+			if err := fn(buf, h.Model.Case, f); err != nil {
+				msg := fmt.Sprintf("Error: creating export: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+			if _, err := buf.WriteTo(w); err != nil {
+				msg := fmt.Sprintf("Error: writing response body: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+		},
+	)
+}
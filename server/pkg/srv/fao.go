@@ -0,0 +1,54 @@
+package srv
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/fao"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+)
+
+type FAOHandler struct {
+	Logger  Logger
+	Model   *model.Model
+	Options fao.Options
+}
+
+func NewFAOHandler(logger Logger, m *model.Model, o fao.Options) *FAOHandler {
+	return &FAOHandler{
+		Logger:  logger,
+		Model:   m,
+		Options: o,
+	}
+}
+
+// ServeHTTP returns the FAO report of all cases selected by the filter
+// parameters (see parseFAOFilter). All instances of a proceeding count as one
+// case. The query parameter merged (one or several) overrides the configured
+// rule for joined cases.
+func (h FAOHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			f, err := parseFAOFilter(r)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			o := h.Options
+			switch v := r.URL.Query().Get("merged"); v {
+			case "":
+			case "one":
+				o.MergedAsOne = true
+			case "several":
+				o.MergedAsOne = false
+			default:
+				http.Error(w, fmt.Sprintf("Error: invalid request: query parameter merged: expected one or several, got %q", v), http.StatusBadRequest)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, fao.Compute(h.Model.Case, h.Model.Court, h.Model.Proceeding, f, o))
+		},
+	)(w, r)
+}
@@ -3,11 +3,21 @@ package srv
 import (
 	"encoding/json"
 	"fmt"
+	"mime"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/aktenzeichen"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/conflict"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/csvimport"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/fee"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/limitation"
 	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
 	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/search"
 )
 
 type CaseHandler struct {
@@ -26,13 +36,36 @@ func (h CaseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/retrieve", h.RetrieveCases())
 	mux.HandleFunc("/new", h.NewCase())
+	mux.HandleFunc("/import", h.ImportCases())
+	mux.HandleFunc("/search", h.SearchCases())
+	mux.HandleFunc("/az", h.ChangeAz())
+	mux.HandleFunc("/hearing", h.AddHearingDay())
+	mux.HandleFunc("/merge", h.MergeCases())
+	mux.HandleFunc("/split", h.SplitCase())
+	mux.HandleFunc("/roles", h.RetrieveRoles())
+	mux.HandleFunc("/party", h.AddParty())
+	mux.HandleFunc("/custody", h.SetCustody())
+	mux.HandleFunc("/interruption", h.AddInterruption())
+	mux.HandleFunc("/limitation", h.Limitation())
+	mux.HandleFunc("/close", h.CloseCase())
+	mux.HandleFunc("/fees", h.Fees())
 	mux.ServeHTTP(w, r)
 }
 
+// RetrieveCases returns all cases as map from id to case. If any query
+// parameter is given, it returns a page of a filtered and sorted list instead.
+// Use the filter parameters (see parseCaseFilter), sort (a field name, prefix
+// with - for descending order), limit and cursor (NextCursor of the previous
+// page).
 func (h CaseHandler) RetrieveCases() func(http.ResponseWriter, *http.Request) {
 	return methodAllowed(
 		http.MethodGet,
 		func(w http.ResponseWriter, r *http.Request) {
+			if len(r.URL.Query()) > 0 {
+				h.listCases(w, r)
+				return
+			}
+
 			b, err := json.Marshal(h.Model.Case)
 			if err != nil {
 				msg := fmt.Sprintf("Error: marshalling JSON: %v", err)
@@ -52,6 +85,61 @@ func (h CaseHandler) RetrieveCases() func(http.ResponseWriter, *http.Request) {
 	)
 }
 
+// RetrieveRoles returns the catalog of roles, i. e. the allowed values for
+// the fields Art and Rollen.
+func (h CaseHandler) RetrieveRoles() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, h.Logger, http.StatusOK, lawcase.Rollen)
+		},
+	)
+}
+
+func (h CaseHandler) listCases(w http.ResponseWriter, r *http.Request) {
+	f, err := parseCaseFilter(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	q := r.URL.Query()
+	opts := lawcase.ListOptions{
+		Filter: f,
+		Sort:   strings.TrimPrefix(q.Get("sort"), "-"),
+		Desc:   strings.HasPrefix(q.Get("sort"), "-"),
+		Cursor: q.Get("cursor"),
+	}
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error: invalid request: query parameter limit: %v", err), http.StatusBadRequest)
+			return
+		}
+		opts.Limit = limit
+	}
+
+	result, err := h.Model.Case.List(opts)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, h.Logger, http.StatusOK, result)
+}
+
+// newCaseResult is the response body of the new case handler if there are
+// warnings.
+type newCaseResult struct {
+	ID       int      `json:"id"`
+	Warnings []string `json:"warnings"`
+}
+
+// NewCase adds a new case. The case number in Az is normalized. If it is not
+// recognized, if the case would put us on opposite sides of a known person
+// (see package conflict) or if prosecution may already be time-barred (see
+// package limitation), the case is saved anyway and the response contains a
+// warning. A new case must not contain an Abschluss, joins or severances.
 func (h CaseHandler) NewCase() func(http.ResponseWriter, *http.Request) {
 	return methodAllowed(
 		http.MethodPost,
@@ -68,13 +156,46 @@ func (h CaseHandler) NewCase() func(http.ResponseWriter, *http.Request) {
 				return
 			}
 
-			v := validator.New()
+			v := lawcase.NewValidator()
 			if err := v.Struct(c); err != nil {
 				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
 				return
 			}
 
-			id, err := h.Model.Case.AddCase(c, h.Model.WriteEvent("Case"))
+			// The outcome is only set by closing the case, see CloseCase.
+			if c.Abschluss != nil {
+				http.Error(w, "Error: invalid request: Abschluss: a new case can not be closed, use /api/case/close", http.StatusBadRequest)
+				return
+			}
+
+			// Joins and severances are only set by MergeCases and SplitCase
+			// so that both cases reference each other.
+			if len(c.Verbunden) > 0 || c.VerbundenIn != nil || len(c.Abgetrennt) > 0 || c.AbgetrenntVon != nil {
+				http.Error(w, "Error: invalid request: Verbunden, VerbundenIn, Abgetrennt and AbgetrenntVon must be empty, use /api/case/merge and /api/case/split", http.StatusBadRequest)
+				return
+			}
+
+			if c.GerichtID != 0 {
+				if _, err := h.Model.Court.Retrieve(c.GerichtID); err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+					return
+				}
+			}
+			for _, b := range c.Beteiligte {
+				if _, err := h.Model.Person.Retrieve(b.PersonID); err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+					return
+				}
+			}
+
+			warnings := c.Warnings()
+			warnings = append(warnings, conflict.Warnings(conflict.Check(h.Model.Case, h.Model.Person, c, 0))...)
+			if res, err := limitation.Compute(c, today()); err == nil && res.Verjaehrt {
+				warnings = append(warnings, fmt.Sprintf("Tatende: prosecution may be time-barred, limitation period ended on %s", res.Ablauf))
+			}
+			c.Az = aktenzeichen.Normalize(c.Az)
+
+			id, err := h.Model.AddCase(c)
 			if err != nil {
 				msg := fmt.Sprintf("Error: adding case: %v", err)
 				h.Logger.Printf(msg)
@@ -82,6 +203,11 @@ func (h CaseHandler) NewCase() func(http.ResponseWriter, *http.Request) {
 				return
 			}
 
+			if len(warnings) > 0 {
+				writeJSON(w, h.Logger, http.StatusOK, newCaseResult{ID: id, Warnings: warnings})
+				return
+			}
+
 			w.Header().Set("Content-Type", "application/json")
 			respBody := []byte(fmt.Sprintf(`{"id":%d}`, id))
 			if _, err := w.Write(respBody); err != nil {
@@ -94,6 +220,627 @@ func (h CaseHandler) NewCase() func(http.ResponseWriter, *http.Request) {
 	)
 }
 
+// ChangeAz adds a new case number to a case. The number of the same
+// authority that was valid until then ends at the given date. If the number
+// is not recognized or does not fit the authority, it is saved anyway and the
+// response contains a warning.
+func (h CaseHandler) ChangeAz() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var ch lawcase.AzChange
+			if err := json.NewDecoder(r.Body).Decode(&ch); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(ch); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+			d, err := lawcase.ParseDate(ch.Datum)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: field Datum: %v", err), http.StatusBadRequest)
+				return
+			}
+			ch.Datum = d.Format(lawcase.DateLayout)
+			if _, err := h.Model.Case.Retrieve(ch.ID); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			warnings := ch.Warnings()
+			if err := h.Model.ChangeAz(ch); err != nil {
+				msg := fmt.Sprintf("Error: changing case number: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			if warnings == nil {
+				warnings = []string{}
+			}
+			writeJSON(w, h.Logger, http.StatusOK, newCaseResult{ID: ch.ID, Warnings: warnings})
+		},
+	)
+}
+
+type hearingRequest struct {
+	Case  int    `json:"Case"`
+	Datum string `json:"Datum"`
+}
+
+// AddHearingDay adds a day of the main hearing to a case. Days that are
+// already recorded are ignored.
+func (h CaseHandler) AddHearingDay() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req hearingRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			c, err := h.Model.Case.Retrieve(req.Case)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			d, err := lawcase.ParseDate(req.Datum)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: field Datum: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			day := d.Format(lawcase.DateLayout)
+			for _, existing := range c.Hauptverhandlungstage {
+				if existing == day {
+					writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": req.Case})
+					return
+				}
+			}
+			days := append([]string{}, c.Hauptverhandlungstage...)
+			c.Hauptverhandlungstage = append(days, day)
+			sort.Strings(c.Hauptverhandlungstage)
+
+			if err := h.Model.UpdateCase(req.Case, c); err != nil {
+				msg := fmt.Sprintf("Error: updating case: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": req.Case})
+		},
+	)
+}
+
+type custodyRequest struct {
+	Case int           `json:"Case"`
+	Haft *lawcase.Haft `json:"Haft"`
+}
+
+// SetCustody sets or replaces the custody of the client in a case. A Haft of
+// null removes it, e.g. if it was recorded by mistake. A release is recorded
+// with the field Entlassung instead.
+func (h CaseHandler) SetCustody() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req custodyRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			c, err := h.Model.Case.Retrieve(req.Case)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			if req.Haft != nil {
+				v := validator.New()
+				if err := v.Struct(req.Haft); err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+					return
+				}
+			}
+			c.Haft = req.Haft
+
+			if err := h.Model.UpdateCase(req.Case, c); err != nil {
+				msg := fmt.Sprintf("Error: updating case: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": req.Case})
+		},
+	)
+}
+
+type interruptionRequest struct {
+	Case int `json:"Case"`
+	lawcase.Unterbrechung
+}
+
+// AddInterruption adds an act that interrupts the limitation period
+// (§ 78c StGB) to a case.
+func (h CaseHandler) AddInterruption() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req interruptionRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(req.Unterbrechung); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+			c, err := h.Model.Case.Retrieve(req.Case)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			l := append([]lawcase.Unterbrechung{}, c.Unterbrechungen...)
+			c.Unterbrechungen = append(l, req.Unterbrechung)
+			sort.SliceStable(c.Unterbrechungen, func(i, j int) bool {
+				return c.Unterbrechungen[i].Datum < c.Unterbrechungen[j].Datum
+			})
+
+			if err := h.Model.UpdateCase(req.Case, c); err != nil {
+				msg := fmt.Sprintf("Error: updating case: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": req.Case})
+		},
+	)
+}
+
+// Limitation returns the limitation of prosecution of the case given by the
+// query parameter case with the reasoning. The query parameter date replaces
+// today.
+func (h CaseHandler) Limitation() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			v := r.URL.Query().Get("case")
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: query parameter case: invalid value %q", v), http.StatusBadRequest)
+				return
+			}
+			c, err := h.Model.Case.Retrieve(id)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			today, err := parseToday(r)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			res, err := limitation.Compute(c, today)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: case %d: %v", id, err), http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, h.Logger, http.StatusOK, res)
+		},
+	)
+}
+
+// Fees proposes the statutory fees of the case under the RVG. The stage, the
+// court and the hearing days are taken from the case. The query parameters
+// stufe and gericht replace the stage and the court, pflichtverteidiger=true
+// uses the fees of court-appointed counsel.
+func (h CaseHandler) Fees() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			id, err := strconv.Atoi(q.Get("case"))
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: query parameter case: invalid value %q", q.Get("case")), http.StatusBadRequest)
+				return
+			}
+			c, err := h.Model.Case.Retrieve(id)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := fee.FromCase(id, c, h.Model.Court, h.Model.Proceeding)
+			if s := q.Get("stufe"); s != "" {
+				v.Stufe = s
+			}
+			if g := q.Get("gericht"); g != "" {
+				v.Gericht = g
+			}
+			if p := q.Get("pflichtverteidiger"); p != "" {
+				b, err := strconv.ParseBool(p)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: query parameter pflichtverteidiger: invalid value %q", p), http.StatusBadRequest)
+					return
+				}
+				v.Pflichtverteidiger = b
+			}
+
+			res, err := fee.Propose(v)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: case %d: %v", id, err), http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, h.Logger, http.StatusOK, res)
+		},
+	)
+}
+
+type partyRequest struct {
+	Case int `json:"Case"`
+	lawcase.Beteiligter
+}
+
+// AddParty links a person to an existing case. If this leads to a conflict of
+// interest, the person is linked anyway and the response contains a warning.
+func (h CaseHandler) AddParty() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req partyRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(req.Beteiligter); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+			c, err := h.Model.Case.Retrieve(req.Case)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			if _, err := h.Model.Person.Retrieve(req.PersonID); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			c.Beteiligte = append(append([]lawcase.Beteiligter{}, c.Beteiligte...), req.Beteiligter)
+			warnings := conflict.Warnings(conflict.Check(h.Model.Case, h.Model.Person, c, req.Case))
+			if err := h.Model.UpdateCase(req.Case, c); err != nil {
+				msg := fmt.Sprintf("Error: updating case: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			if warnings == nil {
+				warnings = []string{}
+			}
+			writeJSON(w, h.Logger, http.StatusOK, newCaseResult{ID: req.Case, Warnings: warnings})
+		},
+	)
+}
+
+// MergeCases joins the case From to the case ID (Verbindung).
+func (h CaseHandler) MergeCases() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var mg lawcase.Merge
+			if err := json.NewDecoder(r.Body).Decode(&mg); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(mg); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+			d, err := lawcase.ParseDate(mg.Datum)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: field Datum: %v", err), http.StatusBadRequest)
+				return
+			}
+			mg.Datum = d.Format(lawcase.DateLayout)
+
+			if err := h.Model.MergeCases(mg); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": mg.ID})
+		},
+	)
+}
+
+// CloseCase records the outcome of a case and, for convictions and
+// Strafbefehle, the sentence.
+func (h CaseHandler) CloseCase() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var a lawcase.Abschluss
+			if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(a); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+			d, err := lawcase.ParseDate(a.Datum)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: field Datum: %v", err), http.StatusBadRequest)
+				return
+			}
+			a.Datum = d.Format(lawcase.DateLayout)
+
+			if err := h.Model.CloseCase(a); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": a.ID})
+		},
+	)
+}
+
+// SplitCase severs a new case from the case ID (Abtrennung). The request
+// contains the fields of the new case.
+func (h CaseHandler) SplitCase() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var sp lawcase.Split
+			if err := json.NewDecoder(r.Body).Decode(&sp); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := lawcase.NewValidator()
+			if err := v.Struct(sp); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+			d, err := lawcase.ParseDate(sp.Datum)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: field Datum: %v", err), http.StatusBadRequest)
+				return
+			}
+			sp.Datum = d.Format(lawcase.DateLayout)
+			if _, err := h.Model.Case.Retrieve(sp.ID); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			sp.Fields.Az = aktenzeichen.Normalize(sp.Fields.Az)
+
+			id, err := h.Model.SplitCase(sp)
+			if err != nil {
+				msg := fmt.Sprintf("Error: severing case: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": id})
+		},
+	)
+}
+
+// searchResult is one found case in the response body of the search handler.
+type searchResult struct {
+	search.Result
+	Case lawcase.Case `json:"Case"`
+}
+
+// SearchCases returns all cases matching the query parameter q ranked by
+// relevance. The query parameter limit restricts the number of results.
+func (h CaseHandler) SearchCases() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			if strings.TrimSpace(q.Get("q")) == "" {
+				http.Error(w, "Error: query parameter q is required", http.StatusBadRequest)
+				return
+			}
+			var limit int
+			if v := q.Get("limit"); v != "" {
+				l, err := strconv.Atoi(v)
+				if err != nil || l < 0 {
+					http.Error(w, fmt.Sprintf("Error: invalid request: query parameter limit: invalid value %q", v), http.StatusBadRequest)
+					return
+				}
+				limit = l
+			}
+
+			results := []searchResult{}
+			for _, res := range h.Model.Search.Search(q.Get("q"), limit) {
+				results = append(results, searchResult{
+					Result: res,
+					Case:   h.Model.Case[res.ID],
+				})
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string][]searchResult{"Results": results})
+		},
+	)
+}
+
+// importResult is the response body of the import handler.
+type importResult struct {
+	DryRun bool            `json:"DryRun"`
+	Rows   []csvimport.Row `json:"Rows"`
+	IDs    []int           `json:"IDs"`
+}
+
+// ImportCases imports cases from CSV data. The query parameter map (e.g.
+// map=Rubrum=Mandant) can be given several times to map case fields to CSV
+// columns, the query parameter default sets default values for empty fields.
+// With the query parameter dryrun nothing is saved. If one of the rows is
+// invalid, no case is saved at all.
+func (h CaseHandler) ImportCases() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if mt != "text/csv" {
+				http.Error(w, "Error: Content-Type must be text/csv", http.StatusBadRequest)
+				return
+			}
+
+			q := r.URL.Query()
+			mapping, err := csvimport.ParseAssignments(q["map"])
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid mapping: %v", err), http.StatusBadRequest)
+				return
+			}
+			defaults, err := csvimport.ParseAssignments(q["default"])
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid defaults: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			rows, err := csvimport.Read(r.Body, csvimport.Options{Mapping: mapping, Defaults: defaults})
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: reading CSV data: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			result := importResult{
+				DryRun: q.Has("dryrun"),
+				Rows:   rows,
+				IDs:    []int{},
+			}
+			if result.Rows == nil {
+				result.Rows = []csvimport.Row{}
+			}
+
+			if csvimport.HasErrors(rows) {
+				writeJSON(w, h.Logger, http.StatusBadRequest, result)
+				return
+			}
+
+			if !result.DryRun {
+				for _, row := range rows {
+					id, err := h.Model.AddCase(row.Case)
+					if err != nil {
+						msg := fmt.Sprintf("Error: adding case from line %d: %v", row.Line, err)
+						h.Logger.Printf(msg)
+						http.Error(w, msg, http.StatusInternalServerError)
+						return
+					}
+					result.IDs = append(result.IDs, id)
+				}
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, result)
+		},
+	)
+}
+
+// parseCaseFilter reads the filter for case lists from the query parameters
+// art, stand, gericht, from and to. Dates may be given in ISO or German format.
+func parseCaseFilter(r *http.Request) (lawcase.Filter, error) {
+	q := r.URL.Query()
+	f := lawcase.Filter{
+		Art:     q.Get("art"),
+		Stand:   q.Get("stand"),
+		Gericht: q.Get("gericht"),
+	}
+	if v := q.Get("from"); v != "" {
+		t, err := lawcase.ParseDate(v)
+		if err != nil {
+			return lawcase.Filter{}, fmt.Errorf("query parameter from: %w", err)
+		}
+		f.From = t
+	}
+	if v := q.Get("to"); v != "" {
+		t, err := lawcase.ParseDate(v)
+		if err != nil {
+			return lawcase.Filter{}, fmt.Errorf("query parameter to: %w", err)
+		}
+		f.To = t
+	}
+	return f, nil
+}
+
+// writeJSON marshals the given value and writes it with the given status code
+// to the response.
+func writeJSON(w http.ResponseWriter, logger Logger, code int, v any) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		msg := fmt.Sprintf("Error: marshalling JSON: %v", err)
+		logger.Printf(msg)
+		http.Error(w, msg, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if _, err := w.Write(b); err != nil {
+		logger.Printf("Error: writing response body: %v", err)
+	}
+}
+
 func methodAllowed(method string, fn func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
 	wrapper := func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != method {
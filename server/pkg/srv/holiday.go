@@ -0,0 +1,69 @@
+package srv
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/holiday"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+)
+
+type HolidayHandler struct {
+	Logger Logger
+	Model  *model.Model
+}
+
+func NewHolidayHandler(logger Logger, m *model.Model) *HolidayHandler {
+	return &HolidayHandler{
+		Logger: logger,
+		Model:  m,
+	}
+}
+
+// ServeHTTP returns the public holidays of a year (query parameter year,
+// default the current year) in a Bundesland. The Bundesland is given by the
+// query parameter land or derived from the court of the case given by the
+// query parameter case.
+func (h HolidayHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+			year := time.Now().Year()
+			if v := q.Get("year"); v != "" {
+				y, err := strconv.Atoi(v)
+				if err != nil || y < 1954 || y > 9999 {
+					http.Error(w, fmt.Sprintf("Error: invalid request: query parameter year: invalid value %q", v), http.StatusBadRequest)
+					return
+				}
+				year = y
+			}
+
+			land := q.Get("land")
+			if v := q.Get("case"); v != "" && land == "" {
+				id, err := strconv.Atoi(v)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: query parameter case: invalid value %q", v), http.StatusBadRequest)
+					return
+				}
+				if _, err := h.Model.Case.Retrieve(id); err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+					return
+				}
+				land = h.Model.CaseLand(id)
+			}
+			if land != "" && !holiday.IsLand(land) {
+				http.Error(w, fmt.Sprintf("Error: invalid request: unknown Bundesland %q", land), http.StatusBadRequest)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]any{
+				"Jahr":       year,
+				"Bundesland": land,
+				"Feiertage":  holiday.Holidays(year, land),
+			})
+		},
+	)(w, r)
+}
@@ -0,0 +1,334 @@
+package srv
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/export"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/fee"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/invoice"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+type InvoiceHandler struct {
+	Logger Logger
+	Model  *model.Model
+}
+
+func NewInvoiceHandler(logger Logger, m *model.Model) *InvoiceHandler {
+	return &InvoiceHandler{
+		Logger: logger,
+		Model:  m,
+	}
+}
+
+func (h InvoiceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/retrieve", h.RetrieveInvoices())
+	mux.HandleFunc("/sender", h.RetrieveSender())
+	mux.HandleFunc("/sender/update", h.UpdateSender())
+	mux.HandleFunc("/new", h.NewInvoice())
+	mux.HandleFunc("/cancel", h.CancelInvoice())
+	mux.HandleFunc("/pdf", h.PDF())
+	mux.ServeHTTP(w, r)
+}
+
+// RetrieveInvoices returns all invoices as map from id to invoice. With the
+// query parameter case it returns the list of invoices of this case instead.
+func (h InvoiceHandler) RetrieveInvoices() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			v := r.URL.Query().Get("case")
+			if v == "" {
+				writeJSON(w, h.Logger, http.StatusOK, h.Model.Invoice)
+				return
+			}
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: query parameter case: invalid value %q", v), http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, h.Logger, http.StatusOK, h.Model.Invoice.OfCase(id))
+		},
+	)
+}
+
+// RetrieveSender returns the law firm issuing invoices.
+func (h InvoiceHandler) RetrieveSender() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, h.Logger, http.StatusOK, h.Model.Absender)
+		},
+	)
+}
+
+// UpdateSender replaces the law firm issuing invoices. Issued invoices keep
+// the former values.
+func (h InvoiceHandler) UpdateSender() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var a invoice.Absender
+			if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(a); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+
+			if err := h.Model.Absender.Set(a, h.Model.WriteEvent("InvoiceSender")); err != nil {
+				msg := fmt.Sprintf("Error: setting invoice sender: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+			writeJSON(w, h.Logger, http.StatusOK, h.Model.Absender)
+		},
+	)
+}
+
+type newInvoiceRequest struct {
+	CaseID int `json:"CaseID"`
+
+	// Datum defaults to today.
+	Datum string `json:"Datum"`
+
+	// Empfaenger defaults to the first client of the case.
+	Empfaenger *invoice.Empfaenger `json:"Empfaenger"`
+
+	// Positionen default to the statutory fees of the case proposed by the
+	// fee engine. Pflichtverteidiger selects the fees of court-appointed
+	// counsel for this proposal.
+	Positionen         []invoice.Position `json:"Positionen"`
+	Pflichtverteidiger bool               `json:"Pflichtverteidiger"`
+
+	// Steuersatz defaults to the regular rate of the value added tax.
+	Steuersatz *int `json:"Steuersatz"`
+}
+
+// NewInvoice issues an invoice of a case with the next number of the year.
+func (h InvoiceHandler) NewInvoice() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req newInvoiceRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			inv, err := h.buildInvoice(req)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(inv); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+
+			h.issue(w, inv)
+		},
+	)
+}
+
+// buildInvoice fills the invoice from the request and the case.
+func (h InvoiceHandler) buildInvoice(req newInvoiceRequest) (invoice.Invoice, error) {
+	if h.Model.Absender.Name == "" {
+		return invoice.Invoice{}, fmt.Errorf("the invoice sender is not set")
+	}
+	c, err := h.Model.Case.Retrieve(req.CaseID)
+	if err != nil {
+		return invoice.Invoice{}, err
+	}
+	datum, err := parseInvoiceDate(req.Datum)
+	if err != nil {
+		return invoice.Invoice{}, err
+	}
+
+	inv := invoice.Invoice{
+		CaseID:     req.CaseID,
+		Datum:      datum,
+		Absender:   h.Model.Absender,
+		Positionen: req.Positionen,
+		Steuersatz: fee.Umsatzsteuer,
+	}
+	if req.Steuersatz != nil {
+		inv.Steuersatz = *req.Steuersatz
+	}
+
+	if req.Empfaenger != nil {
+		inv.Empfaenger = *req.Empfaenger
+	} else if ids := c.Mandanten(); len(ids) > 0 {
+		if p, err := h.Model.Person.Retrieve(ids[0]); err == nil {
+			inv.Empfaenger = invoice.Empfaenger{Name: p.FullName(), Anschrift: p.Anschrift}
+		}
+	}
+
+	if len(inv.Positionen) == 0 {
+		v := fee.FromCase(req.CaseID, c, h.Model.Court, h.Model.Proceeding)
+		v.Pflichtverteidiger = req.Pflichtverteidiger
+		vorschlag, err := fee.Propose(v)
+		if err != nil {
+			return invoice.Invoice{}, fmt.Errorf("case %d: %v", req.CaseID, err)
+		}
+		inv.Positionen = feePositions(vorschlag)
+	}
+	return inv, nil
+}
+
+// feePositions converts the proposed fees to items of an invoice. The value
+// added tax (Nr. 7008 VV RVG) is computed by the invoice itself.
+func feePositions(v fee.Vorschlag) []invoice.Position {
+	var result []invoice.Position
+	for _, p := range v.Positionen {
+		art := "Gebuehr"
+		switch p.Nr {
+		case "7008":
+			continue
+		case "7002":
+			art = "Auslage"
+		}
+		bezeichnung := p.Bezeichnung
+		if p.Datum != "" {
+			if t, err := time.Parse(invoice.DateLayout, p.Datum); err == nil {
+				bezeichnung += " am " + t.Format("02.01.2006")
+			}
+		}
+		result = append(result, invoice.Position{Art: art, Nr: p.Nr, Bezeichnung: bezeichnung, Betrag: p.Betrag})
+	}
+	return result
+}
+
+// parseInvoiceDate normalizes the date of an invoice. It defaults to today.
+func parseInvoiceDate(s string) (string, error) {
+	if strings.TrimSpace(s) == "" {
+		return time.Now().Format(invoice.DateLayout), nil
+	}
+	t, err := lawcase.ParseDate(s)
+	if err != nil {
+		return "", fmt.Errorf("Datum: %w", err)
+	}
+	return t.Format(invoice.DateLayout), nil
+}
+
+type cancelInvoiceRequest struct {
+	ID int `json:"ID"`
+
+	// Datum of the cancellation invoice defaults to today.
+	Datum string `json:"Datum"`
+}
+
+// CancelInvoice issues a cancellation invoice for an invoice.
+func (h InvoiceHandler) CancelInvoice() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req cancelInvoiceRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+			datum, err := parseInvoiceDate(req.Datum)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			storno, err := h.Model.Invoice.Cancellation(req.ID, datum)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			h.issue(w, storno)
+		},
+	)
+}
+
+// issue issues the invoice and writes it with its id.
+func (h InvoiceHandler) issue(w http.ResponseWriter, inv invoice.Invoice) {
+	if err := h.Model.Invoice.Check(inv); err != nil {
+		http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+		return
+	}
+	id, err := h.Model.Invoice.Issue(inv, h.Model.WriteEvent("Invoice"))
+	if err != nil {
+		msg := fmt.Sprintf("Error: issuing invoice: %v", err)
+		h.Logger.Printf(msg)
+		http.Error(w, msg, http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, h.Logger, http.StatusOK, invoice.Entry{ID: id, Invoice: h.Model.Invoice[id]})
+}
+
+// PDF returns the invoice with the id given by the query parameter id as PDF.
+func (h InvoiceHandler) PDF() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			v := r.URL.Query().Get("id")
+			id, err := strconv.Atoi(v)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: query parameter id: invalid value %q", v), http.StatusBadRequest)
+				return
+			}
+			inv, err := h.Model.Invoice.Retrieve(id)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			c, _ := h.Model.Case.Retrieve(inv.CaseID)
+			cancelled := ""
+			if inv.Storno != 0 {
+				cancelled = h.Model.Invoice[inv.Storno].Nummer
+			}
+
+			buf := new(bytes.Buffer)
+			if err := export.InvoicePDF(buf, inv, c, cancelled); err != nil {
+				msg := fmt.Sprintf("Error: creating PDF: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", "Rechnung-"+inv.Nummer+".pdf"))
+			if _, err := buf.WriteTo(w); err != nil {
+				msg := fmt.Sprintf("Error: writing response body: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+		},
+	)
+}
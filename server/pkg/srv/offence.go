@@ -0,0 +1,36 @@
+package srv
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/offence"
+)
+
+type OffenceHandler struct {
+	Logger Logger
+}
+
+func NewOffenceHandler(logger Logger) *OffenceHandler {
+	return &OffenceHandler{
+		Logger: logger,
+	}
+}
+
+// ServeHTTP returns the catalog of offences. With the query parameter q it
+// returns only the offences whose citation or description contains q.
+func (h OffenceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			q := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+			result := []offence.Norm{}
+			for _, n := range offence.Catalog() {
+				if q == "" || strings.Contains(strings.ToLower(n.String()), q) {
+					result = append(result, n)
+				}
+			}
+			writeJSON(w, h.Logger, http.StatusOK, result)
+		},
+	)(w, r)
+}
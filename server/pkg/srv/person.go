@@ -0,0 +1,205 @@
+package srv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/conflict"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/person"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/record"
+)
+
+type PersonHandler struct {
+	Logger Logger
+	Model  *model.Model
+}
+
+func NewPersonHandler(logger Logger, m *model.Model) *PersonHandler {
+	return &PersonHandler{
+		Logger: logger,
+		Model:  m,
+	}
+}
+
+func (h PersonHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/retrieve", h.RetrievePersons())
+	mux.HandleFunc("/new", h.NewPerson())
+	mux.HandleFunc("/conflicts", h.Conflicts())
+	mux.HandleFunc("/record", h.Record())
+	mux.ServeHTTP(w, r)
+}
+
+func (h PersonHandler) RetrievePersons() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, h.Logger, http.StatusOK, h.Model.Person)
+		},
+	)
+}
+
+func (h PersonHandler) NewPerson() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			p := person.Person{}
+			if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(p); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+
+			id, err := h.Model.Person.AddPerson(p, h.Model.WriteEvent("Person"))
+			if err != nil {
+				msg := fmt.Sprintf("Error: adding person: %v", err)
+				h.Logger.Printf(msg)
+				http.Error(w, msg, http.StatusInternalServerError)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": id})
+		},
+	)
+}
+
+// Conflicts checks a case for conflicts of interest with all existing cases
+// without saving it. The request body is the case like for a new case. The
+// query parameter case gives the id of an existing case that is skipped, e.g.
+// when checking a changed case.
+func (h PersonHandler) Conflicts() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			c := lawcase.Case{}
+			if err := json.NewDecoder(r.Body).Decode(&c); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+			var self int
+			if v := r.URL.Query().Get("case"); v != "" {
+				id, err := strconv.Atoi(v)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: query parameter case: invalid value %q", v), http.StatusBadRequest)
+					return
+				}
+				self = id
+			}
+
+			conflicts := conflict.Check(h.Model.Case, h.Model.Person, c, self)
+			if conflicts == nil {
+				conflicts = []conflict.Conflict{}
+			}
+			writeJSON(w, h.Logger, http.StatusOK, map[string][]conflict.Conflict{"Conflicts": conflicts})
+		},
+	)
+}
+
+type recordRequest struct {
+	// Person adds the convictions of all cases where the person is our
+	// client, Cases the convictions of the given cases.
+	Person int   `json:"Person"`
+	Cases  []int `json:"Cases"`
+
+	Verurteilungen []record.Verurteilung `json:"Verurteilungen" validate:"dive"`
+}
+
+// Record computes when the convictions of a client are no longer included in
+// a certificate of good conduct and erased from the Bundeszentralregister.
+// The convictions are taken from the outcome of cases or given manually. The
+// query parameter date replaces today.
+func (h PersonHandler) Record() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req recordRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+			today, err := parseToday(r)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			for i := range req.Verurteilungen {
+				t, err := lawcase.ParseDate(req.Verurteilungen[i].Datum)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: field Datum: %v", err), http.StatusBadRequest)
+					return
+				}
+				req.Verurteilungen[i].Datum = t.Format(record.DateLayout)
+			}
+
+			v := validator.New()
+			if err := v.Struct(req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+
+			ids := append([]int{}, req.Cases...)
+			if req.Person != 0 {
+				if _, err := h.Model.Person.Retrieve(req.Person); err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+					return
+				}
+				var own []int
+				for id, c := range h.Model.Case {
+					if containsInt(c.Mandanten(), req.Person) && !containsInt(ids, id) {
+						own = append(own, id)
+					}
+				}
+				sort.Ints(own)
+				ids = append(ids, own...)
+			}
+
+			vs := req.Verurteilungen
+			for i, id := range ids {
+				c, err := h.Model.Case.Retrieve(id)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+					return
+				}
+				if v, ok := record.FromCase(id, c); ok {
+					vs = append(vs, v)
+				} else if i < len(req.Cases) {
+					http.Error(w, fmt.Sprintf("Error: invalid request: case %d did not end with a conviction", id), http.StatusBadRequest)
+					return
+				}
+			}
+
+			res, err := record.Compute(vs, today)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			writeJSON(w, h.Logger, http.StatusOK, res)
+		},
+	)
+}
@@ -0,0 +1,128 @@
+package srv
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/proceeding"
+)
+
+type ProceedingHandler struct {
+	Logger Logger
+	Model  *model.Model
+}
+
+func NewProceedingHandler(logger Logger, m *model.Model) *ProceedingHandler {
+	return &ProceedingHandler{
+		Logger: logger,
+		Model:  m,
+	}
+}
+
+func (h ProceedingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/retrieve", h.RetrieveProceedings())
+	mux.HandleFunc("/new", h.NewProceeding())
+	mux.HandleFunc("/link", h.Link())
+	mux.ServeHTTP(w, r)
+}
+
+func (h ProceedingHandler) RetrieveProceedings() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, h.Logger, http.StatusOK, h.Model.Proceeding)
+		},
+	)
+}
+
+// NewProceeding adds a new proceeding. All referenced cases must exist and
+// must not belong to another proceeding.
+func (h ProceedingHandler) NewProceeding() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			p := proceeding.Proceeding{}
+			if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(p); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+			for _, i := range p.Instanzen {
+				if _, err := h.Model.Case.Retrieve(i.CaseID); err != nil {
+					http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+					return
+				}
+			}
+
+			id, err := h.Model.Proceeding.AddProceeding(p, h.Model.WriteEvent("Proceeding"))
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": id})
+		},
+	)
+}
+
+// linkRequest links a case to a proceeding. Position is the zero based index
+// in the list of instances. Without position the case is appended.
+type linkRequest struct {
+	Proceeding int  `json:"Proceeding"`
+	Position   *int `json:"Position"`
+	proceeding.Instanz
+}
+
+// Link adds an existing case as instance to a proceeding.
+func (h ProceedingHandler) Link() func(http.ResponseWriter, *http.Request) {
+	return methodAllowed(
+		http.MethodPost,
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Content-Type") != "application/json" {
+				http.Error(w, "Error: Content-Type must be application/json", http.StatusBadRequest)
+				return
+			}
+
+			var req linkRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("Error: decoding request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			v := validator.New()
+			if err := v.Struct(req.Instanz); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request:\n%v", err), http.StatusBadRequest)
+				return
+			}
+			if _, err := h.Model.Case.Retrieve(req.CaseID); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+			position := -1
+			if req.Position != nil {
+				position = *req.Position
+			}
+
+			if err := h.Model.Proceeding.Link(req.Proceeding, position, req.Instanz, h.Model.WriteEvent("Proceeding")); err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, map[string]int{"id": req.Proceeding})
+		},
+	)
+}
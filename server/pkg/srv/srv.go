@@ -10,6 +10,7 @@ import (
 	"os"
 	"os/signal"
 
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/fao"
 	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
 	"github.com/normanjaeckel/fao-strafrecht/server/pkg/public"
 	"golang.org/x/sys/unix"
@@ -27,6 +28,7 @@ type LoggerWithFatal interface {
 type Environment interface {
 	Host() string
 	Port() string
+	MergedCases() string
 }
 
 const APIPrefix = "api"
@@ -43,14 +45,17 @@ func Run(logger LoggerWithFatal, env Environment, m *model.Model) error {
 	}()
 
 	addr := fmt.Sprintf("%s:%s", env.Host(), env.Port())
-	if err := Start(ctx, logger, m, addr); err != nil {
+	o := fao.Options{MergedAsOne: env.MergedCases() != "several"}
+	if err := Start(ctx, logger, m, addr, o); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func Handler(logger Logger, m *model.Model) http.Handler {
+// Handler returns the handler for all routes. The FAO options are the default
+// for the FAO report.
+func Handler(logger Logger, m *model.Model, o fao.Options) http.Handler {
 	mux := http.NewServeMux()
 
 	// // Model case
@@ -58,6 +63,46 @@ func Handler(logger Logger, m *model.Model) http.Handler {
 	h := NewCaseHandler(logger, m)
 	mux.Handle(p+"/", http.StripPrefix(p, h))
 
+	// Model court
+	p = "/" + APIPrefix + "/" + "court"
+	mux.Handle(p+"/", http.StripPrefix(p, NewCourtHandler(logger, m)))
+
+	// Model proceeding
+	p = "/" + APIPrefix + "/" + "proceeding"
+	mux.Handle(p+"/", http.StripPrefix(p, NewProceedingHandler(logger, m)))
+
+	// Model person
+	p = "/" + APIPrefix + "/" + "person"
+	mux.Handle(p+"/", http.StripPrefix(p, NewPersonHandler(logger, m)))
+
+	// Model deadline
+	p = "/" + APIPrefix + "/" + "deadline"
+	mux.Handle(p+"/", http.StripPrefix(p, NewDeadlineHandler(logger, m)))
+
+	// Model appointment
+	p = "/" + APIPrefix + "/" + "appointment"
+	mux.Handle(p+"/", http.StripPrefix(p, NewAppointmentHandler(logger, m)))
+
+	// Model invoice
+	p = "/" + APIPrefix + "/" + "invoice"
+	mux.Handle(p+"/", http.StripPrefix(p, NewInvoiceHandler(logger, m)))
+
+	// Export
+	p = "/" + APIPrefix + "/" + "export"
+	mux.Handle(p+"/", http.StripPrefix(p, NewExportHandler(logger, m)))
+
+	// Statistics
+	mux.Handle("/"+APIPrefix+"/"+"stats", NewStatsHandler(logger, m))
+
+	// Public holidays
+	mux.Handle("/"+APIPrefix+"/"+"holiday", NewHolidayHandler(logger, m))
+
+	// Catalog of offences
+	mux.Handle("/"+APIPrefix+"/"+"offence", NewOffenceHandler(logger))
+
+	// FAO report
+	mux.Handle("/"+APIPrefix+"/"+"fao", NewFAOHandler(logger, m, o))
+
 	// Root
 	mux.Handle("/", public.Files())
 
@@ -66,10 +111,10 @@ func Handler(logger Logger, m *model.Model) http.Handler {
 
 // Start starts the server. It blocks and returns an error if the server was not shut down
 // gracefully.
-func Start(ctx context.Context, logger Logger, m *model.Model, addr string) error {
+func Start(ctx context.Context, logger Logger, m *model.Model, addr string, o fao.Options) error {
 	s := &http.Server{
 		Addr:    addr,
-		Handler: Handler(logger, m),
+		Handler: Handler(logger, m, o),
 	}
 
 	go func() {
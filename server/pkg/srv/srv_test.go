@@ -3,6 +3,7 @@ package srv_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -12,7 +13,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/fao"
 	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/deadline"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/invoice"
 	"github.com/normanjaeckel/fao-strafrecht/server/pkg/srv"
 	"github.com/normanjaeckel/fao-strafrecht/server/pkg/testutils"
 )
@@ -32,7 +36,7 @@ func TestStart(t *testing.T) {
 	ch := make(chan error, 1)
 
 	go func() {
-		ch <- srv.Start(ctx, logger, model, ":8080")
+		ch <- srv.Start(ctx, logger, model, ":8080", fao.Options{})
 	}()
 	cancel()
 
@@ -116,6 +120,44 @@ func TestRetrieveCaseHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("test retrieve page of cases", func(t *testing.T) {
+		for _, c := range []string{
+			`{"Rubrum":"Zander","Beginn":"2021-01-01","Stand":"laufend","Art":"Verteidiger"}`,
+			`{"Rubrum":"Ärger","Beginn":"2019-05-01","Stand":"laufend","Art":"Verteidiger"}`,
+			`{"Rubrum":"Becker","Beginn":"2020-03-02","Stand":"laufend","Art":"Nebenkläger"}`,
+		} {
+			res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(c))
+			if err != nil {
+				t.Fatalf("issuing POST request: %v", err)
+			}
+			checkOK(t, res)
+		}
+
+		res, err := http.Get(ts.URL + path + "?art=Verteidiger&sort=Rubrum&limit=1")
+		if err != nil {
+			t.Fatalf("issuing GET request to %q: %v", path, err)
+		}
+
+		respBody := checkOK(t, res)
+
+		expected := `{"Total":2,"Cases":[{"ID":2,"Rubrum":"Ärger","Az":"","Gericht":"","Beginn":"2019-05-01","Ende":"","Gegenstand":"","Art":"Verteidiger","Beschreibung":"","Stand":"laufend"}],"NextCursor":"eyJWIjoiw4RyZ2VyIiwiSUQiOjJ9"}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		res, err = http.Get(ts.URL + path + "?sort=Unknown")
+		if err != nil {
+			t.Fatalf("issuing GET request to %q: %v", path, err)
+		}
+
+		respBody = checkBadRequest(t, res)
+
+		expected = "Error: invalid request: unknown sort field \"Unknown\"\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
 	// TODO: Add test to retrieve one case
 }
 
@@ -174,6 +216,47 @@ func TestNewCaseHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("POST request with unrecognized Az", func(t *testing.T) {
+		reqBody := []byte(`{"Rubrum": "test_rubrum_Oohae3ie", "Az": "000234/2022", "Beginn": "2022-01-01","Stand":"laufend","Art":"Verteidiger"}`)
+
+		res, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+
+		respBody := checkOK(t, res)
+
+		expected := `{"id":2,"warnings":["Az: unrecognized case number \"000234/2022\""]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("POST request with Az to normalize", func(t *testing.T) {
+		reqBody := []byte(`{"Rubrum": "test_rubrum_ieH5ahqu", "Az": "123  Js 4567/2025", "Beginn": "2022-01-01","Stand":"laufend","Art":"Verteidiger"}`)
+
+		res, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+
+		respBody := checkOK(t, res)
+
+		expected := `{"id":3}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		gotEventstore, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("reading eventstore file: %v", err)
+		}
+		expectedAz := `"Az":"123 Js 4567/25"`
+		if !bytes.Contains(gotEventstore, []byte(expectedAz)) {
+			t.Fatalf("eventstore does not contain normalized Az %q: %q", expectedAz, gotEventstore)
+		}
+	})
+
 	t.Run("invalid request, invalid JSON", func(t *testing.T) {
 		reqBody := []byte(`invalid`)
 
@@ -209,7 +292,7 @@ func TestNewCaseHandler(t *testing.T) {
 		expected := "Error: invalid request:\n" +
 			"Key: 'Case.Rubrum' Error:Field validation for 'Rubrum' failed on the 'required' tag\n" +
 			"Key: 'Case.Beginn' Error:Field validation for 'Beginn' failed on the 'required' tag\n" +
-			"Key: 'Case.Art' Error:Field validation for 'Art' failed on the 'oneof' tag\n" +
+			"Key: 'Case.Art' Error:Field validation for 'Art' failed on the 'rolle' tag\n" +
 			"Key: 'Case.Stand' Error:Field validation for 'Stand' failed on the 'required' tag\n"
 		if string(respBody) != expected {
 			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
@@ -233,7 +316,7 @@ func TestNewCaseHandler(t *testing.T) {
 		respBody := checkBadRequest(t, res)
 
 		expected := "Error: invalid request:\n" +
-			"Key: 'Case.Art' Error:Field validation for 'Art' failed on the 'oneof' tag\n"
+			"Key: 'Case.Art' Error:Field validation for 'Art' failed on the 'rolle' tag\n"
 		if string(respBody) != expected {
 			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
 		}
@@ -245,6 +328,1582 @@ func TestNewCaseHandler(t *testing.T) {
 		}
 	})
 
+	t.Run("invalid request, case with Abschluss", func(t *testing.T) {
+		reqBody := []byte(`{"Rubrum":"test_rubrum_Eiph4ahx","Beginn":"2022-01-01","Stand":"laufend","Art":"Verteidiger","Abschluss":{"ID":4,"Datum":"2022-05-01","Ergebnis":"Freispruch"}}`)
+
+		res, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+
+		respBody := checkBadRequest(t, res)
+
+		expected := "Error: invalid request: Abschluss: a new case can not be closed, use /api/case/close\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("invalid request, case with join", func(t *testing.T) {
+		reqBody := []byte(`{"Rubrum":"test_rubrum_Ahng5ooT","Beginn":"2022-01-01","Stand":"laufend","Art":"Verteidiger","VerbundenIn":{"CaseID":1,"Datum":"2022-05-01"}}`)
+
+		res, err := http.Post(ts.URL+path, "application/json", bytes.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+
+		respBody := checkBadRequest(t, res)
+
+		expected := "Error: invalid request: Verbunden, VerbundenIn, Abgetrennt and AbgetrenntVon must be empty, use /api/case/merge and /api/case/split\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+}
+
+func TestSearchCasesHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	path := "/api/case/search"
+
+	reqBody := `{"Rubrum":"Müller","Beginn":"2019-01-02","Stand":"laufend","Art":"Verteidiger","Gegenstand":"Betrug u.a."}`
+	res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(reqBody))
+	if err != nil {
+		t.Fatalf("issuing POST request: %v", err)
+	}
+	checkOK(t, res)
+
+	t.Run("search", func(t *testing.T) {
+		res, err := http.Get(ts.URL + path + "?q=mueller+betr")
+		if err != nil {
+			t.Fatalf("issuing GET request to %q: %v", path, err)
+		}
+
+		respBody := checkOK(t, res)
+
+		expected := `{"Results":[{"ID":1,"Score":2.773,"Snippets":[{"Field":"Gegenstand","Text":"\u003cmark\u003eBetrug\u003c/mark\u003e u.a."},{"Field":"Rubrum","Text":"\u003cmark\u003eMüller\u003c/mark\u003e"}],"Case":{"Rubrum":"Müller","Az":"","Gericht":"","Beginn":"2019-01-02","Ende":"","Gegenstand":"Betrug u.a.","Art":"Verteidiger","Beschreibung":"","Stand":"laufend"}}]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("missing query", func(t *testing.T) {
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("issuing GET request to %q: %v", path, err)
+		}
+
+		respBody := checkBadRequest(t, res)
+
+		expected := "Error: query parameter q is required\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestStatsHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	for _, c := range []string{
+		`{"Rubrum":"A","Beginn":"2017-01-02","Ende":"2018-01-01","Stand":"abgeschlossen","Art":"Verteidiger","Gericht":"LG Leipzig"}`,
+		`{"Rubrum":"B","Beginn":"2021-06-01","Ende":"2021-06-11","Stand":"abgeschlossen","Art":"Nebenkläger","Gericht":"AG Leipzig"}`,
+	} {
+		res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(c))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		checkOK(t, res)
+	}
+
+	path := "/api/stats?fao=2022-01-01"
+
+	res, err := http.Get(ts.URL + path)
+	if err != nil {
+		t.Fatalf("issuing GET request to %q: %v", path, err)
+	}
+
+	respBody := checkOK(t, res)
+
+	expected := `{"Total":1,"PerYear":{"2021":1},"PerArt":{"Nebenkläger":1},"PerGericht":{"AG Leipzig":1},"PerStand":{"abgeschlossen":1},"PerTatvorwurf":{},"PerErgebnis":{},"ErgebnisPerTatvorwurf":{},"ErgebnisPerGericht":{},"AverageDurationDays":10,"DurationCases":1,"HearingIntensive":0,"HearingIntensiveShare":0}`
+	if string(respBody) != expected {
+		t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+	}
+}
+
+func TestCourtHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	t.Run("new court", func(t *testing.T) {
+		reqBody := `{"Name":"Amtsgericht Leipzig","Ort":"Leipzig","Instanz":"AG","Spruchkoerper":["Strafrichter","Schöffengericht"],"Bundesland":"SN"}`
+		res, err := http.Post(ts.URL+"/api/court/new", "application/json", strings.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+
+		respBody := checkOK(t, res)
+
+		expected := `{"id":1}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("invalid court", func(t *testing.T) {
+		reqBody := `{"Name":"Amtsgericht Leipzig","Ort":"Leipzig","Instanz":"Amtsgericht","Bundesland":"SN"}`
+		res, err := http.Post(ts.URL+"/api/court/new", "application/json", strings.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+
+		respBody := checkBadRequest(t, res)
+
+		expected := "Error: invalid request:\n" +
+			"Key: 'Court.Instanz' Error:Field validation for 'Instanz' failed on the 'oneof' tag\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("case with unknown court", func(t *testing.T) {
+		reqBody := `{"Rubrum":"A","Beginn":"2021-06-01","Stand":"laufend","Art":"Verteidiger","GerichtID":2}`
+		res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+
+		respBody := checkBadRequest(t, res)
+
+		expected := "Error: invalid request: court 2 does not exist\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("suggestions and assign", func(t *testing.T) {
+		reqBody := `{"Rubrum":"A","Beginn":"2021-06-01","Stand":"laufend","Art":"Verteidiger","Gericht":"AG Leipzig 5 Ls 12/22"}`
+		res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		checkOK(t, res)
+
+		res, err = http.Get(ts.URL + "/api/court/suggestions")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+
+		respBody := checkOK(t, res)
+
+		expected := `[{"CaseID":1,"Gericht":"AG Leipzig 5 Ls 12/22","Suggestions":[{"CourtID":1,"Score":1}]}]`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		res, err = http.Post(ts.URL+"/api/court/assign", "application/json", strings.NewReader(`{"Case":1,"Court":1}`))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		checkOK(t, res)
+
+		res, err = http.Get(ts.URL + "/api/court/suggestions")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+
+		respBody = checkOK(t, res)
+
+		expected = `[]`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestChangeAzHandler(t *testing.T) {
+	logger := log.Default()
+	ts, filename, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	path := "/api/case/az"
+
+	reqBody := `{"Rubrum":"A","Beginn":"2025-01-01","Stand":"laufend","Art":"Verteidiger"}`
+	res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(reqBody))
+	if err != nil {
+		t.Fatalf("issuing POST request: %v", err)
+	}
+	checkOK(t, res)
+
+	t.Run("change case number", func(t *testing.T) {
+		reqBody := `{"ID":1,"Behoerde":"Amtsgericht","Nummer":"123 Js 4567/2025","Datum":"01.02.2025"}`
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+
+		respBody := checkOK(t, res)
+
+		expected := `{"id":1,"warnings":["Nummer: register sign Js does not belong to Amtsgericht"]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		gotEventstore, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("reading eventstore file: %v", err)
+		}
+		expectedEvent := `{"Name":"CaseAz","Data":{"ID":1,"Behoerde":"Amtsgericht","Nummer":"123 Js 4567/25","Datum":"2025-02-01"}}`
+		if !bytes.Contains(gotEventstore, []byte(expectedEvent)) {
+			t.Fatalf("eventstore does not contain %q: %q", expectedEvent, gotEventstore)
+		}
+	})
+
+	t.Run("search by case number", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/api/case/search?q=123Js4567/25")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+
+		respBody := checkOK(t, res)
+
+		expected := `{"Results":[{"ID":1,`
+		if !strings.HasPrefix(string(respBody), expected) {
+			t.Fatalf("wrong response body: expected prefix %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("unknown case", func(t *testing.T) {
+		reqBody := `{"ID":2,"Behoerde":"Amtsgericht","Nummer":"5 Ls 12/26","Datum":"2026-01-01"}`
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+
+		respBody := checkBadRequest(t, res)
+
+		expected := "Error: invalid request: case 2 does not exist\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestImportCasesHandler(t *testing.T) {
+	logger := log.Default()
+	ts, filename, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	path := "/api/case/import"
+	csvData := "Mandant;Datum;Rolle;Stand\n" +
+		"Müller;01.02.2019;Pflichtverteidiger;abgeschlossen\n"
+	query := "?map=Rubrum=Mandant&map=Beginn=Datum&map=Art=Rolle"
+
+	t.Run("wrong content type", func(t *testing.T) {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(csvData))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+
+		respBody := checkBadRequest(t, res)
+
+		expected := "Error: Content-Type must be text/csv\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		res, err := http.Post(ts.URL+path+query+"&dryrun", "text/csv", strings.NewReader(csvData))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+
+		respBody := checkOK(t, res)
+
+		expected := `{"DryRun":true,"Rows":[{"Line":2,"Case":{"Rubrum":"Müller","Az":"","Gericht":"","Beginn":"2019-02-01","Ende":"","Gegenstand":"","Art":"Verteidiger","Beschreibung":"","Stand":"abgeschlossen"},"Errors":[]}],"IDs":[]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		gotEventstore, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("reading eventstore file: %v", err)
+		}
+		if len(gotEventstore) != 0 {
+			t.Fatalf("dry run must not write events, got %q", gotEventstore)
+		}
+	})
+
+	t.Run("invalid row", func(t *testing.T) {
+		res, err := http.Post(ts.URL+path+query, "text/csv", strings.NewReader(csvData+"Schulze;gestern;Verteidiger;laufend\n"))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+
+		respBody := checkBadRequest(t, res)
+
+		expected := `"Line":3,"Case":{"Rubrum":"Schulze","Az":"","Gericht":"","Beginn":"gestern","Ende":"","Gegenstand":"","Art":"Verteidiger","Beschreibung":"","Stand":"laufend"},"Errors":["field Beginn: invalid date \"gestern\""]}],"IDs":[]}`
+		if !strings.HasSuffix(string(respBody), expected) {
+			t.Fatalf("wrong response body: expected suffix %q, got %q", expected, string(respBody))
+		}
+
+		gotEventstore, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("reading eventstore file: %v", err)
+		}
+		if len(gotEventstore) != 0 {
+			t.Fatalf("invalid import must not write events, got %q", gotEventstore)
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		res, err := http.Post(ts.URL+path+query, "text/csv; charset=utf-8", strings.NewReader(csvData))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+
+		respBody := checkOK(t, res)
+
+		expected := `"IDs":[1]}`
+		if !strings.HasSuffix(string(respBody), expected) {
+			t.Fatalf("wrong response body: expected suffix %q, got %q", expected, string(respBody))
+		}
+
+		gotEventstore, err := ioutil.ReadFile(filename)
+		if err != nil {
+			t.Fatalf("reading eventstore file: %v", err)
+		}
+		expectedEventstore := []byte(fmt.Sprintf(
+			`{"Event":{"Name":"Case","Data":{"ID":1,"Fields":{"Rubrum":"Müller","Az":"","Gericht":"","Beginn":"2019-02-01","Ende":"","Gegenstand":"","Art":"Verteidiger","Beschreibung":"","Stand":"abgeschlossen"}}},"Timestamp":%d}`, time.Now().Unix(),
+		))
+		expectedEventstore = append(expectedEventstore, '\n')
+		if !bytes.Equal(expectedEventstore, gotEventstore) {
+			t.Fatalf("wrong content of eventstore: expected %q, got %q", expectedEventstore, gotEventstore)
+		}
+	})
+}
+
+func TestProceedingHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	for _, c := range []string{
+		`{"Rubrum":"A","Beginn":"2021-01-04","Stand":"abgeschlossen","Art":"Verteidiger","Gericht":"AG Leipzig, Schöffengericht"}`,
+		`{"Rubrum":"A","Beginn":"2021-09-01","Stand":"abgeschlossen","Art":"Verteidiger","Gericht":"LG Leipzig"}`,
+	} {
+		res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(c))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		checkOK(t, res)
+	}
+
+	post := func(path string, body string) *http.Response {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		return res
+	}
+
+	t.Run("new proceeding and link", func(t *testing.T) {
+		respBody := checkOK(t, post("/api/proceeding/new", `{"Bezeichnung":"A wegen Betrugs","Instanzen":[{"CaseID":1,"Stufe":"Erstinstanz","Ergebnis":"Verurteilung"}]}`))
+		if expected := `{"id":1}`; string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		checkOK(t, post("/api/proceeding/link", `{"Proceeding":1,"CaseID":2,"Stufe":"Berufung"}`))
+
+		res, err := http.Get(ts.URL + "/api/proceeding/retrieve")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody = checkOK(t, res)
+		expected := `{"1":{"Bezeichnung":"A wegen Betrugs","Instanzen":[{"CaseID":1,"Stufe":"Erstinstanz","Ergebnis":"Verurteilung"},{"CaseID":2,"Stufe":"Berufung","Ergebnis":""}]}}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("link case twice", func(t *testing.T) {
+		respBody := checkBadRequest(t, post("/api/proceeding/link", `{"Proceeding":1,"CaseID":2,"Stufe":"Revision"}`))
+		expected := "Error: invalid request: case 2 is used twice\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("link unknown case", func(t *testing.T) {
+		respBody := checkBadRequest(t, post("/api/proceeding/link", `{"Proceeding":1,"CaseID":3,"Stufe":"Revision"}`))
+		expected := "Error: invalid request: case 3 does not exist\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("hearing days and FAO report", func(t *testing.T) {
+		for _, body := range []string{
+			`{"Case":1,"Datum":"03.05.2021"}`,
+			`{"Case":1,"Datum":"2021-05-03"}`,
+			`{"Case":2,"Datum":"2021-11-02"}`,
+		} {
+			checkOK(t, post("/api/case/hearing", body))
+		}
+
+		res, err := http.Get(ts.URL + "/api/fao?fao=2022-01-01")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody := checkOK(t, res)
+		expected := `{"Faelle":1,"Hauptverhandlungstage":2,"Verfahren":[{"ProceedingID":1,"Bezeichnung":"A wegen Betrugs","Instanzen":[` +
+			`{"CaseID":1,"Stufe":"Erstinstanz","Gericht":"AG Leipzig","Ergebnis":"Verurteilung","Hauptverhandlungstage":1,"Qualifiziert":true},` +
+			`{"CaseID":2,"Stufe":"Berufung","Gericht":"LG Leipzig","Hauptverhandlungstage":1,"Qualifiziert":true}]}]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestMergeSplitCaseHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	post := func(path string, body string) *http.Response {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		return res
+	}
+
+	for _, c := range []string{
+		`{"Rubrum":"A","Beginn":"2021-01-04","Stand":"laufend","Art":"Verteidiger","Gericht":"LG Leipzig"}`,
+		`{"Rubrum":"B","Beginn":"2021-02-04","Stand":"laufend","Art":"Verteidiger","Gericht":"LG Leipzig"}`,
+	} {
+		checkOK(t, post("/api/case/new", c))
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		checkOK(t, post("/api/case/merge", `{"ID":1,"From":2,"Datum":"01.03.2021"}`))
+
+		respBody := checkBadRequest(t, post("/api/case/merge", `{"ID":1,"From":2,"Datum":"01.03.2021"}`))
+		expected := "Error: invalid request: case 2 is already joined to case 1\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("FAO counting rule", func(t *testing.T) {
+		for path, expected := range map[string]string{
+			"/api/fao":                `"Faelle":1`,
+			"/api/fao?merged=several": `"Faelle":2`,
+		} {
+			res, err := http.Get(ts.URL + path)
+			if err != nil {
+				t.Fatalf("issuing GET request to %q: %v", path, err)
+			}
+			respBody := checkOK(t, res)
+			if !strings.HasPrefix(string(respBody), "{"+expected) {
+				t.Fatalf("wrong response body for %q: expected prefix %q, got %q", path, expected, string(respBody))
+			}
+		}
+	})
+
+	t.Run("split", func(t *testing.T) {
+		respBody := checkOK(t, post("/api/case/split", `{"ID":1,"Datum":"2021-06-01","Fields":{"Rubrum":"A II","Beginn":"2021-06-01","Stand":"laufend","Art":"Verteidiger"}}`))
+		expected := `{"id":3}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		respBody = checkBadRequest(t, post("/api/case/split", `{"ID":1,"Datum":"2021-06-01","Fields":{"Beginn":"2021-06-01","Stand":"laufend","Art":"Verteidiger"}}`))
+		expected = "Error: invalid request:\n" +
+			"Key: 'Split.Fields.Rubrum' Error:Field validation for 'Rubrum' failed on the 'required' tag\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestRolesHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	t.Run("retrieve catalog", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/api/case/roles")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody := checkOK(t, res)
+		if !strings.Contains(string(respBody), `{"Name":"Vernehmungsbeistand",`) {
+			t.Fatalf("wrong response body: role Vernehmungsbeistand missing in %q", string(respBody))
+		}
+	})
+
+	t.Run("case with several roles", func(t *testing.T) {
+		reqBody := `{"Rubrum":"A","Beginn":"2021-06-01","Stand":"laufend","Art":"Nebenkläger","Rollen":["Adhäsionskläger"]}`
+		res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		checkOK(t, res)
+
+		res, err = http.Get(ts.URL + "/api/case/retrieve?art=Adh%C3%A4sionskl%C3%A4ger")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody := checkOK(t, res)
+		if !strings.HasPrefix(string(respBody), `{"Total":1,`) {
+			t.Fatalf("wrong response body: expected one case, got %q", string(respBody))
+		}
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		reqBody := `{"Rubrum":"A","Beginn":"2021-06-01","Stand":"laufend","Art":"Nebenkläger","Rollen":["Sachverständiger"]}`
+		res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(reqBody))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		respBody := checkBadRequest(t, res)
+		expected := "Error: invalid request:\n" +
+			"Key: 'Case.Rollen[0]' Error:Field validation for 'Rollen[0]' failed on the 'rolle' tag\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestPersonHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	post := func(path string, body string) *http.Response {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		return res
+	}
+
+	for _, p := range []string{`{"Name":"A"}`, `{"Name":"V"}`} {
+		checkOK(t, post("/api/person/new", p))
+	}
+
+	t.Run("invalid person", func(t *testing.T) {
+		respBody := checkBadRequest(t, post("/api/person/new", `{"Vorname":"X"}`))
+		expected := "Error: invalid request:\n" +
+			"Key: 'Person.Name' Error:Field validation for 'Name' failed on the 'required' tag\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	defence := `{"Rubrum":"A","Beginn":"2021-06-01","Stand":"laufend","Art":"Verteidiger","Beteiligte":[{"PersonID":1,"Rolle":"Mandant"}]}`
+	respBody := checkOK(t, post("/api/case/new", defence))
+	if expected := `{"id":1}`; string(respBody) != expected {
+		t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+	}
+
+	nebenklage := `{"Rubrum":"V","Beginn":"2021-07-01","Stand":"laufend","Art":"Nebenkläger","Beteiligte":[{"PersonID":2,"Rolle":"Mandant"},{"PersonID":1,"Rolle":"Beschuldigter"}]}`
+
+	t.Run("standalone check", func(t *testing.T) {
+		respBody := checkOK(t, post("/api/person/conflicts", nebenklage))
+		expected := `{"Conflicts":[{"PersonID":1,"CaseID":1,"Hinweis":"A is our client in case 1 (A) but on the opposite side in this case"}]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("warning in new case", func(t *testing.T) {
+		respBody := checkOK(t, post("/api/case/new", nebenklage))
+		expected := `{"id":2,"warnings":["Beteiligte: possible conflict of interest: A is our client in case 1 (A) but on the opposite side in this case"]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("add party to existing case", func(t *testing.T) {
+		respBody := checkOK(t, post("/api/case/party", `{"Case":1,"PersonID":2,"Rolle":"Verletzter"}`))
+		expected := `{"id":1,"warnings":["Beteiligte: possible conflict of interest: V is our client in case 2 (V) but on the opposite side in this case",` +
+			`"Beteiligte: possible conflict of interest: A is our client in this case but on the opposite side in case 2 (V)"]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("unknown person", func(t *testing.T) {
+		respBody := checkBadRequest(t, post("/api/case/party", `{"Case":1,"PersonID":5,"Rolle":"Zeuge"}`))
+		expected := "Error: invalid request: person 5 does not exist\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestDeadlineHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	post := func(path string, body string) *http.Response {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		return res
+	}
+	get := func(path string) *http.Response {
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("issuing GET request to %q: %v", path, err)
+		}
+		return res
+	}
+
+	checkOK(t, post("/api/court/new", `{"Name":"Amtsgericht Leipzig","Ort":"Leipzig","Instanz":"AG","Bundesland":"SN"}`))
+	checkOK(t, post("/api/case/new", `{"Rubrum":"A","Beginn":"2025-06-01","Stand":"laufend","Art":"Verteidiger","GerichtID":1}`))
+
+	t.Run("calculate", func(t *testing.T) {
+		respBody := checkOK(t, get("/api/deadline/calculate?art=Einspruch&ereignis=17.10.2025&case=1"))
+		expected := `{"Art":"Einspruch","Ereignis":"2025-10-17","Bundesland":"SN","Ende":"2025-11-03"}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		respBody = checkBadRequest(t, get("/api/deadline/calculate?art=Klage&ereignis=2025-10-17"))
+		expected = "Error: invalid request: unknown deadline \"Klage\"\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("new deadlines", func(t *testing.T) {
+		respBody := checkOK(t, post("/api/deadline/new", `{"CaseID":1,"Art":"Einspruch","Ereignis":"17.10.2025"}`))
+		expected := `{"ID":1,"CaseID":1,"Art":"Einspruch","Bezeichnung":"","Ereignis":"2025-10-17","Ende":"2025-11-03","Bundesland":"SN"}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		checkOK(t, post("/api/deadline/new", `{"CaseID":1,"Art":"Stellungnahme","Ende":"2025-10-20"}`))
+
+		respBody = checkBadRequest(t, post("/api/deadline/new", `{"CaseID":2,"Art":"Einspruch","Ereignis":"17.10.2025"}`))
+		expected = "Error: invalid request: case 2 does not exist\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("upcoming and overdue", func(t *testing.T) {
+		respBody := checkOK(t, get("/api/deadline/upcoming?date=2025-10-25&days=10"))
+		if !strings.HasPrefix(string(respBody), `[{"ID":1,`) || strings.Contains(string(respBody), `"ID":2`) {
+			t.Fatalf("wrong upcoming deadlines: %q", string(respBody))
+		}
+
+		respBody = checkOK(t, get("/api/deadline/overdue?date=2025-10-25"))
+		if !strings.HasPrefix(string(respBody), `[{"ID":2,`) || strings.Contains(string(respBody), `"ID":1`) {
+			t.Fatalf("wrong overdue deadlines: %q", string(respBody))
+		}
+	})
+
+	t.Run("done", func(t *testing.T) {
+		checkOK(t, post("/api/deadline/done", `{"ID":2,"Datum":"2025-10-26"}`))
+
+		respBody := checkOK(t, get("/api/deadline/overdue?date=2025-10-25"))
+		if expected := "[]"; string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		respBody = checkBadRequest(t, post("/api/deadline/done", `{"ID":2}`))
+		expected := "Error: invalid request: deadline 2 is already done\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestCustodyHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	post := func(path string, body string) *http.Response {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		return res
+	}
+
+	checkOK(t, post("/api/case/new", `{"Rubrum":"A","Beginn":"2025-01-10","Stand":"laufend","Art":"Verteidiger"}`))
+	checkOK(t, post("/api/case/new", `{"Rubrum":"B","Beginn":"2025-05-02","Stand":"laufend","Art":"Verteidiger","Haft":{"Festnahme":"2025-05-02","Anstalt":"JVA Leipzig","Haftbefehl":"Untersuchungshaft"}}`))
+
+	t.Run("set custody", func(t *testing.T) {
+		checkOK(t, post("/api/case/custody", `{"Case":1,"Haft":{"Festnahme":"2025-01-10","Anstalt":"JVA Dresden","Haftbefehl":"Untersuchungshaft"}}`))
+
+		respBody := checkBadRequest(t, post("/api/case/custody", `{"Case":1,"Haft":{"Festnahme":"10.01.2025","Haftbefehl":"Untersuchungshaft"}}`))
+		expected := "Error: invalid request:\nKey: 'Haft.Festnahme' Error:Field validation for 'Festnahme' failed on the 'datetime' tag\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("dashboard", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/api/deadline/custody?date=2025-08-01")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody := checkOK(t, res)
+		expected := `[{"ID":1,"Rubrum":"A","Az":"","Haft":{"Festnahme":"2025-01-10","Anstalt":"JVA Dresden","Haftbefehl":"Untersuchungshaft"},"Tage":204,"Haftpruefung":"2025-10-09","Verbleibend":69},` +
+			`{"ID":2,"Rubrum":"B","Az":"","Haft":{"Festnahme":"2025-05-02","Anstalt":"JVA Leipzig","Haftbefehl":"Untersuchungshaft"},"Tage":92,"Haftpruefung":"2025-11-01","Verbleibend":92}]`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("release", func(t *testing.T) {
+		checkOK(t, post("/api/case/custody", `{"Case":1,"Haft":{"Festnahme":"2025-01-10","Haftbefehl":"Untersuchungshaft","Entlassung":"2025-08-15"}}`))
+		res, err := http.Get(ts.URL + "/api/deadline/custody?date=2025-08-16")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody := checkOK(t, res)
+		if !strings.HasPrefix(string(respBody), `[{"ID":2,`) || strings.Contains(string(respBody), `"ID":1`) {
+			t.Fatalf("wrong custody cases: %q", string(respBody))
+		}
+	})
+}
+
+func TestEnforcementHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	post := func(path string, body string) *http.Response {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		return res
+	}
+
+	checkOK(t, post("/api/case/new", `{"Rubrum":"A","Beginn":"2024-10-01","Stand":"laufend","Art":"Verteidiger"}`))
+
+	t.Run("invalid sentence", func(t *testing.T) {
+		respBody := checkBadRequest(t, post("/api/deadline/enforcement", `{"CaseID":1,"Beginn":"10.01.2025"}`))
+		expected := "Error: invalid request: Monate or Tage is required\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+		checkBadRequest(t, post("/api/deadline/enforcement", `{"CaseID":2,"Monate":4,"Beginn":"10.01.2025"}`))
+	})
+
+	t.Run("key dates become deadlines", func(t *testing.T) {
+		respBody := checkOK(t, post("/api/deadline/enforcement", `{"CaseID":1,"Monate":4,"Beginn":"10.01.2025","Vorfrist":30}`))
+		var result struct {
+			ZweiDrittel string
+			Strafende   string
+			Fristen     []deadline.Entry
+		}
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		if result.ZweiDrittel != "2025-03-30" || result.Strafende != "2025-05-09" || len(result.Fristen) != 2 {
+			t.Fatalf("wrong result: %s", respBody)
+		}
+		expected := deadline.Deadline{CaseID: 1, Art: "Zwei-Drittel-Zeitpunkt", Bezeichnung: "Zwei-Drittel-Zeitpunkt (§ 57 Abs. 1 StGB)", Ereignis: "2025-01-10", Ende: "2025-03-30", Vorfrist: "2025-02-28"}
+		if result.Fristen[0].Deadline != expected {
+			t.Fatalf("wrong deadline: expected %+v, got %+v", expected, result.Fristen[0].Deadline)
+		}
+
+		res, err := http.Get(ts.URL + "/api/deadline/upcoming?date=2025-03-01")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody = checkOK(t, res)
+		if !strings.Contains(string(respBody), `"Art":"Zwei-Drittel-Zeitpunkt"`) || strings.Contains(string(respBody), `"Art":"Strafende"`) {
+			t.Fatalf("wrong upcoming deadlines: %s", respBody)
+		}
+	})
+}
+
+func TestRecordHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	post := func(path string, body string) *http.Response {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		return res
+	}
+
+	checkOK(t, post("/api/person/new", `{"Name":"Muster","Vorname":"Max"}`))
+	checkOK(t, post("/api/case/new", `{"Rubrum":"A","Beginn":"2019-10-01","Stand":"laufend","Art":"Verteidiger","Beteiligte":[{"PersonID":1,"Rolle":"Mandant"}]}`))
+	checkOK(t, post("/api/case/new", `{"Rubrum":"B","Beginn":"2024-10-01","Stand":"laufend","Art":"Verteidiger","Beteiligte":[{"PersonID":1,"Rolle":"Mandant"}]}`))
+	checkOK(t, post("/api/case/close", `{"ID":1,"Datum":"10.03.2020","Ergebnis":"Strafbefehl","Strafe":{"Tagessaetze":60,"Tagessatzhoehe":30}}`))
+
+	t.Run("cases of the client and manual input", func(t *testing.T) {
+		respBody := checkOK(t, post("/api/person/record?date=2025-03-10", `{"Person":1,"Verurteilungen":[{"Datum":"01.06.2023","Art":"Geldstrafe","Tagessaetze":120}]}`))
+		var result struct {
+			Eintraege        []struct{ CaseID int }
+			Tilgungsreife    string
+			Fuehrungszeugnis string
+		}
+		if err := json.Unmarshal(respBody, &result); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		if len(result.Eintraege) != 2 || result.Eintraege[0].CaseID != 1 || result.Tilgungsreife != "2033-06-01" || result.Fuehrungszeugnis != "2028-05-31" {
+			t.Fatalf("wrong result: %s", respBody)
+		}
+	})
+
+	t.Run("case without conviction", func(t *testing.T) {
+		respBody := checkBadRequest(t, post("/api/person/record", `{"Cases":[2]}`))
+		expected := "Error: invalid request: case 2 did not end with a conviction\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestLimitationHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	post := func(path string, body string) *http.Response {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		return res
+	}
+	get := func(path string) *http.Response {
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("issuing GET request to %q: %v", path, err)
+		}
+		return res
+	}
+
+	t.Run("warning for new cases", func(t *testing.T) {
+		respBody := checkOK(t, post("/api/case/new", `{"Rubrum":"A","Beginn":"2025-06-01","Stand":"laufend","Art":"Verteidiger","Tatende":"2019-03-15","Strafdrohung":{"Monate":60}}`))
+		expected := `{"id":1,"warnings":["Tatende: prosecution may be time-barred, limitation period ended on 2024-03-14"]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("interruption", func(t *testing.T) {
+		checkOK(t, post("/api/case/interruption", `{"Case":1,"Datum":"2023-01-10","Nr":1}`))
+
+		respBody := checkBadRequest(t, post("/api/case/interruption", `{"Case":1,"Datum":"2023-01-10","Nr":13}`))
+		expected := "Error: invalid request:\nKey: 'Unterbrechung.Nr' Error:Field validation for 'Nr' failed on the 'max' tag\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("limitation", func(t *testing.T) {
+		respBody := checkOK(t, get("/api/case/limitation?case=1&date=2025-06-01"))
+		expected := `{"Jahre":5,"Beginn":"2019-03-15","Ablauf":"2028-01-09","Absolut":"2029-03-14","Verjaehrt":false,"Begruendung":[` +
+			`"maximum penalty 5 years: period of 5 years (§ 78 Abs. 3 Nr. 4 StGB)",` +
+			`"offence completed on 2019-03-15: period begins (§ 78a StGB)",` +
+			`"period ends on 2024-03-14",` +
+			`"erste Vernehmung des Beschuldigten oder Bekanntgabe des Ermittlungsverfahrens (§ 78c Abs. 1 Satz 1 Nr. 1 StGB) on 2023-01-10: period begins anew and ends on 2028-01-09 (§ 78c Abs. 3 Satz 1 StGB)"]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		checkOK(t, post("/api/case/new", `{"Rubrum":"B","Beginn":"2025-06-01","Stand":"laufend","Art":"Verteidiger"}`))
+		respBody = checkBadRequest(t, get("/api/case/limitation?case=2"))
+		expected = "Error: invalid request: case 2: Tatende is missing\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("no warning on the last day of the period", func(t *testing.T) {
+		// The period of five years ends today.
+		tatende := time.Now().AddDate(-5, 0, 1).Format("2006-01-02")
+		respBody := checkOK(t, post("/api/case/new", `{"Rubrum":"C","Beginn":"2025-06-01","Stand":"laufend","Art":"Verteidiger","Tatende":"`+tatende+`","Strafdrohung":{"Monate":60}}`))
+		expected := `{"id":3}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestOffenceHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	t.Run("catalog", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/api/offence?q=urkunden")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody := checkOK(t, res)
+		expected := `[{"ID":"§ 267 Abs. 1 StGB","Gesetz":"StGB","Paragraph":"267","Absatz":"1","Bezeichnung":"Urkundenfälschung","Mindeststrafe":0,"Hoechststrafe":60,"Geldstrafe":true}]`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("offences charged in new cases", func(t *testing.T) {
+		res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(`{"Rubrum":"A","Beginn":"2025-06-01","Stand":"laufend","Art":"Verteidiger","Tatvorwuerfe":[{"Norm":"§ 263 Abs. 1 StGB"}]}`))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		checkOK(t, res)
+
+		res, err = http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(`{"Rubrum":"B","Beginn":"2025-06-01","Stand":"laufend","Art":"Verteidiger","Tatvorwuerfe":[{"Norm":"§ 263 StGB"}]}`))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		respBody := checkBadRequest(t, res)
+		expected := "Error: invalid request:\nKey: 'Case.Tatvorwuerfe[0].Norm' Error:Field validation for 'Norm' failed on the 'norm' tag\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		res, err = http.Get(ts.URL + "/api/case/search?q=betrug")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody = checkOK(t, res)
+		if !strings.Contains(string(respBody), `"ID":1`) {
+			t.Fatalf("case with offence charged not found: %q", string(respBody))
+		}
+	})
+}
+
+func TestCloseCaseHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	post := func(path string, body string) *http.Response {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		return res
+	}
+
+	checkOK(t, post("/api/case/new", `{"Rubrum":"A","Gericht":"AG Leipzig","Beginn":"2025-01-02","Stand":"laufend","Art":"Verteidiger","Tatvorwuerfe":[{"Norm":"§ 316 Abs. 1 StGB"}]}`))
+
+	t.Run("invalid sentence", func(t *testing.T) {
+		respBody := checkBadRequest(t, post("/api/case/close", `{"ID":1,"Datum":"02.05.2025","Ergebnis":"Verurteilung"}`))
+		expected := "Error: invalid request: Strafe: required for outcome Verurteilung\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("close and compare", func(t *testing.T) {
+		checkOK(t, post("/api/case/close", `{"ID":1,"Datum":"02.05.2025","Ergebnis":"Strafbefehl","Strafe":{"Tagessaetze":40,"Tagessatzhoehe":50,"Fahrverbot":2}}`))
+
+		res, err := http.Get(ts.URL + "/api/stats")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody := checkOK(t, res)
+		expected := `"PerErgebnis":{"Strafbefehl":1},"ErgebnisPerTatvorwurf":{"§ 316 Abs. 1 StGB":{"Strafbefehl":1}},"ErgebnisPerGericht":{"AG Leipzig":{"Strafbefehl":1}}`
+		if !strings.Contains(string(respBody), expected) {
+			t.Fatalf("wrong response body: expected %q in %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestFeesHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(`{"Rubrum":"A","Gericht":"AG Leipzig","Beginn":"2025-01-02","Stand":"laufend","Art":"Verteidiger","Hauptverhandlungstage":["2025-03-01"]}`))
+	if err != nil {
+		t.Fatalf("issuing POST request: %v", err)
+	}
+	checkOK(t, res)
+
+	get := func(query string) *http.Response {
+		res, err := http.Get(ts.URL + "/api/case/fees?" + query)
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		return res
+	}
+
+	t.Run("proposal", func(t *testing.T) {
+		respBody := checkOK(t, get("case=1&pflichtverteidiger=true"))
+		var p struct {
+			Positionen []struct{ Nr string }
+			Brutto     int
+		}
+		if err := json.Unmarshal(respBody, &p); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		if len(p.Positionen) != 5 || p.Positionen[2].Nr != "4108" || p.Brutto != 75446 {
+			t.Fatalf("wrong proposal: %s", respBody)
+		}
+	})
+
+	t.Run("invalid stage", func(t *testing.T) {
+		respBody := checkBadRequest(t, get("case=1&stufe=Wiederaufnahme"))
+		expected := "Error: invalid request: case 1: no fees for Stufe \"Wiederaufnahme\"\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestInvoiceHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(`{"Rubrum":"A","Gericht":"AG Leipzig","Beginn":"2025-01-02","Stand":"laufend","Art":"Verteidiger","Hauptverhandlungstage":["2025-03-01"]}`))
+	if err != nil {
+		t.Fatalf("issuing POST request: %v", err)
+	}
+	checkOK(t, res)
+
+	post := func(path, body string) *http.Response {
+		res, err := http.Post(ts.URL+"/api/invoice/"+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		return res
+	}
+	newInvoice := `{"CaseID":1,"Datum":"01.04.2025","Empfaenger":{"Name":"Max Müller"},"Pflichtverteidiger":true}`
+
+	t.Run("without sender", func(t *testing.T) {
+		respBody := checkBadRequest(t, post("new", newInvoice))
+		expected := "Error: invalid request: the invoice sender is not set\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	checkOK(t, post("sender/update", `{"Name":"Kanzlei Muster","Anschrift":"Hauptstraße 1\n04109 Leipzig","Steuernummer":"231/123/45678"}`))
+
+	t.Run("without recipient", func(t *testing.T) {
+		respBody := checkBadRequest(t, post("new", `{"CaseID":1}`))
+		if !strings.Contains(string(respBody), "Empfaenger.Name") {
+			t.Fatalf("wrong response body: got %q", string(respBody))
+		}
+	})
+
+	t.Run("new invoice with proposed fees", func(t *testing.T) {
+		var e invoice.Entry
+		if err := json.Unmarshal(checkOK(t, post("new", newInvoice)), &e); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		if e.ID != 1 || e.Nummer != "2025-0001" || e.Datum != "2025-04-01" || e.Brutto != 75446 {
+			t.Fatalf("wrong invoice: got %+v", e)
+		}
+		if len(e.Positionen) != 4 || e.Positionen[3].Art != "Auslage" || e.Absender.Name != "Kanzlei Muster" {
+			t.Fatalf("wrong positions or sender: got %+v", e)
+		}
+	})
+
+	t.Run("date before last invoice", func(t *testing.T) {
+		respBody := checkBadRequest(t, post("new", `{"CaseID":1,"Datum":"2025-03-31","Empfaenger":{"Name":"B"}}`))
+		expected := "Error: invalid request: Datum 2025-03-31 is before the date of invoice 2025-0001\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		var e invoice.Entry
+		if err := json.Unmarshal(checkOK(t, post("cancel", `{"ID":1,"Datum":"2025-04-02"}`)), &e); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		if e.ID != 2 || e.Nummer != "2025-0002" || e.Storno != 1 || e.Brutto != -75446 {
+			t.Fatalf("wrong cancellation invoice: got %+v", e)
+		}
+
+		respBody := checkBadRequest(t, post("cancel", `{"ID":1,"Datum":"2025-04-03"}`))
+		expected := "Error: invalid request: invoice 1 is already cancelled\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("retrieve of case", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/api/invoice/retrieve?case=1")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		var es []invoice.Entry
+		if err := json.Unmarshal(checkOK(t, res), &es); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		if len(es) != 2 || es[0].StorniertDurch != 2 {
+			t.Fatalf("wrong invoices: got %+v", es)
+		}
+	})
+
+	t.Run("pdf", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/api/invoice/pdf?id=2")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody := checkOK(t, res)
+		if ct := res.Header.Get("Content-Type"); ct != "application/pdf" {
+			t.Fatalf("wrong Content-Type: expected %q, got %q", "application/pdf", ct)
+		}
+		expected := `inline; filename="Rechnung-2025-0002.pdf"`
+		if cd := res.Header.Get("Content-Disposition"); cd != expected {
+			t.Fatalf("wrong Content-Disposition: expected %q, got %q", expected, cd)
+		}
+		if !bytes.HasPrefix(respBody, []byte("%PDF-1.4")) || !bytes.Contains(respBody, []byte("Rechnung Nr. 2025-0001")) {
+			t.Fatalf("wrong PDF")
+		}
+	})
+}
+
+func TestHolidayHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	for path, body := range map[string]string{
+		"/api/court/new": `{"Name":"Landgericht Leipzig","Ort":"Leipzig","Instanz":"LG","Bundesland":"SN"}`,
+		"/api/case/new":  `{"Rubrum":"A","Beginn":"2025-06-01","Stand":"laufend","Art":"Verteidiger","Gericht":"LG Leipzig 1 KLs 100 Js 1/25"}`,
+	} {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		checkOK(t, res)
+	}
+
+	t.Run("Bundesland of the court of the case", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/api/holiday?year=2025&case=1")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody := checkOK(t, res)
+		expected := `"Bundesland":"SN","Feiertage":[{"Datum":"2025-01-01","Name":"Neujahr"},`
+		if !strings.Contains(string(respBody), expected) || !strings.Contains(string(respBody), `{"Datum":"2025-11-19","Name":"Buß- und Bettag"}`) {
+			t.Fatalf("wrong response body: expected holidays of SN, got %q", string(respBody))
+		}
+	})
+
+	t.Run("unknown Bundesland", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/api/holiday?land=XX")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody := checkBadRequest(t, res)
+		expected := "Error: invalid request: unknown Bundesland \"XX\"\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestAppointmentHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	post := func(path string, body string) *http.Response {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		return res
+	}
+	get := func(path string) *http.Response {
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("issuing GET request to %q: %v", path, err)
+		}
+		return res
+	}
+
+	checkOK(t, post("/api/court/new", `{"Name":"Amtsgericht Leipzig","Ort":"Leipzig","Instanz":"AG","Bundesland":"SN"}`))
+	checkOK(t, post("/api/case/new", `{"Rubrum":"Müller","Az":"100 Js 1/25","Beginn":"2025-06-01","Stand":"laufend","Art":"Verteidiger"}`))
+
+	t.Run("new appointments", func(t *testing.T) {
+		respBody := checkOK(t, post("/api/appointment/new", `{"CaseID":1,"Art":"Hauptverhandlung","Beginn":"2025-11-04T09:00","GerichtID":1}`))
+		if expected := `{"id":1}`; string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		respBody = checkBadRequest(t, post("/api/appointment/new", `{"CaseID":2,"Art":"Haftbesuch","Beginn":"2025-11-04T09:00"}`))
+		expected := "Error: invalid request: case 2 does not exist\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		respBody = checkBadRequest(t, post("/api/appointment/new", `{"CaseID":1,"Art":"Haftbesuch","Beginn":"2025-11-04T09:00","Ende":"2025-11-04T08:00"}`))
+		expected = "Error: invalid request: Ende must be after Beginn\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("update and cancel", func(t *testing.T) {
+		checkOK(t, post("/api/appointment/update", `{"ID":1,"Fields":{"CaseID":1,"Art":"Hauptverhandlung","Beginn":"2025-11-05T09:00","GerichtID":1}}`))
+		checkOK(t, post("/api/appointment/cancel", `{"ID":1}`))
+
+		respBody := checkBadRequest(t, post("/api/appointment/cancel", `{"ID":1}`))
+		expected := "Error: invalid request: appointment 1 is already cancelled\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("calendar feed", func(t *testing.T) {
+		respBody := checkOK(t, post("/api/appointment/feed", `{"Name":"Kanzlei"}`))
+		var feed struct {
+			URL string `json:"URL"`
+		}
+		if err := json.Unmarshal(respBody, &feed); err != nil {
+			t.Fatalf("decoding response body: %v", err)
+		}
+
+		res := get(feed.URL)
+		respBody = checkOK(t, res)
+		if ct := res.Header.Get("Content-Type"); ct != "text/calendar; charset=utf-8" {
+			t.Fatalf("wrong content type: expected %q, got %q", "text/calendar; charset=utf-8", ct)
+		}
+		for _, expected := range []string{
+			"SUMMARY:Hauptverhandlung: Müller (100 Js 1/25)\r\n",
+			"LOCATION:Amtsgericht Leipzig\r\n",
+			"DTSTART:20251105T080000Z\r\n",
+			"SEQUENCE:2\r\n",
+			"STATUS:CANCELLED\r\n",
+		} {
+			if !strings.Contains(string(respBody), expected) {
+				t.Fatalf("wrong calendar: expected %q in %q", expected, string(respBody))
+			}
+		}
+
+		statusCheck(t, get("/api/appointment/ical/unknown.ics"), http.StatusNotFound)
+	})
+}
+
+func TestImportAppointmentsHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	for _, body := range []string{
+		`{"Rubrum":"Müller","Az":"100 Js 1/25","Beginn":"2025-06-01","Stand":"laufend","Art":"Verteidiger"}`,
+		`{"Rubrum":"Schulze","Az":"200 Js 2/25","Beginn":"2025-06-01","Stand":"laufend","Art":"Verteidiger"}`,
+		`{"Rubrum":"Schmidt","Az":"200 Js 2/25","Beginn":"2025-06-01","Stand":"laufend","Art":"Nebenkläger"}`,
+	} {
+		res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		checkOK(t, res)
+	}
+
+	path := "/api/appointment/import"
+	data := "BEGIN:VCALENDAR\r\n" +
+		"BEGIN:VEVENT\r\nUID:1@example\r\nDTSTART:20251104T080000Z\r\nSUMMARY:Hauptverhandlung 100 Js 1/25\r\nEND:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\nUID:2@example\r\nDTSTART:20251105T080000Z\r\nSUMMARY:Akteneinsicht\r\nDESCRIPTION:Az. 200 Js 2/25\r\nEND:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	t.Run("wrong content type", func(t *testing.T) {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(data))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		respBody := checkBadRequest(t, res)
+		expected := "Error: Content-Type must be text/calendar\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		res, err := http.Post(ts.URL+path, "text/calendar", strings.NewReader(data))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		respBody := checkOK(t, res)
+		for _, expected := range []string{
+			`"Assigned":[{"ID":1,"Appointment":{"CaseID":1,"Art":"Hauptverhandlung","Titel":"Hauptverhandlung 100 Js 1/25","Beginn":"2025-11-04T09:00",`,
+			`"Unassigned":[{"ID":2,"Appointment":{"CaseID":0,"Art":"Akteneinsicht",`,
+			`"Candidates":[2,3]}],"Updated":[],"Skipped":[]}`,
+		} {
+			if !strings.Contains(string(respBody), expected) {
+				t.Fatalf("wrong response body: expected %q in %q", expected, string(respBody))
+			}
+		}
+	})
+
+	t.Run("import again skips known events", func(t *testing.T) {
+		res, err := http.Post(ts.URL+path, "text/calendar", strings.NewReader(data))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		respBody := checkOK(t, res)
+		expected := `{"Assigned":[],"Unassigned":[],"Updated":[],"Skipped":["1@example","2@example"]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("import changed events", func(t *testing.T) {
+		rescheduled := "BEGIN:VCALENDAR\r\n" +
+			"BEGIN:VEVENT\r\nUID:1@example\r\nSEQUENCE:1\r\nDTSTART:20251111T090000Z\r\nSUMMARY:Hauptverhandlung 100 Js 1/25\r\nEND:VEVENT\r\n" +
+			"END:VCALENDAR\r\n"
+		res, err := http.Post(ts.URL+path, "text/calendar", strings.NewReader(rescheduled))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		respBody := checkOK(t, res)
+		expected := `"Updated":[{"ID":1,"Appointment":{"CaseID":1,"Art":"Hauptverhandlung","Titel":"Hauptverhandlung 100 Js 1/25","Beginn":"2025-11-11T10:00","Ende":"","Ort":"","Notiz":"","UID":"1@example","Sequence":1,`
+		if !strings.Contains(string(respBody), expected) {
+			t.Fatalf("wrong response body: expected %q in %q", expected, string(respBody))
+		}
+
+		cancelled := strings.Replace(strings.Replace(rescheduled, "SEQUENCE:1", "SEQUENCE:3", 1), "END:VEVENT", "STATUS:CANCELLED\r\nEND:VEVENT", 1)
+		res, err = http.Post(ts.URL+path, "text/calendar", strings.NewReader(cancelled))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		respBody = checkOK(t, res)
+		for _, expected := range []string{`"Beginn":"2025-11-11T10:00"`, `"Sequence":2,`, `"Abgesagt":true,"ImportSequence":3}}]`} {
+			if !strings.Contains(string(respBody), expected) {
+				t.Fatalf("wrong response body: expected %q in %q", expected, string(respBody))
+			}
+		}
+
+		res, err = http.Post(ts.URL+path, "text/calendar", strings.NewReader(rescheduled))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		respBody = checkOK(t, res)
+		expected = `{"Assigned":[],"Unassigned":[],"Updated":[],"Skipped":["1@example"]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("assign", func(t *testing.T) {
+		res, err := http.Post(ts.URL+"/api/appointment/assign", "application/json", strings.NewReader(`{"ID":2,"CaseID":3}`))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		checkOK(t, res)
+
+		res, err = http.Get(ts.URL + "/api/appointment/unassigned")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody := checkOK(t, res)
+		if expected := "[]"; string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("import next version of assigned event", func(t *testing.T) {
+		rescheduled := "BEGIN:VCALENDAR\r\n" +
+			"BEGIN:VEVENT\r\nUID:2@example\r\nSEQUENCE:1\r\nDTSTART:20251112T080000Z\r\nSUMMARY:Akteneinsicht\r\nDESCRIPTION:Az. 200 Js 2/25\r\nEND:VEVENT\r\n" +
+			"END:VCALENDAR\r\n"
+		res, err := http.Post(ts.URL+path, "text/calendar", strings.NewReader(rescheduled))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		respBody := checkOK(t, res)
+		expected := `"Updated":[{"ID":2,"Appointment":{"CaseID":3,"Art":"Akteneinsicht","Titel":"Akteneinsicht","Beginn":"2025-11-12T09:00",`
+		if !strings.Contains(string(respBody), expected) {
+			t.Fatalf("wrong response body: expected %q in %q", expected, string(respBody))
+		}
+		for _, expected := range []string{`"Sequence":2,`, `"ImportSequence":1}`} {
+			if !strings.Contains(string(respBody), expected) {
+				t.Fatalf("wrong response body: expected %q in %q", expected, string(respBody))
+			}
+		}
+	})
+}
+
+func TestAppointmentConflicts(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	post := func(path string, body string) *http.Response {
+		res, err := http.Post(ts.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("issuing POST request to %q: %v", path, err)
+		}
+		return res
+	}
+
+	checkOK(t, post("/api/court/new", `{"Name":"Amtsgericht Leipzig","Ort":"Leipzig","Instanz":"AG","Bundesland":"SN"}`))
+	checkOK(t, post("/api/court/new", `{"Name":"Amtsgericht Chemnitz","Ort":"Chemnitz","Instanz":"AG","Bundesland":"SN"}`))
+	checkOK(t, post("/api/case/new", `{"Rubrum":"A","Beginn":"2025-06-01","Stand":"laufend","Art":"Verteidiger"}`))
+
+	t.Run("buffer per court pair", func(t *testing.T) {
+		checkOK(t, post("/api/appointment/buffer", `{"GerichtA":1,"GerichtB":2,"Minuten":120}`))
+
+		respBody := checkBadRequest(t, post("/api/appointment/buffer", `{"GerichtA":1,"GerichtB":3,"Minuten":60}`))
+		expected := "Error: invalid request: court 3 does not exist\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("warnings", func(t *testing.T) {
+		checkOK(t, post("/api/appointment/new", `{"CaseID":1,"Art":"Hauptverhandlung","Beginn":"2025-11-04T09:00","GerichtID":1}`))
+
+		respBody := checkOK(t, post("/api/appointment/new", `{"CaseID":1,"Art":"Hauptverhandlung","Beginn":"2025-11-04T11:00","GerichtID":2}`))
+		expected := `{"id":2,"warnings":["Beginn: only 60 minutes between this appointment and appointment 1 at another court, 120 minutes needed"]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		respBody = checkOK(t, post("/api/appointment/update", `{"ID":2,"Fields":{"CaseID":1,"Art":"Hauptverhandlung","Beginn":"2025-11-04T09:30","GerichtID":2}}`))
+		expected = `{"id":2,"warnings":["Beginn: overlaps with appointment 1"]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+
+	t.Run("list of conflicts", func(t *testing.T) {
+		res, err := http.Get(ts.URL + "/api/appointment/conflicts?date=2025-11-01")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		respBody := checkOK(t, res)
+		expected := `[{"First":1,"Second":2,"Abstand":-30,"Puffer":120}]`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		checkOK(t, post("/api/appointment/cancel", `{"ID":2}`))
+		res, err = http.Get(ts.URL + "/api/appointment/conflicts?date=2025-11-01")
+		if err != nil {
+			t.Fatalf("issuing GET request: %v", err)
+		}
+		if respBody := checkOK(t, res); string(respBody) != "[]" {
+			t.Fatalf("wrong response body: expected %q, got %q", "[]", string(respBody))
+		}
+	})
+
+	t.Run("warnings for imported and assigned appointments", func(t *testing.T) {
+		data := "BEGIN:VCALENDAR\r\n" +
+			"BEGIN:VEVENT\r\nUID:3@example\r\nDTSTART:20251104T083000Z\r\nSUMMARY:Akteneinsicht\r\nEND:VEVENT\r\n" +
+			"END:VCALENDAR\r\n"
+		res, err := http.Post(ts.URL+"/api/appointment/import", "text/calendar", strings.NewReader(data))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		respBody := checkOK(t, res)
+		expected := `"Warnings":["Beginn: overlaps with appointment 1"]}]`
+		if !strings.Contains(string(respBody), expected) {
+			t.Fatalf("wrong response body: expected %q in %q", expected, string(respBody))
+		}
+
+		respBody = checkOK(t, post("/api/appointment/assign", `{"ID":3,"CaseID":1}`))
+		expected = `{"id":3,"warnings":["Beginn: overlaps with appointment 1"]}`
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
+}
+
+func TestExportHandler(t *testing.T) {
+	logger := log.Default()
+	ts, _, cleanup := testutils.CreateServer(t, logger)
+	defer cleanup()
+
+	for _, c := range []string{
+		`{"Rubrum":"A","Beginn":"2019-01-02","Stand":"abgeschlossen","Art":"Verteidiger"}`,
+		`{"Rubrum":"B","Beginn":"2021-06-01","Stand":"laufend","Art":"Nebenkläger"}`,
+	} {
+		res, err := http.Post(ts.URL+"/api/case/new", "application/json", strings.NewReader(c))
+		if err != nil {
+			t.Fatalf("issuing POST request: %v", err)
+		}
+		checkOK(t, res)
+	}
+
+	t.Run("CSV with filter", func(t *testing.T) {
+		path := "/api/export/cases.csv?art=Verteidiger&from=01.01.2019"
+
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("issuing GET request to %q: %v", path, err)
+		}
+
+		respBody := checkOK(t, res)
+
+		expected := "\xef\xbb\xbf" +
+			"Nr.;Rubrum;Aktenzeichen;Gericht;Beginn;Ende;Gegenstand;Art der Tätigkeit;Beschreibung;Stand\r\n" +
+			"1;A;;;02.01.2019;;;Verteidiger;;abgeschlossen\r\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+
+		expectedCTHeader := "text/csv; charset=utf-8"
+		gotCTHeader := res.Header.Get("Content-Type")
+		if expectedCTHeader != gotCTHeader {
+			t.Fatalf("wrong response Content-Type header: expected %q, got %q", expectedCTHeader, gotCTHeader)
+		}
+	})
+
+	t.Run("XLSX", func(t *testing.T) {
+		path := "/api/export/cases.xlsx"
+
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("issuing GET request to %q: %v", path, err)
+		}
+
+		respBody := checkOK(t, res)
+
+		if !bytes.HasPrefix(respBody, []byte("PK")) {
+			t.Fatalf("response body is not a zip archive: %q", respBody)
+		}
+
+		expectedCDHeader := `attachment; filename="faelle.xlsx"`
+		gotCDHeader := res.Header.Get("Content-Disposition")
+		if expectedCDHeader != gotCDHeader {
+			t.Fatalf("wrong response Content-Disposition header: expected %q, got %q", expectedCDHeader, gotCDHeader)
+		}
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		path := "/api/export/cases.csv?to=morgen"
+
+		res, err := http.Get(ts.URL + path)
+		if err != nil {
+			t.Fatalf("issuing GET request to %q: %v", path, err)
+		}
+
+		respBody := checkBadRequest(t, res)
+
+		expected := "Error: invalid request: query parameter to: invalid date \"morgen\"\n"
+		if string(respBody) != expected {
+			t.Fatalf("wrong response body: expected %q, got %q", expected, string(respBody))
+		}
+	})
 }
 
 // Some helpers for HTTP requests.
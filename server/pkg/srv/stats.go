@@ -0,0 +1,64 @@
+package srv
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/stats"
+)
+
+// FAOYears is the period before the application in which the cases for the
+// FAO have to be handled (§ 5 Abs. 1 FAO).
+const FAOYears = 3
+
+type StatsHandler struct {
+	Logger Logger
+	Model  *model.Model
+}
+
+func NewStatsHandler(logger Logger, m *model.Model) *StatsHandler {
+	return &StatsHandler{
+		Logger: logger,
+		Model:  m,
+	}
+}
+
+// ServeHTTP returns the statistics of all cases selected by the filter
+// parameters (see parseCaseFilter). The query parameter fao sets the date
+// range to the FAO window, i. e. the three years before the given date of
+// application.
+func (h StatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	methodAllowed(
+		http.MethodGet,
+		func(w http.ResponseWriter, r *http.Request) {
+			f, err := parseFAOFilter(r)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("Error: invalid request: %v", err), http.StatusBadRequest)
+				return
+			}
+
+			writeJSON(w, h.Logger, http.StatusOK, stats.Compute(h.Model.Case, h.Model.Court, f))
+		},
+	)(w, r)
+}
+
+// parseFAOFilter reads the case filter (see parseCaseFilter). The query
+// parameter fao sets the date range to the FAO window, i. e. the three years
+// before the given date of application.
+func parseFAOFilter(r *http.Request) (lawcase.Filter, error) {
+	f, err := parseCaseFilter(r)
+	if err != nil {
+		return lawcase.Filter{}, err
+	}
+	if v := r.URL.Query().Get("fao"); v != "" {
+		t, err := lawcase.ParseDate(v)
+		if err != nil {
+			return lawcase.Filter{}, fmt.Errorf("query parameter fao: %w", err)
+		}
+		f.From = t.AddDate(-FAOYears, 0, 0)
+		f.To = t
+	}
+	return f, nil
+}
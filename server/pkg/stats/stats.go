@@ -0,0 +1,211 @@
+/*
+Package stats computes aggregated numbers about the cases of the practice.
+*/
+package stats
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/court"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+)
+
+// hearingIntensiveCourts contains abbreviations and words (or parts of words)
+// that mark a court or formation at the Schöffengericht or above. Hearings
+// before such courts count for the FAO (§ 5 Abs. 1 lit. f FAO), so we call
+// these cases hearing-intensive.
+var (
+	hearingIntensiveAbbr  = []string{"lg", "olg", "bgh", "kg"}
+	hearingIntensiveWords = []string{
+		"schöffengericht",
+		"landgericht",
+		"strafkammer",
+		"jugendkammer",
+		"schwurgericht",
+		"strafsenat",
+		"kammergericht",
+		"oberlandesgericht",
+		"bundesgerichtshof",
+	}
+)
+
+// Stats contains all aggregates. A case with several roles is counted in
+// PerArt for each of them, a case with several offences charged in
+// PerTatvorwurf for each of them. PerTatvorwurf is keyed by the ID of the
+// offence in the catalog of package offence.
+type Stats struct {
+	Total         int            `json:"Total"`
+	PerYear       map[string]int `json:"PerYear"`
+	PerArt        map[string]int `json:"PerArt"`
+	PerGericht    map[string]int `json:"PerGericht"`
+	PerStand      map[string]int `json:"PerStand"`
+	PerTatvorwurf map[string]int `json:"PerTatvorwurf"`
+
+	// PerErgebnis counts the closed cases by outcome (see
+	// lawcase.Ergebnisse). ErgebnisPerTatvorwurf and ErgebnisPerGericht
+	// count the outcomes per offence charged and per court.
+	PerErgebnis           map[string]int            `json:"PerErgebnis"`
+	ErgebnisPerTatvorwurf map[string]map[string]int `json:"ErgebnisPerTatvorwurf"`
+	ErgebnisPerGericht    map[string]map[string]int `json:"ErgebnisPerGericht"`
+
+	// AverageDurationDays is the average number of days from Beginn to Ende
+	// of all cases with valid dates in both fields. DurationCases is the
+	// number of these cases.
+	AverageDurationDays float64 `json:"AverageDurationDays"`
+	DurationCases       int     `json:"DurationCases"`
+
+	// HearingIntensive is the number of cases before the Schöffengericht or
+	// a higher court, HearingIntensiveShare their share of all cases.
+	HearingIntensive      int     `json:"HearingIntensive"`
+	HearingIntensiveShare float64 `json:"HearingIntensiveShare"`
+}
+
+// Compute returns the aggregates of all cases selected by the filter. Cases
+// that reference a court of the court directory are counted under the name of
+// that court.
+func Compute(cs lawcase.Model, courts court.Model, f lawcase.Filter) Stats {
+	s := Stats{
+		PerYear:       map[string]int{},
+		PerArt:        map[string]int{},
+		PerGericht:    map[string]int{},
+		PerStand:      map[string]int{},
+		PerTatvorwurf: map[string]int{},
+
+		PerErgebnis:           map[string]int{},
+		ErgebnisPerTatvorwurf: map[string]map[string]int{},
+		ErgebnisPerGericht:    map[string]map[string]int{},
+	}
+
+	var durationDays float64
+	for _, id := range cs.IDs(f) {
+		c := cs[id]
+		s.Total++
+
+		beginn, errBeginn := lawcase.ParseDate(c.Beginn)
+		if errBeginn == nil {
+			s.PerYear[strconv.Itoa(beginn.Year())]++
+		} else {
+			s.PerYear["unbekannt"]++
+		}
+		if roles := c.Roles(); len(roles) > 0 {
+			for _, r := range roles {
+				s.PerArt[r]++
+			}
+		} else {
+			s.PerArt[orUnknown("")]++
+		}
+		gericht := courtOfCase(c, courts)
+		s.PerGericht[gericht]++
+		s.PerStand[orUnknown(StandGroup(c.Stand))]++
+		counted := map[string]bool{}
+		for _, t := range c.Tatvorwuerfe {
+			if counted[t.Norm] {
+				continue
+			}
+			counted[t.Norm] = true
+			s.PerTatvorwurf[t.Norm]++
+			if c.Abschluss != nil {
+				increment(s.ErgebnisPerTatvorwurf, t.Norm, c.Abschluss.Ergebnis)
+			}
+		}
+		if c.Abschluss != nil {
+			s.PerErgebnis[c.Abschluss.Ergebnis]++
+			increment(s.ErgebnisPerGericht, gericht, c.Abschluss.Ergebnis)
+		}
+
+		if ende, err := lawcase.ParseDate(c.Ende); err == nil && errBeginn == nil && !ende.Before(beginn) {
+			durationDays += ende.Sub(beginn).Hours() / 24
+			s.DurationCases++
+		}
+
+		if IsHearingIntensive(c) {
+			s.HearingIntensive++
+		}
+	}
+
+	if s.DurationCases > 0 {
+		s.AverageDurationDays = round(durationDays / float64(s.DurationCases))
+	}
+	if s.Total > 0 {
+		s.HearingIntensiveShare = round(float64(s.HearingIntensive) / float64(s.Total))
+	}
+	return s
+}
+
+// courtOfCase returns the name of the court of the case in the court
+// directory or the name from the free-text field Gericht.
+func courtOfCase(c lawcase.Case, courts court.Model) string {
+	if g, ok := courts[c.GerichtID]; ok {
+		return g.Name
+	}
+	return orUnknown(CourtName(c.Gericht))
+}
+
+func increment(m map[string]map[string]int, key, outcome string) {
+	if m[key] == nil {
+		m[key] = map[string]int{}
+	}
+	m[key][outcome]++
+}
+
+// CourtName returns the name of the court from the free-text field Gericht
+// which usually contains the court and the case number like "AG Leipzig 123 Cs
+// 456 Js 7890/22" or "AG Leipzig, Schöffengericht". It is the text up to the
+// first digit, comma or semicolon.
+func CourtName(gericht string) string {
+	end := strings.IndexFunc(gericht, func(r rune) bool {
+		return unicode.IsDigit(r) || r == ',' || r == ';'
+	})
+	if end >= 0 {
+		gericht = gericht[:end]
+	}
+	return strings.Trim(gericht, " ,:-")
+}
+
+// StandGroup returns the first word of the free-text field Stand in lower
+// case, so that "abgeschlossen, rechtskräftig seit ..." and "Abgeschlossen"
+// are in the same group.
+func StandGroup(stand string) string {
+	fields := strings.FieldsFunc(stand, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ',' || r == ';'
+	})
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
+
+// IsHearingIntensive reports whether the case is before the Schöffengericht
+// or a higher court according to the field Gericht.
+func IsHearingIntensive(c lawcase.Case) bool {
+	words := strings.FieldsFunc(strings.ToLower(c.Gericht), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, w := range words {
+		for _, a := range hearingIntensiveAbbr {
+			if w == a {
+				return true
+			}
+		}
+		for _, hw := range hearingIntensiveWords {
+			if strings.Contains(w, hw) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unbekannt"
+	}
+	return s
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
@@ -0,0 +1,118 @@
+package stats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/court"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model/lawcase"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/stats"
+)
+
+var testCases = lawcase.Model{
+	1: {Beginn: "2019-01-01", Ende: "2019-01-11", Art: "Verteidiger", Gericht: "AG Leipzig 123 Cs 456 Js 7890/19", Stand: "abgeschlossen"},
+	2: {Beginn: "2019-06-01", Ende: "2019-06-21", Art: "Verteidiger", Gericht: "AG Leipzig, Schöffengericht", Stand: "Abgeschlossen, rechtskräftig"},
+	3: {Beginn: "2021-03-01", Ende: "noch anhängig", Art: "Nebenkläger", Gericht: "LG Dresden; BGH", Stand: "laufend"},
+	4: {Beginn: "unklar", Art: "Zeugenbeistand", Stand: ""},
+}
+
+func TestCompute(t *testing.T) {
+	s := stats.Compute(testCases, court.Model{}, lawcase.Filter{})
+
+	if s.Total != 4 {
+		t.Fatalf("wrong total: expected 4, got %d", s.Total)
+	}
+	if s.PerYear["2019"] != 2 || s.PerYear["2021"] != 1 || s.PerYear["unbekannt"] != 1 {
+		t.Fatalf("wrong cases per year: %v", s.PerYear)
+	}
+	if s.PerArt["Verteidiger"] != 2 || s.PerArt["Nebenkläger"] != 1 || s.PerArt["Zeugenbeistand"] != 1 {
+		t.Fatalf("wrong cases per Art: %v", s.PerArt)
+	}
+	if s.PerGericht["AG Leipzig"] != 2 || s.PerGericht["LG Dresden"] != 1 || s.PerGericht["unbekannt"] != 1 {
+		t.Fatalf("wrong cases per Gericht: %v", s.PerGericht)
+	}
+	if s.PerStand["abgeschlossen"] != 2 || s.PerStand["laufend"] != 1 {
+		t.Fatalf("wrong cases per Stand: %v", s.PerStand)
+	}
+	if s.AverageDurationDays != 15 || s.DurationCases != 2 {
+		t.Fatalf("wrong average duration: expected 15 days of 2 cases, got %v days of %d cases", s.AverageDurationDays, s.DurationCases)
+	}
+	if s.HearingIntensive != 2 || s.HearingIntensiveShare != 0.5 {
+		t.Fatalf("wrong hearing-intensive cases: expected 2 (0.5), got %d (%v)", s.HearingIntensive, s.HearingIntensiveShare)
+	}
+
+	t.Run("with filter", func(t *testing.T) {
+		from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		s := stats.Compute(testCases, court.Model{}, lawcase.Filter{From: from})
+		if s.Total != 1 || s.PerYear["2021"] != 1 {
+			t.Fatalf("wrong result with filter: %+v", s)
+		}
+	})
+}
+
+func TestComputeWithCourts(t *testing.T) {
+	courts := court.Model{
+		1: {Name: "Amtsgericht Leipzig", Ort: "Leipzig", Instanz: "AG"},
+	}
+	cs := lawcase.Model{
+		1: {Gericht: "AG Leipzig", GerichtID: 1},
+		2: {Gericht: "AG L.", GerichtID: 1},
+		3: {Gericht: "AG Dresden"},
+	}
+
+	s := stats.Compute(cs, courts, lawcase.Filter{})
+	if s.PerGericht["Amtsgericht Leipzig"] != 2 || s.PerGericht["AG Dresden"] != 1 {
+		t.Fatalf("wrong cases per Gericht: %v", s.PerGericht)
+	}
+}
+
+func TestIsHearingIntensive(t *testing.T) {
+	for gericht, expected := range map[string]bool{
+		"AG Leipzig":                  false,
+		"AG Leipzig, Strafrichter":    false,
+		"Erfolg beim AG":              false,
+		"AG Leipzig, Schöffengericht": true,
+		"Jugendschöffengericht Halle": true,
+		"LG Leipzig":                  true,
+		"OLG Dresden 1 Ws 12/22":      true,
+		"Oberlandesgericht Dresden":   true,
+	} {
+		got := stats.IsHearingIntensive(lawcase.Case{Gericht: gericht})
+		if got != expected {
+			t.Fatalf("wrong result for %q: expected %v, got %v", gericht, expected, got)
+		}
+	}
+}
+
+func TestComputePerTatvorwurf(t *testing.T) {
+	cs := lawcase.Model{
+		1: {Tatvorwuerfe: []lawcase.Tatvorwurf{{Norm: "§ 263 Abs. 1 StGB"}, {Norm: "§ 263 Abs. 1 StGB", Versuch: true}, {Norm: "§ 267 Abs. 1 StGB"}}},
+		2: {Tatvorwuerfe: []lawcase.Tatvorwurf{{Norm: "§ 263 Abs. 1 StGB"}}},
+		3: {},
+	}
+	s := stats.Compute(cs, court.Model{}, lawcase.Filter{})
+	if len(s.PerTatvorwurf) != 2 || s.PerTatvorwurf["§ 263 Abs. 1 StGB"] != 2 || s.PerTatvorwurf["§ 267 Abs. 1 StGB"] != 1 {
+		t.Fatalf("wrong cases per Tatvorwurf: %v", s.PerTatvorwurf)
+	}
+}
+
+func TestComputeOutcomes(t *testing.T) {
+	betrug := []lawcase.Tatvorwurf{{Norm: "§ 263 Abs. 1 StGB"}}
+	cs := lawcase.Model{
+		1: {Gericht: "AG Leipzig", Tatvorwuerfe: betrug, Abschluss: &lawcase.Abschluss{Ergebnis: "Verurteilung"}},
+		2: {Gericht: "AG Leipzig", Tatvorwuerfe: betrug, Abschluss: &lawcase.Abschluss{Ergebnis: "Einstellung153a"}},
+		3: {Gericht: "AG Dresden", Tatvorwuerfe: betrug, Abschluss: &lawcase.Abschluss{Ergebnis: "Verurteilung"}},
+		4: {Gericht: "AG Dresden", Tatvorwuerfe: betrug},
+	}
+	s := stats.Compute(cs, court.Model{}, lawcase.Filter{})
+
+	if s.PerErgebnis["Verurteilung"] != 2 || s.PerErgebnis["Einstellung153a"] != 1 {
+		t.Fatalf("wrong cases per Ergebnis: %v", s.PerErgebnis)
+	}
+	if got := s.ErgebnisPerTatvorwurf["§ 263 Abs. 1 StGB"]; got["Verurteilung"] != 2 || got["Einstellung153a"] != 1 {
+		t.Fatalf("wrong outcomes per Tatvorwurf: %v", s.ErgebnisPerTatvorwurf)
+	}
+	if got := s.ErgebnisPerGericht; got["AG Leipzig"]["Verurteilung"] != 1 || got["AG Leipzig"]["Einstellung153a"] != 1 || got["AG Dresden"]["Verurteilung"] != 1 {
+		t.Fatalf("wrong outcomes per Gericht: %v", s.ErgebnisPerGericht)
+	}
+}
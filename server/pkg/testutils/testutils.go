@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/normanjaeckel/fao-strafrecht/server/pkg/eventstore"
+	"github.com/normanjaeckel/fao-strafrecht/server/pkg/fao"
 	"github.com/normanjaeckel/fao-strafrecht/server/pkg/model"
 	"github.com/normanjaeckel/fao-strafrecht/server/pkg/srv"
 )
@@ -52,7 +53,7 @@ func CreateServer(t testing.TB, logger eventstore.Logger) (*httptest.Server, str
 		t.Fatalf("loading model: %v", err)
 	}
 
-	ts := httptest.NewServer(srv.Handler(logger, model))
+	ts := httptest.NewServer(srv.Handler(logger, model, fao.Options{MergedAsOne: true}))
 
 	cleanupFn := func() {
 		defer ts.Close()